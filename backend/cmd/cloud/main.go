@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
@@ -19,8 +21,10 @@ import (
 	apicommon "http-mqtt-boilerplate/backend/internal/shared/api"
 	"http-mqtt-boilerplate/backend/internal/shared/helpers"
 	"http-mqtt-boilerplate/backend/pkg/generate"
+	"http-mqtt-boilerplate/backend/pkg/lifecycle"
 	"http-mqtt-boilerplate/backend/pkg/router"
 	"http-mqtt-boilerplate/backend/pkg/utils"
+	"http-mqtt-boilerplate/docs"
 	"http-mqtt-boilerplate/web"
 )
 
@@ -68,7 +72,10 @@ func main() {
 	}
 
 	// Builders
-	rb, err := router.NewRouteBuilder(logger, collector)
+	rb, err := router.NewRouteBuilder(logger, collector,
+		router.WithStrictSummaries(router.DefaultSummaryMaxLength),
+		router.WithBodyLimitResponse(apicommon.MaxBodySize, apicommon.BodyLimitResponse),
+	)
 	fatalIfErr(logger, err)
 
 	// Create services
@@ -88,18 +95,26 @@ func main() {
 
 	// HTTP Server
 	httpAddr := fmt.Sprintf(":%d", config.Port)
-	httpServer := apicommon.NewHTTPServer(logger, httpAddr, rb.Router())
+	httpServer := apicommon.NewHTTPServer(logger, httpAddr, rb.Router(), apicommon.HTTPServerTimeouts{
+		ReadHeader: config.ReadHeaderTimeout,
+		Read:       config.ReadTimeout,
+		Write:      config.WriteTimeout,
+		Idle:       config.IdleTimeout,
+		Shutdown:   config.ShutdownTimeout,
+	})
 	httpServer.StartOnBackground(sigCancel)
 
+	lc := lifecycle.New(logger)
+	lc.Register("http server", 0, config.ShutdownTimeout, httpServer.Shutdown)
+
 	// Wait for signal (either OS or some failure)
 	<-sigCtx.Done()
 	logger.Info("received signal, shutting down...")
 
-	// Shutdown HTTP server
-	logger.Info("http server shutting down...")
+	if err := lc.Shutdown(context.Background()); err != nil {
+		logger.Error("server exited with shutdown errors", utils.ErrAttr(err))
 
-	if err := httpServer.ShutdownWithDefaultTimeout(); err != nil {
-		logger.Error("http server shutdown failed", utils.ErrAttr(err))
+		return
 	}
 
 	logger.Info("server exited gracefully")
@@ -121,6 +136,8 @@ func registerHTTPHandlers(l *slog.Logger, rb *router.RouteBuilder, h *cloudapi.H
 		rb.Use(mw.LoggerMiddleware)
 
 		h.RegisterPing("/ping", rb)
+		h.RegisterLiveness("/livez", rb)
+		h.RegisterReadiness("/readyz", rb)
 		h.RegisterHealth("/health", rb)
 	})
 
@@ -128,10 +145,28 @@ func registerHTTPHandlers(l *slog.Logger, rb *router.RouteBuilder, h *cloudapi.H
 	fatalIfErr(l, err)
 	webapp.Register(rb.Router(), l)
 
+	// Serve the OpenAPI spec generated for this build, generate.sh embeds it
+	spec, err := docs.OpenAPISpec("cloud")
+	if errors.Is(err, fs.ErrNotExist) {
+		l.Warn("OpenAPI spec not embedded, run generate.sh to serve it", utils.ErrAttr(err))
+	} else {
+		fatalIfErr(l, err)
+
+		specHandler, err := apicommon.NewSpecHandler(spec)
+		fatalIfErr(l, err)
+
+		rb.Router().Get("/openapi.yaml", specHandler.ServeYAML)
+		rb.Router().Get("/openapi.json", specHandler.ServeJSON)
+	}
+
 	rb.Router().HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
 	})
 
+	// Respond to unknown paths and methods with the JSON error shape
+	rb.Router().NotFound(apicommon.NotFoundHandler)
+	rb.Router().MethodNotAllowed(apicommon.MethodNotAllowedHandler)
+
 	l.Info("http handlers registered successfully")
 }
 
@@ -148,7 +183,9 @@ func getCollector(c *config.Config, l *slog.Logger) (generate.MetadataCollector,
 		},
 		DatabaseSchemaFileOutputPath: "docs/cloud/schema.sql",
 		DocsFileOutputPath:           "docs/cloud/api_docs.json",
+		MarkdownOutputPath:           "docs/cloud/api_docs.md",
 		OpenAPISpecOutputPath:        "docs/cloud/openapi.yaml",
+		WarnUnusedTypes:              true,
 		Deployment:                   "cloud",
 		APIInfo: generate.APIInfo{
 			Title:       "Cloud API",
@@ -157,6 +194,9 @@ func getCollector(c *config.Config, l *slog.Logger) (generate.MetadataCollector,
 			Servers: []generate.ServerInfo{
 				{URL: "http://localhost:8080", Description: "Local server"},
 			},
+			TagDescriptions: map[string]string{
+				cloudapi.CoreGroup: "Server status and health checks",
+			},
 		},
 	})
 }
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
@@ -17,9 +19,11 @@ import (
 	apicommon "http-mqtt-boilerplate/backend/internal/shared/api"
 	"http-mqtt-boilerplate/backend/internal/shared/helpers"
 	"http-mqtt-boilerplate/backend/pkg/generate"
+	"http-mqtt-boilerplate/backend/pkg/lifecycle"
 	"http-mqtt-boilerplate/backend/pkg/mqtt"
 	"http-mqtt-boilerplate/backend/pkg/router"
 	"http-mqtt-boilerplate/backend/pkg/utils"
+	"http-mqtt-boilerplate/docs"
 	"http-mqtt-boilerplate/web"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -70,7 +74,10 @@ func main() {
 	}
 
 	// Builders
-	rb, err := router.NewRouteBuilder(logger, collector)
+	rb, err := router.NewRouteBuilder(logger, collector,
+		router.WithStrictSummaries(router.DefaultSummaryMaxLength),
+		router.WithBodyLimitResponse(apicommon.MaxBodySize, apicommon.BodyLimitResponse),
+	)
 	fatalIfErr(logger, err)
 
 	mb, err := mqtt.NewMQTTBuilder(logger, collector, mqtt.MQTTClientOptions{
@@ -106,23 +113,34 @@ func main() {
 
 	// HTTP Server
 	httpAddr := fmt.Sprintf(":%d", config.Port)
-	httpServer := apicommon.NewHTTPServer(logger, httpAddr, rb.Router())
+	httpServer := apicommon.NewHTTPServer(logger, httpAddr, rb.Router(), apicommon.HTTPServerTimeouts{
+		ReadHeader: config.ReadHeaderTimeout,
+		Read:       config.ReadTimeout,
+		Write:      config.WriteTimeout,
+		Idle:       config.IdleTimeout,
+		Shutdown:   config.ShutdownTimeout,
+	})
 	httpServer.StartOnBackground(sigCancel)
 
+	// Stop accepting HTTP requests first, then disconnect from the broker
+	lc := lifecycle.New(logger)
+	lc.Register("http server", 0, config.ShutdownTimeout, httpServer.Shutdown)
+	lc.Register("mqtt client", 1, 0, func(context.Context) error {
+		mb.DisconnectWithDefaultTimeout()
+
+		return nil
+	})
+
 	// Wait for signal (either OS or some failure)
 	<-sigCtx.Done()
 	logger.Info("received signal, shutting down...")
 
-	// Shutdown HTTP server
-	logger.Info("http server shutting down...")
+	if err := lc.Shutdown(context.Background()); err != nil {
+		logger.Error("server exited with shutdown errors", utils.ErrAttr(err))
 
-	if err := httpServer.ShutdownWithDefaultTimeout(); err != nil {
-		logger.Error("http server shutdown failed", utils.ErrAttr(err))
+		return
 	}
 
-	logger.Info("disconnecting from MQTT broker...")
-	mb.DisconnectWithDefaultTimeout()
-
 	logger.Info("server exited gracefully")
 }
 
@@ -142,13 +160,12 @@ func registerHTTPHandlers(l *slog.Logger, rb *router.RouteBuilder, h *localapi.H
 		rb.Use(mw.LoggerMiddleware)
 
 		h.RegisterPing("/ping", rb)
+		h.RegisterLiveness("/livez", rb)
+		h.RegisterReadiness("/readyz", rb)
 		h.RegisterHealth("/health", rb)
 
 		rb.Route("/team", func(rb *router.RouteBuilder) {
-			h.RegisterGetTeam("/{teamID}", rb)
-			h.RegisterPutTeam("/", rb)
-			h.RegisterCreateTeam("/", rb)
-			h.RegisterDeleteTeam("/", rb)
+			rb.MustRegisterAll(h.TeamRoutes())
 		})
 	})
 
@@ -156,10 +173,28 @@ func registerHTTPHandlers(l *slog.Logger, rb *router.RouteBuilder, h *localapi.H
 	fatalIfErr(l, err)
 	webapp.Register(rb.Router(), l)
 
+	// Serve the OpenAPI spec generated for this build, generate.sh embeds it
+	spec, err := docs.OpenAPISpec("local")
+	if errors.Is(err, fs.ErrNotExist) {
+		l.Warn("OpenAPI spec not embedded, run generate.sh to serve it", utils.ErrAttr(err))
+	} else {
+		fatalIfErr(l, err)
+
+		specHandler, err := apicommon.NewSpecHandler(spec)
+		fatalIfErr(l, err)
+
+		rb.Router().Get("/openapi.yaml", specHandler.ServeYAML)
+		rb.Router().Get("/openapi.json", specHandler.ServeJSON)
+	}
+
 	rb.Router().HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
 	})
 
+	// Respond to unknown paths and methods with the JSON error shape
+	rb.Router().NotFound(apicommon.NotFoundHandler)
+	rb.Router().MethodNotAllowed(apicommon.MethodNotAllowedHandler)
+
 	l.Info("http handlers registered successfully")
 }
 
@@ -194,7 +229,10 @@ func getCollector(c *config.Config, l *slog.Logger) (generate.MetadataCollector,
 		},
 		DatabaseSchemaFileOutputPath: "docs/local/schema.sql",
 		DocsFileOutputPath:           "docs/local/api_docs.json",
+		MarkdownOutputPath:           "docs/local/api_docs.md",
 		OpenAPISpecOutputPath:        "docs/local/openapi.yaml",
+		AsyncAPISpecOutputPath:       "docs/local/asyncapi.yaml",
+		WarnUnusedTypes:              true,
 		Deployment:                   "local",
 		APIInfo: generate.APIInfo{
 			Title:       "Local API",
@@ -203,6 +241,10 @@ func getCollector(c *config.Config, l *slog.Logger) (generate.MetadataCollector,
 			Servers: []generate.ServerInfo{
 				{URL: "http://localhost:8080", Description: "Local server"},
 			},
+			TagDescriptions: map[string]string{
+				localapi.CoreGroup: "Server status and health checks",
+				localapi.TeamGroup: "Team management",
+			},
 		},
 	})
 }
@@ -3,8 +3,10 @@ package cloud
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	clouddb "http-mqtt-boilerplate/backend/internal/cloud/gen"
+	"http-mqtt-boilerplate/backend/pkg/utils"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -25,6 +27,9 @@ func NewCoreService(l *slog.Logger, db *pgxpool.Pool, queries *clouddb.Queries)
 	}
 }
 
+// healthCheckTimeout bounds the dependency checks of [CoreService.Health].
+const healthCheckTimeout = 2 * time.Second
+
 // HealthStatus represents the health status of cloud services.
 type HealthStatus struct {
 	Database bool
@@ -36,8 +41,12 @@ func (s *CoreService) Health(ctx context.Context) HealthStatus {
 		Database: true,
 	}
 
+	// Keep the check fast, a hanging database must not hang the health endpoint
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
 	if err := s.db.Ping(ctx); err != nil {
-		s.l.Error("database unreachable", slog.String("error", err.Error()))
+		s.l.Error("database unreachable", utils.ErrAttr(err))
 
 		status.Database = false
 	}
@@ -54,11 +54,51 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// RegisterLiveness registers a liveness probe, it responds with 200 as long as the process can serve requests.
+func (h *Handler) RegisterLiveness(path string, rb *router.RouteBuilder) {
+	rb.MustGet(path, router.RouteSpec{
+		OperationID: "liveness",
+		Summary:     "Check server liveness",
+		Description: "Check if the server process is alive. Dependencies are not checked, use readiness for that",
+		Group:       CoreGroup,
+		RequestType: nil,
+		Responses: apitypes.GenerateResponses(map[int]router.ResponseSpec{
+			200: {
+				Description: "Server is alive",
+				Type:        sharedtypes.PingResponse{},
+				Examples: map[string]any{
+					"Alive": sharedtypes.PingResponse{Message: "Alive", Status: sharedtypes.PingStatusOK},
+				},
+			},
+		}),
+		Handler: apitypes.ErrorHandler(func(w http.ResponseWriter, r *http.Request) error {
+			resp := sharedtypes.PingResponse{
+				Message: "Alive", Status: sharedtypes.PingStatusOK,
+			}
+			apitypes.RespondJSON(w, r, http.StatusOK, resp)
+
+			return nil
+		}),
+	})
+}
+
+// RegisterReadiness registers a readiness probe, it responds with 503 until all dependencies are reachable.
+func (h *Handler) RegisterReadiness(path string, rb *router.RouteBuilder) {
+	h.registerReadiness(path, rb, "readiness", "Check server readiness",
+		"Check the database and MQTT broker connections. Responds with 503 if any dependency is unavailable")
+}
+
+// RegisterHealth registers the readiness check under its original operation, kept for backward compatibility.
 func (h *Handler) RegisterHealth(path string, rb *router.RouteBuilder) {
+	h.registerReadiness(path, rb, "health", "Check server health",
+		"Alias of readiness, kept for backward compatibility. Check the database and MQTT broker connections. Responds with 503 if any dependency is unavailable")
+}
+
+func (h *Handler) registerReadiness(path string, rb *router.RouteBuilder, operationID, summary, description string) {
 	rb.MustGet(path, router.RouteSpec{
-		OperationID: "health",
-		Summary:     "Check server health",
-		Description: "Check if the server is healthy",
+		OperationID: operationID,
+		Summary:     summary,
+		Description: description,
 		Group:       CoreGroup,
 		RequestType: nil,
 		Handler:     apitypes.ErrorHandler(h.Health),
@@ -71,7 +111,7 @@ func (h *Handler) RegisterHealth(path string, rb *router.RouteBuilder) {
 				},
 			},
 			503: {
-				Description: "Server unavailable",
+				Description: "One or more dependencies are unavailable",
 				Type:        localtypes.HealthResponse{},
 				Examples: map[string]any{
 					"Database Unavailable": localtypes.HealthResponse{Database: false, MQTT: true},
@@ -13,16 +13,36 @@ import (
 	"http-mqtt-boilerplate/backend/pkg/utils"
 )
 
+// exampleTeamID is the team ID used in the documentation examples.
+const exampleTeamID = "0190b7c4-8f6e-7c3a-9d2b-4a5e6f708192"
+
+// TeamRoutes returns the team management routes, paths are relative to the team group.
+func (h *Handler) TeamRoutes() []router.RouteSpec {
+	return []router.RouteSpec{
+		h.getTeamRoute("/{teamID}"),
+		h.patchTeamRoute("/{teamID}"),
+		h.listTeamUsersRoute("/{teamID}/users"),
+		h.putTeamRoute("/"),
+		h.createTeamRoute("/"),
+		h.deleteTeamRoute("/"),
+	}
+}
+
 func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) error {
-	teamID := chi.URLParam(r, "teamID")
+	teamID, err := utils.NewUUID(chi.URLParam(r, "teamID"))
+	if err != nil {
+		return apitypes.NewAPIError(http.StatusBadRequest, "Invalid team ID")
+	}
 
 	apitypes.RespondJSON(w, r, http.StatusOK, localtypes.GetTeamResponse{TeamID: teamID, Users: []localtypes.User{{UserID: "Asdf"}}})
 
 	return nil
 }
 
-func (h *Handler) RegisterGetTeam(path string, rb *router.RouteBuilder) {
-	rb.MustGet(path, router.RouteSpec{
+func (h *Handler) getTeamRoute(path string) router.RouteSpec {
+	return router.RouteSpec{
+		Method:      http.MethodGet,
+		Path:        path,
 		OperationID: "getTeam",
 		Summary:     "Get a team",
 		Description: "Get a team by its ID",
@@ -35,7 +55,7 @@ func (h *Handler) RegisterGetTeam(path string, rb *router.RouteBuilder) {
 				In:          "path",
 				Description: "ID of the team to get",
 				Required:    true,
-				Type:        new(string),
+				Type:        new(utils.UUID),
 			},
 		},
 		Responses: apitypes.GenerateResponses(map[int]router.ResponseSpec{
@@ -50,7 +70,7 @@ func (h *Handler) RegisterGetTeam(path string, rb *router.RouteBuilder) {
 				Description: "Successful ping response",
 				Type:        localtypes.GetTeamResponse{},
 				Examples: map[string]any{
-					"example-1": localtypes.GetTeamResponse{TeamID: "123", Users: []localtypes.User{{UserID: "123", Name: "John"}}},
+					"example-1": localtypes.GetTeamResponse{TeamID: exampleTeamID, Users: []localtypes.User{{UserID: "123", Name: "John"}}},
 				},
 			},
 			400: {
@@ -61,17 +81,22 @@ func (h *Handler) RegisterGetTeam(path string, rb *router.RouteBuilder) {
 				},
 			},
 		}),
-	})
+	}
 }
 
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) error {
-	apitypes.RespondJSON(w, r, http.StatusOK, sharedtypes.PingResponse{Message: "Pong", Status: sharedtypes.PingStatusOK})
+	teamID := utils.NewRandomUUID()
+
+	w.Header().Set("Location", "/api/team/"+teamID.String())
+	apitypes.RespondJSON(w, r, http.StatusCreated, localtypes.GetTeamResponse{TeamID: teamID, Users: []localtypes.User{}})
 
 	return nil
 }
 
-func (h *Handler) RegisterCreateTeam(path string, rb *router.RouteBuilder) {
-	rb.MustPost(path, router.RouteSpec{
+func (h *Handler) createTeamRoute(path string) router.RouteSpec {
+	return router.RouteSpec{
+		Method:      http.MethodPost,
+		Path:        path,
 		OperationID: "createTeam",
 		Summary:     "Create a team",
 		Description: "Create a team by its name",
@@ -84,11 +109,17 @@ func (h *Handler) RegisterCreateTeam(path string, rb *router.RouteBuilder) {
 			},
 		},
 		Responses: apitypes.GenerateResponses(map[int]router.ResponseSpec{
-			200: {
-				Description: "Successful ping response",
-				Type:        sharedtypes.PingResponse{},
+			201: {
+				Description: "Team created",
+				Type:        localtypes.GetTeamResponse{},
 				Examples: map[string]any{
-					"example-1": sharedtypes.PingResponse{Message: "Pong", Status: sharedtypes.PingStatusOK},
+					"example-1": localtypes.GetTeamResponse{TeamID: exampleTeamID, Users: []localtypes.User{}},
+				},
+				Headers: map[string]router.HeaderSpec{
+					"Location": {
+						Description: "URL of the created team (e.g., /api/team/" + exampleTeamID + ")",
+						Type:        new(string),
+					},
 				},
 			},
 			400: {
@@ -99,7 +130,7 @@ func (h *Handler) RegisterCreateTeam(path string, rb *router.RouteBuilder) {
 				},
 			},
 		}),
-	})
+	}
 }
 
 func (h *Handler) DeleteTeam(w http.ResponseWriter, r *http.Request) error {
@@ -108,8 +139,10 @@ func (h *Handler) DeleteTeam(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-func (h *Handler) RegisterDeleteTeam(path string, rb *router.RouteBuilder) {
-	rb.MustDelete(path, router.RouteSpec{
+func (h *Handler) deleteTeamRoute(path string) router.RouteSpec {
+	return router.RouteSpec{
+		Method:      http.MethodDelete,
+		Path:        path,
 		OperationID: "deleteTeam",
 		Summary:     "Create a team",
 		Description: "Create a team by its name",
@@ -137,7 +170,7 @@ func (h *Handler) RegisterDeleteTeam(path string, rb *router.RouteBuilder) {
 				},
 			},
 		}),
-	})
+	}
 }
 
 func (h *Handler) PutTeam(w http.ResponseWriter, r *http.Request) error {
@@ -146,8 +179,10 @@ func (h *Handler) PutTeam(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-func (h *Handler) RegisterPutTeam(path string, rb *router.RouteBuilder) {
-	rb.MustPut(path, router.RouteSpec{
+func (h *Handler) putTeamRoute(path string) router.RouteSpec {
+	return router.RouteSpec{
+		Method:      http.MethodPut,
+		Path:        path,
 		OperationID: "putTeam",
 		Summary:     "Create a team",
 		Description: "Create a team by its name",
@@ -175,5 +210,123 @@ func (h *Handler) RegisterPutTeam(path string, rb *router.RouteBuilder) {
 				},
 			},
 		}),
-	})
+	}
+}
+
+func (h *Handler) PatchTeam(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := utils.NewUUID(chi.URLParam(r, "teamID"))
+	if err != nil {
+		return apitypes.NewAPIError(http.StatusBadRequest, "Invalid team ID")
+	}
+
+	// The body is a CreateTeamRequestPatch, applied onto the current team
+	if _, err := apitypes.DecodeAndValidatePatchJSON(r, localtypes.CreateTeamRequest{Name: "My Team"}); err != nil {
+		return err
+	}
+
+	apitypes.RespondJSON(w, r, http.StatusOK, localtypes.GetTeamResponse{TeamID: teamID, Users: []localtypes.User{}})
+
+	return nil
+}
+
+// patchTeamRoute is an example partial update route, the body is the all-optional patch type of CreateTeamRequest.
+func (h *Handler) patchTeamRoute(path string) router.RouteSpec {
+	return router.RouteSpec{
+		Method:      http.MethodPatch,
+		Path:        path,
+		OperationID: "patchTeam",
+		Summary:     "Update a team",
+		Description: "Update some of the fields of a team, absent fields are left unchanged",
+		Group:       TeamGroup,
+		Handler:     apitypes.ErrorHandler(h.PatchTeam),
+		RequestType: &router.RequestBodySpec{
+			Type:  localtypes.CreateTeamRequest{},
+			Patch: true,
+			Examples: map[string]any{
+				"rename": localtypes.CreateTeamRequest{Name: "Renamed Team"},
+			},
+		},
+		Parameters: map[string]router.ParameterSpec{
+			"teamID": {
+				In:          "path",
+				Description: "ID of the team to update",
+				Required:    true,
+				Type:        new(utils.UUID),
+			},
+		},
+		Responses: apitypes.GenerateResponses(map[int]router.ResponseSpec{
+			200: {
+				Description: "Team updated",
+				Type:        localtypes.GetTeamResponse{},
+				Examples: map[string]any{
+					"example-1": localtypes.GetTeamResponse{TeamID: exampleTeamID, Users: []localtypes.User{}},
+				},
+			},
+		}),
+	}
+}
+
+// exampleTeamUsers are the users listed by the example list team users route, ordered by ID.
+//
+//nolint:gochecknoglobals // Example data
+var exampleTeamUsers = []localtypes.User{
+	{UserID: "1", Name: "John"},
+	{UserID: "2", Name: "Jane"},
+	{UserID: "3", Name: "Alice"},
+}
+
+func (h *Handler) ListTeamUsers(w http.ResponseWriter, r *http.Request) error {
+	if _, err := utils.NewUUID(chi.URLParam(r, "teamID")); err != nil {
+		return apitypes.NewAPIError(http.StatusBadRequest, "Invalid team ID")
+	}
+
+	limit, cursor, err := apitypes.ParsePageParams(r)
+	if err != nil {
+		return err
+	}
+
+	// Fetch up to limit+1 users after the cursor, like a keyset query would
+	users := make([]localtypes.User, 0, limit+1)
+
+	for _, user := range exampleTeamUsers {
+		if user.UserID > cursor && len(users) <= limit {
+			users = append(users, user)
+		}
+	}
+
+	page := apitypes.NewPage(users, limit, func(u localtypes.User) string { return u.UserID })
+	apitypes.RespondJSON(w, r, http.StatusOK, localtypes.UserPage(page))
+
+	return nil
+}
+
+// listTeamUsersRoute is an example paginated route, the response documents [apitypes.Page] through [localtypes.UserPage].
+func (h *Handler) listTeamUsersRoute(path string) router.RouteSpec {
+	return router.RouteSpec{
+		Method:      http.MethodGet,
+		Path:        path,
+		OperationID: "listTeamUsers",
+		Summary:     "List team users",
+		Description: "List the users of a team, one page at a time",
+		Group:       TeamGroup,
+		Handler:     apitypes.ErrorHandler(h.ListTeamUsers),
+		Parameters: apitypes.PageParameters(map[string]router.ParameterSpec{
+			"teamID": {
+				In:          "path",
+				Description: "ID of the team to list the users of",
+				Required:    true,
+				Type:        new(utils.UUID),
+			},
+		}),
+		Responses: apitypes.GenerateResponses(map[int]router.ResponseSpec{
+			200: {
+				Description: "A page of users",
+				Type:        localtypes.UserPage{},
+				Examples: map[string]any{
+					"first-page": localtypes.UserPage{Items: exampleTeamUsers[:2], NextCursor: new("2"), HasMore: true},
+					"last-page":  localtypes.UserPage{Items: exampleTeamUsers[2:]},
+				},
+			},
+		}),
+	}
 }
@@ -16,10 +16,20 @@ type User struct {
 	Name string `json:"name"`
 }
 
+// UserPage is a page of users, it has the same fields as apicommon.Page[User] and converts from it.
+type UserPage struct {
+	// Users in this page
+	Items []User `json:"items"`
+	// Cursor to request the next page, null on the last page
+	NextCursor *string `json:"nextCursor"`
+	// Whether more users are available
+	HasMore bool `json:"hasMore"`
+}
+
 // GetTeamRequest is the request to get a team.
 type GetTeamRequest struct {
 	// ID of the team to get
-	TeamID string `json:"teamID"`
+	TeamID utils.UUID `json:"teamID"`
 }
 
 // GetTeamResponse is the response to a get team request.
@@ -27,7 +37,7 @@ type GetTeamRequest struct {
 // Deprecated: Use GetTeamResponseV2 instead.
 type GetTeamResponse struct {
 	// ID of the team
-	TeamID string `json:"teamID"`
+	TeamID utils.UUID `json:"teamID"`
 	// Users in the team
 	Users []User `json:"users"`
 }
@@ -35,7 +45,7 @@ type GetTeamResponse struct {
 // CreateTeamRequest is the request to create a new team.
 type CreateTeamRequest struct {
 	// Name of the team to create
-	Name string `json:"name"`
+	Name string `json:"name" validate:"min=1"`
 }
 
 // CreateUserRequest is the request to create a new user.
@@ -43,7 +53,7 @@ type CreateUserRequest struct {
 	// Username to create
 	Username string `json:"username"`
 	// Password to create
-	Password string `json:"password"`
+	Password string `json:"password" openapi:"writeOnly"`
 }
 
 // CreateUserResponse is the response to a create user request.
@@ -51,7 +61,7 @@ type CreateUserResponse struct {
 	// ID of the created user
 	UserID string `json:"userID"`
 	// Creation timestamp
-	CreatedAt time.Time `json:"createdAt"`
+	CreatedAt time.Time `json:"createdAt" openapi:"readOnly"`
 	// URL to the user
 	URL *utils.URL `json:"url"`
 }
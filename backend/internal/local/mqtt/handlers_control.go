@@ -1,15 +1,12 @@
 package mqtt
 
 import (
-	"encoding/json"
+	"context"
 	"log/slog"
 	"time"
 
-	"github.com/eclipse/paho.golang/paho"
-
 	"http-mqtt-boilerplate/backend/internal/local/mqtt/types"
 	"http-mqtt-boilerplate/backend/pkg/mqtt"
-	"http-mqtt-boilerplate/backend/pkg/utils"
 )
 
 // RegisterDeviceCommandPublish registers the device command publication operation.
@@ -67,8 +64,8 @@ func (s *Handler) RegisterDeviceCommandSubscribe(mb *mqtt.MQTTBuilder) {
 			DeviceID: "device-001",
 			Command:  "restart",
 		},
-		Handler: s.handleDeviceCommand,
-		QoS:     mqtt.QoSAtLeastOnce,
+		TypedHandler: mqtt.TypedHandler(s.handleDeviceCommand),
+		QoS:          mqtt.QoSAtLeastOnce,
 		Examples: map[string]any{
 			"restart": types.DeviceCommand{
 				DeviceID: "device-001",
@@ -79,16 +76,7 @@ func (s *Handler) RegisterDeviceCommandSubscribe(mb *mqtt.MQTTBuilder) {
 }
 
 // handleDeviceCommand handles incoming device commands.
-func (s *Handler) handleDeviceCommand(msg *paho.Publish) {
-	var command types.DeviceCommand
-	if err := json.Unmarshal(msg.Payload, &command); err != nil {
-		s.l.Error("failed to unmarshal device command",
-			slog.String("topic", msg.Topic),
-			utils.ErrAttr(err))
-
-		return
-	}
-
+func (s *Handler) handleDeviceCommand(_ context.Context, _ map[string]string, command types.DeviceCommand) error {
 	s.l.Info("received device command",
 		slog.String("deviceID", command.DeviceID),
 		slog.String("command", command.Command),
@@ -96,6 +84,8 @@ func (s *Handler) handleDeviceCommand(msg *paho.Publish) {
 
 	// Process the command (e.g., log, validate, forward to device)
 	// TODO: Add your business logic here
+
+	return nil
 }
 
 // RegisterDeviceStatusPublish registers the device status publication operation.
@@ -157,8 +147,8 @@ func (s *Handler) RegisterDeviceStatusSubscribe(mb *mqtt.MQTTBuilder) {
 			Uptime:    3600,
 			Timestamp: time.Time{},
 		},
-		Handler: s.handleDeviceStatus,
-		QoS:     mqtt.QoSAtLeastOnce,
+		TypedHandler: mqtt.TypedHandler(s.handleDeviceStatus),
+		QoS:          mqtt.QoSAtLeastOnce,
 		Examples: map[string]any{
 			"online": types.DeviceStatus{
 				DeviceID:  "device-001",
@@ -171,16 +161,7 @@ func (s *Handler) RegisterDeviceStatusSubscribe(mb *mqtt.MQTTBuilder) {
 }
 
 // handleDeviceStatus handles incoming device status updates.
-func (s *Handler) handleDeviceStatus(msg *paho.Publish) {
-	var status types.DeviceStatus
-	if err := json.Unmarshal(msg.Payload, &status); err != nil {
-		s.l.Error("failed to unmarshal device status",
-			slog.String("topic", msg.Topic),
-			utils.ErrAttr(err))
-
-		return
-	}
-
+func (s *Handler) handleDeviceStatus(_ context.Context, _ map[string]string, status types.DeviceStatus) error {
 	s.l.Info("received device status",
 		slog.String("deviceID", status.DeviceID),
 		slog.String("status", status.Status),
@@ -188,4 +169,6 @@ func (s *Handler) handleDeviceStatus(msg *paho.Publish) {
 
 	// Process the status (e.g., update device registry, trigger alerts)
 	// TODO: Add your business logic here
+
+	return nil
 }
@@ -1,15 +1,12 @@
 package mqtt
 
 import (
-	"encoding/json"
+	"context"
 	"log/slog"
 	"time"
 
-	"github.com/eclipse/paho.golang/paho"
-
 	"http-mqtt-boilerplate/backend/internal/local/mqtt/types"
 	"http-mqtt-boilerplate/backend/pkg/mqtt"
-	"http-mqtt-boilerplate/backend/pkg/utils"
 )
 
 // RegisterTemperaturePublish registers the temperature publication operation.
@@ -72,8 +69,8 @@ func (s *Handler) RegisterTemperatureSubscribe(mb *mqtt.MQTTBuilder) {
 			Unit:        "celsius",
 			Timestamp:   time.Time{},
 		},
-		Handler: s.handleTemperature,
-		QoS:     mqtt.QoSAtLeastOnce,
+		TypedHandler: mqtt.TypedHandler(s.handleTemperature),
+		QoS:          mqtt.QoSAtLeastOnce,
 		Examples: map[string]any{
 			"normal": types.TemperatureReading{
 				DeviceID:    "device-001",
@@ -86,18 +83,13 @@ func (s *Handler) RegisterTemperatureSubscribe(mb *mqtt.MQTTBuilder) {
 }
 
 // handleTemperature handles incoming temperature readings.
-func (s *Handler) handleTemperature(msg *paho.Publish) {
-	var reading types.TemperatureReading
-	if err := json.Unmarshal(msg.Payload, &reading); err != nil {
-		s.l.Error("failed to unmarshal temperature reading", slog.String("topic", msg.Topic), utils.ErrAttr(err))
-
-		return
-	}
-
+func (s *Handler) handleTemperature(_ context.Context, _ map[string]string, reading types.TemperatureReading) error {
 	s.l.Info("received temperature reading", slog.String("deviceID", reading.DeviceID), slog.Float64("temperature", reading.Temperature), slog.String("unit", reading.Unit), slog.Time("timestamp", reading.Timestamp))
 
 	// Process the reading (e.g., store in database, trigger alerts, etc.)
 	// TODO: Add your business logic here
+
+	return nil
 }
 
 // RegisterSensorTelemetryPublish registers the sensor telemetry publication operation.
@@ -177,8 +169,8 @@ func (s *Handler) RegisterSensorTelemetrySubscribe(mb *mqtt.MQTTBuilder) {
 			Timestamp:  time.Time{},
 			Quality:    95,
 		},
-		Handler: s.handleSensorTelemetry,
-		QoS:     mqtt.QoSAtLeastOnce,
+		TypedHandler: mqtt.TypedHandler(s.handleSensorTelemetry),
+		QoS:          mqtt.QoSAtLeastOnce,
 		Examples: map[string]any{
 			"humidity": types.SensorTelemetry{
 				DeviceID:   "device-001",
@@ -193,16 +185,11 @@ func (s *Handler) RegisterSensorTelemetrySubscribe(mb *mqtt.MQTTBuilder) {
 }
 
 // handleSensorTelemetry handles incoming sensor telemetry data.
-func (s *Handler) handleSensorTelemetry(msg *paho.Publish) {
-	var telemetry types.SensorTelemetry
-	if err := json.Unmarshal(msg.Payload, &telemetry); err != nil {
-		s.l.Error("failed to unmarshal sensor telemetry", slog.String("topic", msg.Topic), utils.ErrAttr(err))
-
-		return
-	}
-
+func (s *Handler) handleSensorTelemetry(_ context.Context, _ map[string]string, telemetry types.SensorTelemetry) error {
 	s.l.Info("received sensor telemetry", slog.String("deviceID", telemetry.DeviceID), slog.String("sensorType", telemetry.SensorType), slog.Float64("value", telemetry.Value), slog.String("unit", telemetry.Unit), slog.Int("quality", telemetry.Quality))
 
 	// Process the telemetry (e.g., store in database, trigger alerts, etc.)
 	// TODO: Add your business logic here
+
+	return nil
 }
@@ -3,18 +3,32 @@ package local
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	localdb "http-mqtt-boilerplate/backend/internal/local/gen"
 	"http-mqtt-boilerplate/backend/pkg/mqtt"
+	"http-mqtt-boilerplate/backend/pkg/utils"
 )
 
+// pinger checks that the database is reachable, it is satisfied by [pgxpool.Pool].
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// connectionChecker reports whether the broker connection is up, it is satisfied by [mqtt.MQTTClient].
+// The client reports connected once a connection completes and disconnected as soon as it is lost,
+// so it gates readiness while the initial connection is still in progress.
+type connectionChecker interface {
+	IsConnected() bool
+}
+
 // CoreService handles core business logic for the local API.
 type CoreService struct {
 	l    *slog.Logger
-	mqtt *mqtt.MQTTClient
-	pool *pgxpool.Pool
+	mqtt connectionChecker
+	pool pinger
 	q    *localdb.Queries
 }
 
@@ -28,6 +42,9 @@ func NewCoreService(l *slog.Logger, mqttClient *mqtt.MQTTClient, pool *pgxpool.P
 	}
 }
 
+// healthCheckTimeout bounds the dependency checks of [CoreService.Health].
+const healthCheckTimeout = 2 * time.Second
+
 // HealthStatus represents the health status of local services.
 type HealthStatus struct {
 	Database bool
@@ -41,8 +58,12 @@ func (s *CoreService) Health(ctx context.Context) HealthStatus {
 		MQTT:     true,
 	}
 
+	// Keep the check fast, a hanging database must not hang the health endpoint
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
 	if err := s.pool.Ping(ctx); err != nil {
-		s.l.Error("database unreachable", slog.String("error", err.Error()))
+		s.l.Error("database unreachable", utils.ErrAttr(err))
 
 		status.Database = false
 	}
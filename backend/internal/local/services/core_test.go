@@ -0,0 +1,63 @@
+package local
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakePinger struct{}
+
+func (fakePinger) Ping(context.Context) error { return nil }
+
+type fakeConnection struct {
+	connected atomic.Bool
+}
+
+func (c *fakeConnection) IsConnected() bool { return c.connected.Load() }
+
+func TestHealthFollowsMQTTConnection(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeConnection{}
+	svc := &CoreService{
+		l:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		mqtt: conn,
+		pool: fakePinger{},
+	}
+
+	// The HTTP server serves before the broker connection completes
+	if status := svc.Health(t.Context()); status.MQTT {
+		t.Fatal("Health().MQTT = true before connecting, want false")
+	}
+
+	// Simulate a delayed connect
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		conn.connected.Store(true)
+	}()
+
+	deadline := time.After(time.Second)
+
+	for !svc.Health(t.Context()).MQTT {
+		select {
+		case <-deadline:
+			t.Fatal("Health().MQTT did not become true after connecting")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if status := svc.Health(t.Context()); !status.Database {
+		t.Error("Health().Database = false, want true")
+	}
+
+	// Connection loss flips readiness back
+	conn.connected.Store(false)
+
+	if status := svc.Health(t.Context()); status.MQTT {
+		t.Error("Health().MQTT = true after connection lost, want false")
+	}
+}
@@ -1,14 +1,13 @@
 package apicommon
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"http-mqtt-boilerplate/backend/internal/shared/types"
@@ -19,35 +18,40 @@ import (
 const (
 	MaxBodySize     = 1048576 // 1MB
 	RequestIDHeader = "X-Request-ID"
-
-	ReadHeaderTimeout = 5 * time.Second
-	ReadTimeout       = 30 * time.Second
-	WriteTimeout      = 30 * time.Second
-	IdleTimeout       = 120 * time.Second
-	ShutdownTimeout   = 10 * time.Second
 )
 
 const zeroUUID = "00000000-0000-0000-0000-000000000000"
 
+// HTTPServerTimeouts bounds the phases of a request and the graceful shutdown of an [HTTPServer].
+type HTTPServerTimeouts struct {
+	ReadHeader time.Duration // ReadHeader bounds reading the request headers
+	Read       time.Duration // Read bounds reading the whole request, body included
+	Write      time.Duration // Write bounds writing the response
+	Idle       time.Duration // Idle bounds how long an idle keep-alive connection stays open
+	Shutdown   time.Duration // Shutdown bounds waiting for in-flight requests in [HTTPServer.Shutdown]
+}
+
 type HTTPServer struct {
-	l      *slog.Logger
-	server *http.Server
+	l               *slog.Logger
+	server          *http.Server
+	shutdownTimeout time.Duration
 }
 
-func NewHTTPServer(l *slog.Logger, addr string, handler http.Handler) *HTTPServer {
+func NewHTTPServer(l *slog.Logger, addr string, handler http.Handler, timeouts HTTPServerTimeouts) *HTTPServer {
 	srv := &http.Server{
 		Addr:              addr,
 		Handler:           handler,
-		ReadHeaderTimeout: ReadHeaderTimeout,
-		ReadTimeout:       ReadTimeout,
-		WriteTimeout:      WriteTimeout,
-		IdleTimeout:       IdleTimeout,
+		ReadHeaderTimeout: timeouts.ReadHeader,
+		ReadTimeout:       timeouts.Read,
+		WriteTimeout:      timeouts.Write,
+		IdleTimeout:       timeouts.Idle,
 	}
 	srv.SetKeepAlivesEnabled(true)
 
 	return &HTTPServer{
-		l:      l.With(slog.String("component", "http-server")),
-		server: srv,
+		l:               l.With(slog.String("component", "http-server")),
+		server:          srv,
+		shutdownTimeout: timeouts.Shutdown,
 	}
 }
 
@@ -62,21 +66,39 @@ func (s *HTTPServer) StartOnBackground(cancel context.CancelFunc) {
 	}()
 }
 
-func (s *HTTPServer) ShutdownWithDefaultTimeout() error {
-	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+// Shutdown gracefully stops the server, waiting for in-flight requests up to the configured shutdown timeout or until ctx is done.
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
 	defer cancel()
 
 	return s.server.Shutdown(ctx)
 }
 
-// MiddlewareHandler holds the logger for middleware.
+// MiddlewareHandler holds the logger and shared configuration for middleware.
 type MiddlewareHandler struct {
-	l *slog.Logger
+	l               *slog.Logger
+	jsonErrorMapper JSONErrorMapper
+}
+
+// MiddlewareOption customizes a [MiddlewareHandler].
+type MiddlewareOption func(*MiddlewareHandler)
+
+// WithJSONErrorMapper sets the mapper used by [DecodeJSON] to convert decoding errors to API errors.
+// The mapper is stored in the request context by [MiddlewareHandler.LoggerMiddleware].
+func WithJSONErrorMapper(mapper JSONErrorMapper) MiddlewareOption {
+	return func(m *MiddlewareHandler) {
+		m.jsonErrorMapper = mapper
+	}
 }
 
 // NewMiddlewareHandler creates a new middleware handler.
-func NewMiddlewareHandler(l *slog.Logger) *MiddlewareHandler {
-	return &MiddlewareHandler{l: l}
+func NewMiddlewareHandler(l *slog.Logger, opts ...MiddlewareOption) *MiddlewareHandler {
+	m := &MiddlewareHandler{l: l, jsonErrorMapper: DefaultJSONErrorMapper{}}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 // HandlerFunc is a HTTP handler that can return an error.
@@ -122,8 +144,9 @@ func ErrorHandler(fn HandlerFunc) http.HandlerFunc {
 
 // RespondJSON sends a JSON response with given status code
 // If data is nil, only headers are sent
-// In case of JSON encoding error, it is logged but not returned to client
-// but the status code is sent already.
+// The body is indented when the request has a truthy pretty query parameter (e.g., ?pretty=1), for debugging.
+// The body is streamed, so in case of JSON encoding error it is logged but not returned to client
+// as the status code is sent already. [RespondJSONWithETag] and [Respond] encode the whole body first.
 func RespondJSON(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -132,78 +155,128 @@ func RespondJSON(w http.ResponseWriter, r *http.Request, statusCode int, data an
 		return
 	}
 
-	l := GetLoggerFromContext(r.Context())
-	if err := utils.ToJSONStream(w, data); err != nil {
+	encode := utils.ToJSONStream
+	if wantsPrettyJSON(r) {
+		encode = utils.ToJSONStreamIndent
+	}
+
+	if err := encode(w, data); err != nil {
+		// Note that if this fails header has already been written
+		// There's not much we can do at this point
+		GetLoggerFromContext(r.Context()).Error("failed to encode JSON response", utils.ErrAttr(err))
+	}
+}
+
+// encodeJSONResponse encodes data for a JSON response, indented if requested with the pretty query parameter.
+// On failure it logs the error, responds with a 500 and returns false.
+func encodeJSONResponse(w http.ResponseWriter, r *http.Request, data any) ([]byte, bool) {
+	encode := utils.ToJSONStream
+	if wantsPrettyJSON(r) {
+		encode = utils.ToJSONStreamIndent
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, data); err != nil {
+		GetLoggerFromContext(r.Context()).Error("failed to encode JSON response", utils.ErrAttr(err))
+		writeJSONResponse(w, r, http.StatusInternalServerError, []byte(`{"message":"Internal Server Error"}`))
+
+		return nil, false
+	}
+
+	return buf.Bytes(), true
+}
+
+// wantsPrettyJSON reports whether the pretty query parameter asks for indented JSON.
+func wantsPrettyJSON(r *http.Request) bool {
+	pretty, err := strconv.ParseBool(r.URL.Query().Get("pretty"))
+
+	return err == nil && pretty
+}
+
+// writeJSONResponse writes an already encoded JSON body with given status code.
+func writeJSONResponse(w http.ResponseWriter, r *http.Request, statusCode int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(body); err != nil {
 		// Note that if this fails header has already been written
 		// There's not much we can do at this point
-		l.Error("failed to encode JSON response", utils.ErrAttr(err))
+		GetLoggerFromContext(r.Context()).Error("failed to write JSON response", utils.ErrAttr(err))
 	}
 }
 
 // DecodeJSON decodes JSON from request body with error handling.
+// Decoding errors are converted to API errors by the [JSONErrorMapper] stored in the request context,
+// or by [DefaultJSONErrorMapper] if none is set.
 //
 //nolint:ireturn // Generic functions must return type parameter T
 func DecodeJSON[T any](r *http.Request) (T, error) {
-	var zero T
+	var res T
 
-	r.Body = http.MaxBytesReader(nil, r.Body, MaxBodySize)
+	if err := decodeJSONInto(r, &res); err != nil {
+		var zero T
 
-	res, err := utils.FromJSONStream[T](r.Body)
-	if err != nil {
-		// FIXME: on Go 1.26 use errors.AsType[...]()
-		var (
-			syntaxError        *json.SyntaxError
-			unmarshalTypeError *json.UnmarshalTypeError
-			maxBytesError      *http.MaxBytesError
-			extraDataError     *utils.ExtraDataAfterJSONError
-		)
-
-		switch {
-		case errors.As(err, &syntaxError):
-			return zero, NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid JSON syntax at position %d", syntaxError.Offset))
+		return zero, err
+	}
 
-		case errors.As(err, &unmarshalTypeError):
-			return zero, NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid type for field '%s'", unmarshalTypeError.Field))
+	return res, nil
+}
 
-		case errors.Is(err, io.EOF):
-			return zero, NewAPIError(http.StatusBadRequest, "Request body is empty")
+// decodeJSONInto decodes JSON from request body into v like [DecodeJSON], fields absent from the body keep their value.
+func decodeJSONInto(r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(nil, r.Body, getMaxBodyBytes(r))
 
-		case errors.Is(err, io.ErrUnexpectedEOF):
-			return zero, NewAPIError(http.StatusBadRequest, "Malformed JSON")
+	if err := utils.FromJSONStreamInto(r.Body, v); err != nil {
+		return GetJSONErrorMapperFromContext(r.Context()).MapJSONError(err)
+	}
 
-		case errors.As(err, &maxBytesError):
-			return zero, NewAPIError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (max %dMB)", MaxBodySize/(1024*1024)))
+	return nil
+}
 
-		case errors.As(err, &extraDataError):
-			return zero, NewAPIError(http.StatusBadRequest, "Request body contains multiple JSON objects")
+// getMaxBodyBytes returns the route's body limit from [router.RouteSpec.MaxBodyBytes], or [MaxBodySize] if unset.
+func getMaxBodyBytes(r *http.Request) int64 {
+	if maxBodyBytes, ok := router.GetMaxBodyBytesFromContext(r.Context()); ok {
+		return maxBodyBytes
+	}
 
-		case strings.HasPrefix(err.Error(), "json: unknown field"):
-			// json package formats this as: json: unknown field "fieldname"
-			return zero, NewAPIError(http.StatusBadRequest, err.Error())
+	return MaxBodySize
+}
 
-		default:
-			return zero, NewAPIError(http.StatusBadRequest, "Invalid JSON payload")
-		}
+// formatByteSize formats a byte count for error messages (e.g., 1MB, 512KB, 100B).
+func formatByteSize(n int64) string {
+	const (
+		kb = 1024
+		mb = 1024 * kb
+	)
+
+	switch {
+	case n >= mb && n%mb == 0:
+		return fmt.Sprintf("%dMB", n/mb)
+	case n >= kb && n%kb == 0:
+		return fmt.Sprintf("%dKB", n/kb)
+	default:
+		return fmt.Sprintf("%dB", n)
 	}
-
-	return res, nil
 }
 
-// GenerateResponses adds standard error responses to the given responses map.
-func GenerateResponses(responses map[int]router.ResponseSpec) map[int]router.ResponseSpec {
-	if _, exists := responses[http.StatusRequestEntityTooLarge]; !exists {
-		responses[http.StatusRequestEntityTooLarge] = router.ResponseSpec{
-			Description: "Request entity too large",
-			Type:        types.ErrorResponse{},
-			Examples: map[string]any{
-				"Request Entity Too Large": types.ErrorResponse{
-					RequestID: zeroUUID,
-					Message:   fmt.Sprintf("Request body too large (max %dMB)", MaxBodySize/(1024*1024)),
-				},
+// BodyLimitResponse returns the documented 413 response of a route with the given request body limit.
+// Pass it to [router.WithBodyLimitResponse] along with [MaxBodySize], so the limit comes from [router.RouteSpec.MaxBodyBytes].
+func BodyLimitResponse(maxBodyBytes int64) router.ResponseSpec {
+	return router.ResponseSpec{
+		Description: "Request entity too large",
+		Type:        types.ErrorResponse{},
+		Examples: map[string]any{
+			"Request Entity Too Large": types.ErrorResponse{
+				RequestID: zeroUUID,
+				Message:   fmt.Sprintf("Request body too large (max %s)", formatByteSize(maxBodyBytes)),
 			},
-		}
+		},
 	}
+}
 
+// GenerateResponses adds standard error responses to the given responses map.
+// The 413 response is added by the route builder, see [BodyLimitResponse].
+func GenerateResponses(responses map[int]router.ResponseSpec) map[int]router.ResponseSpec {
 	if _, exists := responses[http.StatusInternalServerError]; !exists {
 		responses[http.StatusInternalServerError] = router.ResponseSpec{
 			Description: "Internal Server Error",
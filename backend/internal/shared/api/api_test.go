@@ -0,0 +1,41 @@
+package apicommon
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPServerTimeouts(t *testing.T) {
+	t.Parallel()
+
+	timeouts := HTTPServerTimeouts{
+		ReadHeader: 2 * time.Second,
+		Read:       15 * time.Second,
+		Write:      45 * time.Second,
+		Idle:       time.Minute,
+		Shutdown:   20 * time.Second,
+	}
+
+	s := NewHTTPServer(slog.New(slog.NewTextHandler(io.Discard, nil)), ":0", http.NotFoundHandler(), timeouts)
+
+	tests := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{name: "ReadHeaderTimeout", got: s.server.ReadHeaderTimeout, want: timeouts.ReadHeader},
+		{name: "ReadTimeout", got: s.server.ReadTimeout, want: timeouts.Read},
+		{name: "WriteTimeout", got: s.server.WriteTimeout, want: timeouts.Write},
+		{name: "IdleTimeout", got: s.server.IdleTimeout, want: timeouts.Idle},
+		{name: "shutdownTimeout", got: s.shutdownTimeout, want: timeouts.Shutdown},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
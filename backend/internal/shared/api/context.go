@@ -5,12 +5,15 @@ import (
 	"log/slog"
 )
 
-type contextKey struct{}
+// contextKey is the type of the context keys of this package, the name keeps each key distinct.
+type contextKey struct{ name string }
 
 //nolint:gochecknoglobals // Context keys must be package-level variables
 var (
-	loggerKey    = contextKey{}
-	requestIDKey = contextKey{}
+	loggerKey          = contextKey{name: "logger"}
+	requestIDKey       = contextKey{name: "requestID"}
+	jsonErrorMapperKey = contextKey{name: "jsonErrorMapper"}
+	claimsKey          = contextKey{name: "claims"}
 )
 
 // WithLogger adds a request-scoped logger to the context.
@@ -50,3 +53,32 @@ func GetRequestIDFromContext(ctx context.Context) string {
 
 	return zeroUUID
 }
+
+// WithJSONErrorMapperContext adds the JSON error mapper used by [DecodeJSON] to the context.
+func WithJSONErrorMapperContext(ctx context.Context, mapper JSONErrorMapper) context.Context {
+	return context.WithValue(ctx, jsonErrorMapperKey, mapper)
+}
+
+// GetJSONErrorMapperFromContext retrieves the JSON error mapper from context, or [DefaultJSONErrorMapper] if not set.
+//
+//nolint:ireturn // Returns the JSONErrorMapper interface
+func GetJSONErrorMapperFromContext(ctx context.Context) JSONErrorMapper {
+	if mapper, ok := ctx.Value(jsonErrorMapperKey).(JSONErrorMapper); ok && mapper != nil {
+		return mapper
+	}
+
+	return DefaultJSONErrorMapper{}
+}
+
+// WithClaims adds the claims of an authenticated request to the context.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// GetClaims retrieves the claims stored by [MiddlewareHandler.AuthMiddleware].
+// Returns false if the request was not authenticated.
+func GetClaims(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(Claims)
+
+	return claims, ok
+}
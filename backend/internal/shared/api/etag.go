@@ -0,0 +1,59 @@
+package apicommon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// RespondJSONWithETag sends a JSON response like [RespondJSON] with a strong ETag computed from the encoded body.
+// For GET and HEAD requests whose If-None-Match matches the ETag, it responds with 304 Not Modified and no body.
+// The body is encoded once and used for both the hash and the response.
+func RespondJSONWithETag(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	if data == nil {
+		RespondJSON(w, r, statusCode, nil)
+
+		return
+	}
+
+	body, ok := encodeJSONResponse(w, r, data)
+	if !ok {
+		return
+	}
+
+	etag := computeETag(body)
+	w.Header().Set("ETag", etag)
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	writeJSONResponse(w, r, statusCode, body)
+}
+
+// computeETag returns a strong ETag, the SHA-256 of the body.
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches the ETag.
+// If-None-Match uses weak comparison, so a W/ prefix is ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
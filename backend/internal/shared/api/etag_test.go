@@ -0,0 +1,65 @@
+package apicommon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+func TestRespondJSONWithETag(t *testing.T) {
+	t.Parallel()
+
+	data := types.PingResponse{Message: "Pong", Status: types.PingStatusOK}
+
+	// Compute the ETag of the payload with a plain request first
+	w := httptest.NewRecorder()
+	RespondJSONWithETag(w, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, data)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" || etag[0] != '"' {
+		t.Fatalf("ETag = %q, want a strong ETag", etag)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		wantStatus  int
+		wantBody    bool
+	}{
+		{name: "no If-None-Match", method: http.MethodGet, wantStatus: http.StatusOK, wantBody: true},
+		{name: "matching If-None-Match", method: http.MethodGet, ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "matching in list", method: http.MethodGet, ifNoneMatch: `"other", W/` + etag, wantStatus: http.StatusNotModified},
+		{name: "wildcard", method: http.MethodGet, ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "non-matching If-None-Match", method: http.MethodGet, ifNoneMatch: `"other"`, wantStatus: http.StatusOK, wantBody: true},
+		{name: "matching on POST is ignored", method: http.MethodPost, ifNoneMatch: etag, wantStatus: http.StatusOK, wantBody: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			w := httptest.NewRecorder()
+			RespondJSONWithETag(w, r, http.StatusOK, data)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+
+			if gotBody := w.Body.Len() > 0; gotBody != tt.wantBody {
+				t.Errorf("body present = %v, want %v", gotBody, tt.wantBody)
+			}
+		})
+	}
+}
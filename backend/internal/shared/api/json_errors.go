@@ -0,0 +1,62 @@
+package apicommon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// JSONErrorMapper converts a JSON decoding error into an API error.
+// The error is passed as returned by the decoder, so implementations can inspect it with [errors.As]
+// (e.g., *json.SyntaxError, *json.UnmarshalTypeError, *http.MaxBytesError, *utils.ExtraDataAfterJSONError).
+type JSONErrorMapper interface {
+	MapJSONError(err error) *types.ErrorResponse
+}
+
+// JSONErrorMapperFunc adapts a function to a [JSONErrorMapper].
+type JSONErrorMapperFunc func(err error) *types.ErrorResponse
+
+// MapJSONError calls f(err).
+func (f JSONErrorMapperFunc) MapJSONError(err error) *types.ErrorResponse {
+	return f(err)
+}
+
+// DefaultJSONErrorMapper maps JSON decoding errors to English messages without error codes.
+type DefaultJSONErrorMapper struct{}
+
+// MapJSONError implements [JSONErrorMapper].
+func (DefaultJSONErrorMapper) MapJSONError(err error) *types.ErrorResponse {
+	// FIXME: on Go 1.26 use errors.AsType[...]()
+	var (
+		syntaxError        *json.SyntaxError
+		unmarshalTypeError *json.UnmarshalTypeError
+		maxBytesError      *http.MaxBytesError
+		extraDataError     *utils.ExtraDataAfterJSONError
+	)
+
+	switch {
+	case errors.As(err, &syntaxError):
+		return NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid JSON syntax at position %d", syntaxError.Offset))
+	case errors.As(err, &unmarshalTypeError):
+		return NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid type for field '%s'", unmarshalTypeError.Field))
+	case errors.Is(err, io.EOF):
+		return NewAPIError(http.StatusBadRequest, "Request body is empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewAPIError(http.StatusBadRequest, "Malformed JSON")
+	case errors.As(err, &maxBytesError):
+		return NewAPIError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (max %s)", formatByteSize(maxBytesError.Limit)))
+	case errors.As(err, &extraDataError):
+		return NewAPIError(http.StatusBadRequest, "Request body contains multiple JSON objects")
+	case strings.HasPrefix(err.Error(), "json: unknown field"):
+		// json package formats this as: json: unknown field "fieldname"
+		return NewAPIError(http.StatusBadRequest, err.Error())
+	default:
+		return NewAPIError(http.StatusBadRequest, "Invalid JSON payload")
+	}
+}
@@ -0,0 +1,149 @@
+package apicommon
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+	"http-mqtt-boilerplate/backend/pkg/generate"
+	"http-mqtt-boilerplate/backend/pkg/router"
+)
+
+type testPayload struct {
+	Name string `json:"name"`
+}
+
+func TestDecodeJSONDefaultMapper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "empty body", body: "", wantStatus: http.StatusBadRequest, wantMsg: "Request body is empty"},
+		{name: "syntax error", body: `{"name": }`, wantStatus: http.StatusBadRequest, wantMsg: "Invalid JSON syntax"},
+		{name: "type error", body: `{"name": 1}`, wantStatus: http.StatusBadRequest, wantMsg: "Invalid type for field 'name'"},
+		{name: "too large", body: `{"name": "` + strings.Repeat("a", MaxBodySize) + `"}`, wantStatus: http.StatusRequestEntityTooLarge, wantMsg: "Request body too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			_, err := DecodeJSON[testPayload](r)
+
+			var apiErr *types.ErrorResponse
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("DecodeJSON() error = %v, want *types.ErrorResponse", err)
+			}
+
+			if apiErr.StatusCode != tt.wantStatus || !strings.Contains(apiErr.Message, tt.wantMsg) {
+				t.Errorf("DecodeJSON() error = %d %q, want %d containing %q", apiErr.StatusCode, apiErr.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONCustomMapper(t *testing.T) {
+	t.Parallel()
+
+	mapper := JSONErrorMapperFunc(func(err error) *types.ErrorResponse {
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			return &types.ErrorResponse{StatusCode: http.StatusUnprocessableEntity, Message: "JSON ungültig", Code: "invalid_json"}
+		}
+
+		return DefaultJSONErrorMapper{}.MapJSONError(err)
+	})
+
+	mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), WithJSONErrorMapper(mapper))
+
+	var got *types.ErrorResponse
+
+	handler := mw.LoggerMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, err := DecodeJSON[testPayload](r)
+		if !errors.As(err, &got) {
+			t.Errorf("DecodeJSON() error = %v, want *types.ErrorResponse", err)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": }`)))
+
+	if got == nil || got.StatusCode != http.StatusUnprocessableEntity || got.Code != "invalid_json" || got.Message != "JSON ungültig" {
+		t.Errorf("DecodeJSON() error = %+v, want custom mapped error", got)
+	}
+}
+
+func TestDecodeJSONRouteMaxBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	rb, err := router.NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{})
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	rb.MustPost("/ingest", router.RouteSpec{
+		OperationID:  "ingest",
+		Summary:      "Ingest",
+		Description:  "Ingests a payload",
+		Group:        "Test",
+		MaxBodyBytes: 16,
+		RequestType:  &router.RequestBodySpec{Type: testPayload{}},
+		Handler: ErrorHandler(func(w http.ResponseWriter, r *http.Request) error {
+			if _, err := DecodeJSON[testPayload](r); err != nil {
+				return err
+			}
+
+			RespondJSON(w, r, http.StatusOK, nil)
+
+			return nil
+		}),
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "within limit", body: `{"name": "a"}`, wantStatus: http.StatusOK},
+		{name: "over limit", body: `{"name": "abcdefghijklmnop"}`, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			rb.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusRequestEntityTooLarge && !strings.Contains(rec.Body.String(), "max 16B") {
+				t.Errorf("body = %s, want route-specific limit", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBodyLimitResponse(t *testing.T) {
+	t.Parallel()
+
+	response := BodyLimitResponse(10 * 1024 * 1024)
+
+	example, ok := response.Examples["Request Entity Too Large"].(types.ErrorResponse)
+	if !ok || example.Message != "Request body too large (max 10MB)" {
+		t.Errorf("413 example = %+v, want route-specific limit", example)
+	}
+}
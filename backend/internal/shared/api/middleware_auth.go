@@ -0,0 +1,93 @@
+package apicommon
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+const bearerScheme = "Bearer"
+
+// Claims are the identity attributes of an authenticated request (e.g., decoded JWT claims).
+type Claims map[string]any
+
+// TokenValidator validates a bearer token and returns its claims.
+// Implementations decide the token format (e.g., JWT or opaque tokens looked up in a store).
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (Claims, error)
+}
+
+// TokenValidatorFunc adapts a function to a [TokenValidator].
+type TokenValidatorFunc func(ctx context.Context, token string) (Claims, error)
+
+// ValidateToken calls f(ctx, token).
+func (f TokenValidatorFunc) ValidateToken(ctx context.Context, token string) (Claims, error) {
+	return f(ctx, token)
+}
+
+// AuthMiddleware authenticates requests with a bearer token from the Authorization header.
+// Valid tokens have their claims stored in the request context, retrieve them with [GetClaims].
+// Missing, malformed or invalid tokens are rejected with 401.
+func (m *MiddlewareHandler) AuthMiddleware(validator TokenValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := GetLoggerFromContextOrNil(r.Context())
+			if l == nil {
+				l = m.l
+			}
+
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				respondUnauthorized(w, r, "Missing bearer token")
+
+				return
+			}
+
+			token, ok := parseBearerToken(header)
+			if !ok {
+				l.Warn("malformed authorization header")
+				respondUnauthorized(w, r, "Malformed authorization header")
+
+				return
+			}
+
+			claims, err := validator.ValidateToken(r.Context(), token)
+			if err != nil {
+				l.Warn("invalid bearer token", utils.ErrAttr(err))
+				respondUnauthorized(w, r, "Invalid bearer token")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// parseBearerToken extracts the token from an Authorization header value.
+// The scheme is case-insensitive, the token must be non-empty and contain no spaces.
+func parseBearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", false
+	}
+
+	return token, true
+}
+
+// respondUnauthorized responds with 401 and a bearer challenge.
+func respondUnauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("WWW-Authenticate", bearerScheme)
+	RespondJSON(w, r, http.StatusUnauthorized, &types.ErrorResponse{
+		RequestID: GetRequestIDFromContext(r.Context()),
+		Message:   message,
+	})
+}
@@ -0,0 +1,82 @@
+package apicommon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	validator := TokenValidatorFunc(func(_ context.Context, token string) (Claims, error) {
+		if token != "valid-token" {
+			return nil, errors.New("unknown token")
+		}
+
+		return Claims{"sub": "user-1"}, nil
+	})
+
+	var gotClaims Claims
+
+	handler := mw.AuthMiddleware(validator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := GetClaims(r.Context())
+		if !ok {
+			t.Error("GetClaims() ok = false, want claims in context")
+		}
+
+		gotClaims = claims
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "missing", authorization: "", wantStatus: http.StatusUnauthorized},
+		{name: "wrong scheme", authorization: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "empty token", authorization: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "token with spaces", authorization: "Bearer valid token", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer other-token", wantStatus: http.StatusUnauthorized},
+		{name: "valid token", authorization: "Bearer valid-token", wantStatus: http.StatusOK},
+		{name: "case-insensitive scheme", authorization: "bearer valid-token", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.wantStatus)
+		}
+
+		if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("%s: WWW-Authenticate = %q, want %q", tt.name, rec.Header().Get("WWW-Authenticate"), "Bearer")
+		}
+	}
+
+	if gotClaims["sub"] != "user-1" {
+		t.Errorf("claims = %v, want sub user-1", gotClaims)
+	}
+}
+
+func TestGetClaimsUnauthenticated(t *testing.T) {
+	t.Parallel()
+
+	if claims, ok := GetClaims(context.Background()); ok || claims != nil {
+		t.Errorf("GetClaims() = %v, %v, want nil, false", claims, ok)
+	}
+}
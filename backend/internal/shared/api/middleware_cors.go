@@ -0,0 +1,91 @@
+package apicommon
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const corsWildcard = "*"
+
+// CORSOptions configures [MiddlewareHandler.CORSMiddleware].
+type CORSOptions struct {
+	AllowedOrigins   []string      // AllowedOrigins is a list of exact origins, or "*" to allow any origin
+	AllowedMethods   []string      // AllowedMethods defaults to GET, POST, PUT, PATCH, DELETE when empty
+	AllowedHeaders   []string      // AllowedHeaders defaults to Content-Type and the request ID header when empty
+	AllowCredentials bool          // AllowCredentials allows cookies and auth headers; the origin is echoed instead of "*"
+	MaxAge           time.Duration // MaxAge is how long browsers may cache preflight results (0 = not sent)
+}
+
+// isOriginAllowed reports whether the origin matches the allowed origins list.
+func (o CORSOptions) isOriginAllowed(origin string) bool {
+	return slices.Contains(o.AllowedOrigins, corsWildcard) || slices.Contains(o.AllowedOrigins, origin)
+}
+
+// CORSMiddleware adds CORS headers for allowed origins and short-circuits preflight requests with 204.
+// Requests from disallowed origins are passed through without CORS headers, so browsers block them.
+func (m *MiddlewareHandler) CORSMiddleware(opts CORSOptions) func(http.Handler) http.Handler {
+	allowedMethods := opts.AllowedMethods
+	if len(allowedMethods) == 0 {
+		allowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+
+	allowedHeaders := opts.AllowedHeaders
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = []string{"Content-Type", RequestIDHeader}
+	}
+
+	methods := strings.Join(allowedMethods, ", ")
+	headers := strings.Join(allowedHeaders, ", ")
+	wildcard := slices.Contains(opts.AllowedOrigins, corsWildcard) && !opts.AllowCredentials
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			isPreflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			// Responses differ by origin, so caches must key on it
+			w.Header().Add("Vary", "Origin")
+
+			if origin == "" || !opts.isOriginAllowed(origin) {
+				if isPreflight {
+					w.WriteHeader(http.StatusNoContent)
+
+					return
+				}
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if wildcard {
+				w.Header().Set("Access-Control-Allow-Origin", corsWildcard)
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !isPreflight {
+				w.Header().Set("Access-Control-Expose-Headers", RequestIDHeader)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+
+			if opts.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package apicommon
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		opts            CORSOptions
+		method          string
+		origin          string
+		preflight       bool
+		wantStatus      int
+		wantAllowOrigin string
+		wantCredentials string
+		wantMethods     string
+		wantMaxAge      string
+		wantNextCalled  bool
+	}{
+		{
+			name:            "allowed origin",
+			opts:            CORSOptions{AllowedOrigins: []string{"https://app.example.com"}},
+			method:          http.MethodGet,
+			origin:          "https://app.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://app.example.com",
+			wantNextCalled:  true,
+		},
+		{
+			name:           "disallowed origin",
+			opts:           CORSOptions{AllowedOrigins: []string{"https://app.example.com"}},
+			method:         http.MethodGet,
+			origin:         "https://evil.example.com",
+			wantStatus:     http.StatusOK,
+			wantNextCalled: true,
+		},
+		{
+			name:            "wildcard origin",
+			opts:            CORSOptions{AllowedOrigins: []string{"*"}},
+			method:          http.MethodGet,
+			origin:          "https://any.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "*",
+			wantNextCalled:  true,
+		},
+		{
+			name:            "wildcard origin with credentials echoes origin",
+			opts:            CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:          http.MethodGet,
+			origin:          "https://any.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://any.example.com",
+			wantCredentials: "true",
+			wantNextCalled:  true,
+		},
+		{
+			name:            "preflight",
+			opts:            CORSOptions{AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{http.MethodGet, http.MethodPost}, MaxAge: 10 * time.Minute},
+			method:          http.MethodOptions,
+			origin:          "https://app.example.com",
+			preflight:       true,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://app.example.com",
+			wantMethods:     "GET, POST",
+			wantMaxAge:      "600",
+		},
+		{
+			name:       "preflight from disallowed origin",
+			opts:       CORSOptions{AllowedOrigins: []string{"https://app.example.com"}},
+			method:     http.MethodOptions,
+			origin:     "https://evil.example.com",
+			preflight:  true,
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			nextCalled := false
+			handler := mw.CORSMiddleware(tt.opts)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				nextCalled = true
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(tt.method, "/", nil)
+			r.Header.Set("Origin", tt.origin)
+
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if nextCalled != tt.wantNextCalled {
+				t.Errorf("next called = %v, want %v", nextCalled, tt.wantNextCalled)
+			}
+
+			checks := map[string]string{
+				"Access-Control-Allow-Origin":      tt.wantAllowOrigin,
+				"Access-Control-Allow-Credentials": tt.wantCredentials,
+				"Access-Control-Allow-Methods":     tt.wantMethods,
+				"Access-Control-Max-Age":           tt.wantMaxAge,
+			}
+			for header, want := range checks {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
@@ -37,12 +37,18 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the underlying ResponseWriter, so [http.ResponseController] can reach optional interfaces.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // wrapResponseWriter wraps the ResponseWriter to capture status code.
 func wrapResponseWriter(w http.ResponseWriter) *responseWriter {
 	return &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 }
 
-// LoggerMiddleware adds a request-scoped logger to the context and logs requests.
+// LoggerMiddleware adds a request-scoped logger and the configured [JSONErrorMapper] to the context and logs requests.
+// Each request produces a single access log line with request_id, method, path, status, bytes and duration.
 func (m *MiddlewareHandler) LoggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestID := GetRequestIDFromContext(r.Context())
@@ -58,8 +64,9 @@ func (m *MiddlewareHandler) LoggerMiddleware(next http.Handler) http.Handler {
 			slog.Int64("request_bytes", r.ContentLength),
 		)
 
-		// Store logger and request ID in context
+		// Store logger and JSON error mapper in context
 		ctx := WithLogger(r.Context(), reqLogger)
+		ctx = WithJSONErrorMapperContext(ctx, m.jsonErrorMapper)
 
 		wrapped := wrapResponseWriter(w)
 
@@ -73,7 +80,7 @@ func (m *MiddlewareHandler) LoggerMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start)
 		reqLogger.Info("request completed",
 			slog.Int("status", wrapped.statusCode),
-			slog.Int64("response_bytes", wrapped.bytesWritten),
+			slog.Int64("bytes", wrapped.bytesWritten),
 			slog.Duration("duration", duration),
 		)
 	})
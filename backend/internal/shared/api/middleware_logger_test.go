@@ -0,0 +1,99 @@
+package apicommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+func TestLoggerMiddlewareAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus float64
+		wantBytes  float64
+	}{
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+				_, _ = w.Write([]byte("short and stout"))
+			},
+			wantStatus: http.StatusTeapot,
+			wantBytes:  15,
+		},
+		{
+			name: "implicit status on write",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantStatus: http.StatusOK,
+			wantBytes:  2,
+		},
+		{
+			name: "status is not overwritten",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.WriteHeader(http.StatusOK)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "no write",
+			handler:    func(http.ResponseWriter, *http.Request) {},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: utils.SlogReplacer}))
+			mw := NewMiddlewareHandler(l)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/teams", nil)
+			r.Header.Set(RequestIDHeader, testRequestID)
+
+			w := httptest.NewRecorder()
+			mw.RequestIDMiddleware(mw.LoggerMiddleware(tt.handler)).ServeHTTP(w, r)
+
+			if w.Code != int(tt.wantStatus) {
+				t.Errorf("response status = %d, want %v", w.Code, tt.wantStatus)
+			}
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("expected a single JSON access log line, got %q: %v", buf.String(), err)
+			}
+
+			want := map[string]any{
+				"msg":        "request completed",
+				"request_id": testRequestID,
+				"method":     http.MethodPost,
+				"path":       "/api/teams",
+				"status":     tt.wantStatus,
+				"bytes":      tt.wantBytes,
+			}
+			for key, value := range want {
+				if entry[key] != value {
+					t.Errorf("log %s = %v, want %v", key, entry[key], value)
+				}
+			}
+
+			// SlogReplacer formats durations as strings
+			if _, ok := entry["duration"].(string); !ok {
+				t.Errorf("log duration = %v, want a formatted string", entry["duration"])
+			}
+		})
+	}
+}
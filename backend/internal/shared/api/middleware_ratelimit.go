@@ -0,0 +1,192 @@
+package apicommon
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+const (
+	defaultRateLimitRate            = 10 // tokens per second
+	defaultRateLimitBurst           = 20
+	defaultRateLimitCleanupInterval = time.Minute
+)
+
+// KeyFunc returns the key a request is rate limited by.
+type KeyFunc func(r *http.Request) string
+
+// RateLimitByIP keys requests by the client IP of the connection.
+func RateLimitByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// RateLimitByHeader keys requests by the value of a header (e.g., an API key), falling back to the client IP.
+func RateLimitByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		if value := r.Header.Get(name); value != "" {
+			return name + ":" + value
+		}
+
+		return RateLimitByIP(r)
+	}
+}
+
+// RateLimitOptions configures [MiddlewareHandler.RateLimitMiddleware].
+type RateLimitOptions struct {
+	Rate            float64       // Rate is the number of requests per second refilled per key (default 10)
+	Burst           int           // Burst is the bucket size, the number of requests allowed at once (default 20)
+	KeyFunc         KeyFunc       // KeyFunc returns the rate limit key of a request (default [RateLimitByIP])
+	CleanupInterval time.Duration // CleanupInterval is how often idle buckets are removed (default 1m)
+}
+
+// tokenBucket is the state of a single key.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimitStore is an in-memory token bucket store.
+// Idle buckets are removed by [rateLimitStore.runCleanup], once per cleanup interval.
+type rateLimitStore struct {
+	rate            float64
+	burst           float64
+	cleanupInterval time.Duration
+	now             func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// newRateLimitStore creates a store from the options, applying the defaults. now is the clock of the buckets.
+func newRateLimitStore(opts RateLimitOptions, now func() time.Time) *rateLimitStore {
+	store := &rateLimitStore{
+		rate:            float64(defaultRateLimitRate),
+		burst:           float64(defaultRateLimitBurst),
+		cleanupInterval: defaultRateLimitCleanupInterval,
+		now:             now,
+		buckets:         make(map[string]*tokenBucket),
+	}
+
+	if opts.Rate > 0 {
+		store.rate = opts.Rate
+	}
+
+	if opts.Burst > 0 {
+		store.burst = float64(opts.Burst)
+	}
+
+	if opts.CleanupInterval > 0 {
+		store.cleanupInterval = opts.CleanupInterval
+	}
+
+	return store
+}
+
+// take removes a token from the key's bucket.
+// It returns false and the time until a token is available if the bucket is empty.
+func (s *rateLimitStore) take(key string) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: s.burst, last: now}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(s.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*s.rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / s.rate * float64(time.Second))
+	}
+
+	bucket.tokens--
+
+	return true, 0
+}
+
+// runCleanup removes idle buckets every cleanup interval until ctx is done.
+func (s *rateLimitStore) runCleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.cleanup()
+		}
+	}
+}
+
+// cleanup removes buckets that have refilled completely, they are equivalent to a new bucket.
+func (s *rateLimitStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	for key, bucket := range s.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*s.rate >= s.burst {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// RateLimitMiddleware limits requests per key with a token bucket.
+// Requests over the limit get a 429 with a Retry-After header.
+// Idle buckets are removed in the background until ctx is done, pass the server's lifetime context.
+func (m *MiddlewareHandler) RateLimitMiddleware(ctx context.Context, opts RateLimitOptions) func(http.Handler) http.Handler {
+	store := newRateLimitStore(opts, time.Now)
+	go store.runCleanup(ctx)
+
+	keyFunc := opts.KeyFunc
+	if keyFunc == nil {
+		keyFunc = RateLimitByIP
+	}
+
+	return m.rateLimit(store, keyFunc)
+}
+
+// rateLimit limits requests with the buckets of store, keyed by keyFunc.
+func (m *MiddlewareHandler) rateLimit(store *rateLimitStore, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter := store.take(keyFunc(r))
+			if allowed {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			l := GetLoggerFromContextOrNil(r.Context())
+			if l == nil {
+				l = m.l
+			}
+
+			l.Warn("rate limit exceeded", slog.Duration("retry_after", retryAfter))
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			RespondJSON(w, r, http.StatusTooManyRequests, &types.ErrorResponse{
+				RequestID: GetRequestIDFromContext(r.Context()),
+				Message:   "Too Many Requests",
+			})
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package apicommon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	store := newRateLimitStore(RateLimitOptions{Rate: 1, Burst: 3}, clock.Now)
+
+	handler := mw.rateLimit(store, RateLimitByHeader("X-API-Key"))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(apiKey string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-API-Key", apiKey)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	// Exhaust the bucket
+	for i := range 3 {
+		if w := do("alice"); w.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+
+	w := do("alice")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want %q", got, "1")
+	}
+
+	// Other keys have their own bucket
+	if w := do("bob"); w.Code != http.StatusOK {
+		t.Errorf("other key status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Recover after the refill window
+	clock.Advance(time.Second)
+
+	if w := do("alice"); w.Code != http.StatusOK {
+		t.Errorf("status after refill = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if w := do("alice"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("status after using the refilled token = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestRateLimitStoreCleanup(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := newRateLimitStore(RateLimitOptions{Rate: 1, Burst: 2}, clock.Now)
+
+	store.take("a")
+	store.take("b")
+	store.take("b")
+
+	// Buckets that have not refilled yet are kept
+	clock.Advance(500 * time.Millisecond)
+	store.cleanup()
+
+	if len(store.buckets) != 2 {
+		t.Fatalf("buckets = %d, want 2 before refill", len(store.buckets))
+	}
+
+	// "a" has refilled, "b" still misses a token
+	clock.Advance(time.Second)
+	store.cleanup()
+
+	if _, ok := store.buckets["b"]; !ok || len(store.buckets) != 1 {
+		t.Errorf("buckets = %v, want only b after cleanup", slices.Collect(maps.Keys(store.buckets)))
+	}
+}
+
+func TestRateLimitStoreRunCleanup(t *testing.T) {
+	t.Parallel()
+
+	synctest.Test(t, func(t *testing.T) {
+		store := newRateLimitStore(RateLimitOptions{Rate: 1, Burst: 1, CleanupInterval: time.Minute}, time.Now)
+		store.take("a")
+
+		ctx, cancel := context.WithCancel(t.Context())
+
+		done := make(chan struct{})
+		go func() {
+			store.runCleanup(ctx)
+			close(done)
+		}()
+
+		// The bucket refills after a second, it is removed on the first tick
+		time.Sleep(time.Minute)
+		synctest.Wait()
+
+		store.mu.Lock()
+		buckets := len(store.buckets)
+		store.mu.Unlock()
+
+		if buckets != 0 {
+			t.Errorf("buckets = %d, want 0 after a cleanup tick", buckets)
+		}
+
+		// The cleanup stops with the context
+		cancel()
+		<-done
+	})
+}
@@ -1,38 +1,63 @@
 package apicommon
 
 import (
-	"http-mqtt-boilerplate/backend/internal/shared/types"
+	"errors"
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
 )
 
-// RecoveryMiddleware recovers from panics and logs them.
+// RecoveryMiddleware recovers from panics, logs them with the request ID and responds with a JSON 500.
+// Panics with [http.ErrAbortHandler] are re-raised so the server aborts the response as intended.
 func (m *MiddlewareHandler) RecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		//nolint:contextcheck // Context accessed from closure is safe in defer recover
 		defer func() {
-			if err := recover(); err != nil {
-				requestID := GetRequestIDFromContext(r.Context())
-
-				l := GetLoggerFromContextOrNil(r.Context())
-				if l == nil {
-					l = m.l
-				}
-
-				l.Error("panic recovered",
-					slog.Any("error", err),
-					slog.String("stack", string(debug.Stack())),
-				)
-
-				// Respond with a generic error message to avoid leaking internal details
-				RespondJSON(w, r, http.StatusInternalServerError, &types.ErrorResponse{
-					RequestID: requestID,
-					Message:   "Internal Server Error",
-				})
+			err := recover()
+			if err == nil {
+				return
+			}
+
+			if abortErr, ok := err.(error); ok && errors.Is(abortErr, http.ErrAbortHandler) {
+				panic(err)
+			}
+
+			requestID := recoveredRequestID(w, r)
+
+			l := GetLoggerFromContextOrNil(r.Context())
+			if l == nil {
+				l = m.l.With(slog.String("request_id", requestID))
 			}
+
+			l.Error("panic recovered",
+				slog.Any("error", err),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			// Respond with a generic error message to avoid leaking internal details
+			RespondJSON(w, r, http.StatusInternalServerError, &types.ErrorResponse{
+				RequestID: requestID,
+				Message:   "Internal Server Error",
+			})
 		}()
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// recoveredRequestID returns the request ID of a panicking request.
+// The recovery middleware usually runs before [MiddlewareHandler.RequestIDMiddleware], so the request ID
+// is not in its context, but it is available in the response header set by the inner middleware.
+func recoveredRequestID(w http.ResponseWriter, r *http.Request) string {
+	if requestID := GetRequestIDFromContext(r.Context()); requestID != zeroUUID {
+		return requestID
+	}
+
+	if requestID := w.Header().Get(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+
+	return zeroUUID
+}
@@ -0,0 +1,72 @@
+package apicommon
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+
+	mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	panicking := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	})
+
+	// Same order as the servers: recovery wraps request ID and logger
+	handler := mw.RecoveryMiddleware(mw.RequestIDMiddleware(mw.LoggerMiddleware(panicking)))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(RequestIDHeader, testRequestID)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var resp types.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.RequestID != testRequestID {
+		t.Errorf("RequestID = %q, want %q", resp.RequestID, testRequestID)
+	}
+
+	if resp.Message != "Internal Server Error" {
+		t.Errorf("Message = %q, want %q", resp.Message, "Internal Server Error")
+	}
+}
+
+func TestRecoveryMiddlewareAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	handler := mw.RecoveryMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, http.ErrAbortHandler) {
+			t.Errorf("recovered %v, want http.ErrAbortHandler to be re-raised", err)
+		}
+	}()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -8,12 +8,15 @@ import (
 	"github.com/google/uuid"
 )
 
-// RequestIDMiddleware extracts the request ID from the request header or generates a new one
-// if it's not present and stores it in the request context.
+// maxRequestIDLength is the longest incoming request ID that is considered, the longest UUID form is the URN one.
+const maxRequestIDLength = 45
+
+// RequestIDMiddleware propagates a valid incoming request ID (a UUID) or generates a new one
+// if it's absent or malformed. The request ID is echoed in the response header and stored in the request context.
 func (m *MiddlewareHandler) RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Get or generate request ID
-		requestID := r.Header.Get(RequestIDHeader)
+		requestID := parseRequestID(r.Header.Get(RequestIDHeader))
 		if requestID == "" {
 			reqID, err := uuid.NewV7()
 			if err != nil {
@@ -42,3 +45,17 @@ func (m *MiddlewareHandler) RequestIDMiddleware(next http.Handler) http.Handler
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// parseRequestID returns the canonical form of an incoming request ID, or an empty string if it is not a valid UUID.
+func parseRequestID(raw string) string {
+	if raw == "" || len(raw) > maxRequestIDLength {
+		return ""
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil || id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
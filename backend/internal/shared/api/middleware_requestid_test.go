@@ -0,0 +1,74 @@
+package apicommon
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const testRequestID = "0190b7c4-8f6e-7c3a-9d2b-4a5e6f708192"
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		wantID string // Empty means a newly generated ID is expected
+	}{
+		{name: "present valid", header: testRequestID, wantID: testRequestID},
+		{name: "present valid uppercase is canonicalized", header: strings.ToUpper(testRequestID), wantID: testRequestID},
+		{name: "present invalid", header: "not-a-uuid"},
+		{name: "present too long", header: testRequestID + strings.Repeat("0", 64)},
+		{name: "present nil uuid", header: zeroUUID},
+		{name: "absent", header: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			var ctxID string
+
+			handler := mw.RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				ctxID = GetRequestIDFromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(RequestIDHeader, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			headerID := w.Header().Get(RequestIDHeader)
+			if headerID != ctxID {
+				t.Errorf("response header ID %q != context ID %q", headerID, ctxID)
+			}
+
+			if tt.wantID != "" {
+				if ctxID != tt.wantID {
+					t.Errorf("request ID = %q, want %q", ctxID, tt.wantID)
+				}
+
+				return
+			}
+
+			if ctxID == tt.header {
+				t.Errorf("request ID = %q, want a newly generated ID", ctxID)
+			}
+
+			if _, err := uuid.Parse(ctxID); err != nil {
+				t.Errorf("generated request ID %q is not a UUID: %v", ctxID, err)
+			}
+		})
+	}
+}
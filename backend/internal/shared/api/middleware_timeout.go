@@ -0,0 +1,127 @@
+package apicommon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+// timeoutWriter guards the underlying ResponseWriter so the handler cannot write after the timeout response.
+// Headers are kept in a separate map until the handler writes, so they do not race with the timeout response.
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+// Header returns the handler's header map.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader writes the status code unless the request has timed out.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+
+	tw.writeHeaderLocked(code)
+}
+
+// Write writes the body unless the request has timed out.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+
+	return tw.w.Write(b)
+}
+
+// writeHeaderLocked copies the handler's headers and writes the status code. tw.mu must be held.
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	maps.Copy(tw.w.Header(), tw.header)
+	tw.wroteHeader = true
+	tw.w.WriteHeader(code)
+}
+
+// TimeoutMiddleware cancels the request context after d and responds with a JSON 503 if the handler has
+// not written a response by then. The handler receives the deadline context, so DB queries can cancel.
+// Writes after the timeout fail with [http.ErrHandlerTimeout].
+func (m *MiddlewareHandler) TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, header: make(http.Header)}
+			done := make(chan struct{})
+			panicChan := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicChan <- p
+					}
+				}()
+
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicChan:
+				// Re-raise in the request goroutine so the recovery middleware handles it
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				// A handler that only set headers gets the implicit 200, like with a plain ResponseWriter
+				if !tw.wroteHeader {
+					tw.writeHeaderLocked(http.StatusOK)
+				}
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				tw.timedOut = true
+
+				l := GetLoggerFromContextOrNil(r.Context())
+				if l == nil {
+					l = m.l
+				}
+
+				l.Warn("request timed out", slog.Duration("timeout", d), slog.Bool("headers_sent", tw.wroteHeader))
+
+				// Nothing can be done if the handler already started the response
+				if tw.wroteHeader {
+					return
+				}
+
+				RespondJSON(w, r, http.StatusServiceUnavailable, &types.ErrorResponse{
+					RequestID: GetRequestIDFromContext(r.Context()),
+					Message:   fmt.Sprintf("Request timed out after %s", d),
+				})
+			}
+		})
+	}
+}
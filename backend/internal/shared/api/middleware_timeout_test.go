@@ -0,0 +1,100 @@
+package apicommon
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sleep      time.Duration
+		wantStatus int
+	}{
+		{name: "handler finishes in time", sleep: 0, wantStatus: http.StatusOK},
+		{name: "handler exceeds deadline", sleep: time.Second, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			ctxCancelled := make(chan struct{})
+			slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tt.sleep):
+				case <-r.Context().Done():
+					close(ctxCancelled)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := mw.RequestIDMiddleware(mw.TimeoutMiddleware(50 * time.Millisecond)(slow))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(RequestIDHeader, testRequestID)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusServiceUnavailable {
+				return
+			}
+
+			var resp types.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if resp.RequestID != testRequestID {
+				t.Errorf("RequestID = %q, want %q", resp.RequestID, testRequestID)
+			}
+
+			select {
+			case <-ctxCancelled:
+			case <-time.After(time.Second):
+				t.Error("handler context was not cancelled")
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareHeadersWithoutWrite(t *testing.T) {
+	t.Parallel()
+
+	mw := NewMiddlewareHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The handler sets a header and returns without writing a status or body
+	handler := mw.TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", "/api/team/1")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := w.Header().Get("Location"); got != "/api/team/1" {
+		t.Errorf("Location = %q, want %q", got, "/api/team/1")
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,67 @@
+package apicommon
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+// routingMethods are the methods probed to build the Allow header of a 405 response.
+//
+//nolint:gochecknoglobals // Read-only method list
+var routingMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// NotFoundHandler responds with a JSON [types.ErrorResponse] for unknown paths.
+// Wire it with the router's NotFound, chi's default response is plain text.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	respondRoutingError(w, r, http.StatusNotFound)
+}
+
+// MethodNotAllowedHandler responds with a JSON [types.ErrorResponse] for known paths requested with an unsupported method.
+// Wire it with the router's MethodNotAllowed, chi's default response is plain text.
+// The Allow header lists the methods registered for the path.
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	if allowed := allowedMethods(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+	}
+
+	respondRoutingError(w, r, http.StatusMethodNotAllowed)
+}
+
+// allowedMethods returns the methods the router matches for the request path.
+// chi only passes them to its default handler, so the path is matched again for each method.
+func allowedMethods(r *http.Request) []string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return nil
+	}
+
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	var allowed []string
+
+	for _, method := range routingMethods {
+		if rctx.Routes.Match(chi.NewRouteContext(), method, path) {
+			allowed = append(allowed, method)
+		}
+	}
+
+	return allowed
+}
+
+// respondRoutingError responds with the status text as message and the request ID, if any.
+func respondRoutingError(w http.ResponseWriter, r *http.Request, statusCode int) {
+	RespondJSON(w, r, statusCode, &types.ErrorResponse{
+		RequestID: GetRequestIDFromContext(r.Context()),
+		Message:   http.StatusText(statusCode),
+	})
+}
@@ -0,0 +1,106 @@
+package apicommon
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+	"http-mqtt-boilerplate/backend/pkg/generate"
+	"http-mqtt-boilerplate/backend/pkg/router"
+)
+
+func TestRoutingErrorHandlers(t *testing.T) {
+	t.Parallel()
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	rb, err := router.NewRouteBuilder(l, &generate.NoopCollector{})
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	rb.Use(NewMiddlewareHandler(l).RequestIDMiddleware)
+	rb.Router().NotFound(NotFoundHandler)
+	rb.Router().MethodNotAllowed(MethodNotAllowedHandler)
+
+	rb.MustGet("/ping", router.RouteSpec{
+		OperationID: "ping",
+		Summary:     "Ping",
+		Description: "Ping the server",
+		Group:       "Core",
+		Handler:     func(http.ResponseWriter, *http.Request) {},
+	})
+
+	teamIDParam := map[string]router.ParameterSpec{
+		"teamID": {In: router.ParameterInPath, Description: "ID of the team", Required: true, Type: new(string)},
+	}
+
+	rb.Route("/team", func(rb *router.RouteBuilder) {
+		rb.MustGet("/{teamID}", router.RouteSpec{
+			OperationID: "getTeam",
+			Summary:     "Get a team",
+			Description: "Get a team by its ID",
+			Group:       "Team",
+			Parameters:  teamIDParam,
+			Handler:     func(http.ResponseWriter, *http.Request) {},
+		})
+		rb.MustDelete("/{teamID}", router.RouteSpec{
+			OperationID: "deleteTeam",
+			Summary:     "Delete a team",
+			Description: "Delete a team by its ID",
+			Group:       "Team",
+			Parameters:  teamIDParam,
+			Handler:     func(http.ResponseWriter, *http.Request) {},
+		})
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		wantStatus  int
+		wantMessage string
+		wantAllow   string
+	}{
+		{name: "unknown path", method: http.MethodGet, path: "/missing", wantStatus: http.StatusNotFound, wantMessage: "Not Found"},
+		{name: "wrong method", method: http.MethodPost, path: "/ping", wantStatus: http.StatusMethodNotAllowed, wantMessage: "Method Not Allowed", wantAllow: "GET"},
+		{name: "wrong method in sub-router", method: http.MethodPost, path: "/team/123", wantStatus: http.StatusMethodNotAllowed, wantMessage: "Method Not Allowed", wantAllow: "GET, DELETE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r.Header.Set(RequestIDHeader, testRequestID)
+
+			w := httptest.NewRecorder()
+			rb.Router().ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+
+			var body types.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body is not a JSON error response: %v\n%s", err, w.Body.String())
+			}
+
+			if body.Message != tt.wantMessage || body.RequestID != testRequestID {
+				t.Errorf("body = %+v, want message %q and request ID %s", body, tt.wantMessage, testRequestID)
+			}
+		})
+	}
+}
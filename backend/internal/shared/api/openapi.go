@@ -0,0 +1,63 @@
+package apicommon
+
+import (
+	"fmt"
+	"net/http"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+
+	"github.com/oasdiff/yaml"
+)
+
+// SpecHandler serves a generated OpenAPI spec as YAML and JSON.
+// Responses carry an ETag and must be revalidated, so clients always see the contract of the running build.
+type SpecHandler struct {
+	yaml     []byte
+	json     []byte
+	yamlETag string
+	jsonETag string
+}
+
+// NewSpecHandler creates a spec handler from a YAML OpenAPI spec, the JSON form is converted once up front.
+func NewSpecHandler(spec []byte) (*SpecHandler, error) {
+	jsonSpec, err := yaml.YAMLToJSON(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert OpenAPI spec to JSON: %w", err)
+	}
+
+	return &SpecHandler{
+		yaml:     spec,
+		json:     jsonSpec,
+		yamlETag: computeETag(spec),
+		jsonETag: computeETag(jsonSpec),
+	}, nil
+}
+
+// ServeYAML serves the spec as application/yaml.
+func (h *SpecHandler) ServeYAML(w http.ResponseWriter, r *http.Request) {
+	serveSpec(w, r, contentTypeYAML, h.yamlETag, h.yaml)
+}
+
+// ServeJSON serves the spec as application/json.
+func (h *SpecHandler) ServeJSON(w http.ResponseWriter, r *http.Request) {
+	serveSpec(w, r, "application/json", h.jsonETag, h.json)
+}
+
+// serveSpec writes the spec body, or 304 Not Modified when the client's copy is current.
+func serveSpec(w http.ResponseWriter, r *http.Request, contentType, etag string, body []byte) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(body); err != nil {
+		GetLoggerFromContext(r.Context()).Error("failed to write OpenAPI spec", utils.ErrAttr(err))
+	}
+}
@@ -0,0 +1,85 @@
+package apicommon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const testSpec = `openapi: 3.1.0
+info:
+  title: Local API
+  version: 1.0.0
+paths:
+  /livez:
+    get:
+      operationId: liveness
+      responses:
+        "200":
+          description: The server is alive
+`
+
+func TestSpecHandler(t *testing.T) {
+	t.Parallel()
+
+	h, err := NewSpecHandler([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("NewSpecHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		handler         http.HandlerFunc
+		wantContentType string
+	}{
+		{name: "yaml", handler: h.ServeYAML, wantContentType: contentTypeYAML},
+		{name: "json", handler: h.ServeJSON, wantContentType: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(http.MethodGet, "/openapi."+tt.name, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+
+			if got := w.Header().Get("Cache-Control"); got != "no-cache" {
+				t.Errorf("Cache-Control = %q, want no-cache", got)
+			}
+
+			doc, err := openapi3.NewLoader().LoadFromData(w.Body.Bytes())
+			if err != nil {
+				t.Fatalf("served spec does not parse: %v", err)
+			}
+
+			if doc.Info == nil || doc.Info.Title == "" || doc.Paths.Len() == 0 {
+				t.Errorf("served spec = %+v, want info and paths", doc.Info)
+			}
+
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("ETag header missing")
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/openapi."+tt.name, nil)
+			r.Header.Set("If-None-Match", etag)
+
+			w = httptest.NewRecorder()
+			tt.handler(w, r)
+
+			if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+				t.Errorf("revalidation status = %d with %d bytes, want %d with no body", w.Code, w.Body.Len(), http.StatusNotModified)
+			}
+		})
+	}
+}
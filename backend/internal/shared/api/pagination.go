@@ -0,0 +1,86 @@
+package apicommon
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+
+	"http-mqtt-boilerplate/backend/pkg/router"
+)
+
+const (
+	DefaultPageLimit = 20  // DefaultPageLimit is used when the limit query parameter is missing
+	MaxPageLimit     = 100 // MaxPageLimit is the largest accepted limit query parameter
+
+	PageLimitParam  = "limit"
+	PageCursorParam = "cursor"
+)
+
+// Page is a cursor-paginated list response.
+//
+// The generator does not support generic types, so documented responses declare a concrete struct
+// with the same fields in a types package (e.g., localtypes.UserPage) and convert the page to it:
+// `localtypes.UserPage(NewPage(users, limit, cursorOf))`.
+type Page[T any] struct {
+	// Items in this page
+	Items []T `json:"items"`
+	// Cursor to request the next page, null on the last page
+	NextCursor *string `json:"nextCursor"`
+	// Whether more items are available
+	HasMore bool `json:"hasMore"`
+}
+
+// NewPage builds a page from up to limit+1 items fetched by the caller.
+// The extra item only signals that more items exist, it is dropped and cursorOf the last kept item
+// becomes the next cursor.
+func NewPage[T any](items []T, limit int, cursorOf func(T) string) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	if len(items) <= limit {
+		return Page[T]{Items: items}
+	}
+
+	items = items[:limit]
+	nextCursor := cursorOf(items[len(items)-1])
+
+	return Page[T]{Items: items, NextCursor: &nextCursor, HasMore: true}
+}
+
+// ParsePageParams reads the limit and cursor query parameters.
+// The limit defaults to [DefaultPageLimit] and must be between 1 and [MaxPageLimit], otherwise a 400 API error is returned.
+func ParsePageParams(r *http.Request) (int, string, error) {
+	limit, err := QueryParam(r, PageLimitParam, DefaultPageLimit)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if limit < 1 || limit > MaxPageLimit {
+		return 0, "", NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid value for query parameter '%s': must be between 1 and %d", PageLimitParam, MaxPageLimit))
+	}
+
+	return limit, r.URL.Query().Get(PageCursorParam), nil
+}
+
+// PageParameters documents the limit and cursor query parameters read by [ParsePageParams].
+// Other parameters of the route are merged in.
+func PageParameters(params map[string]router.ParameterSpec) map[string]router.ParameterSpec {
+	result := map[string]router.ParameterSpec{
+		PageLimitParam: {
+			In:          router.ParameterInQuery,
+			Description: fmt.Sprintf("Maximum number of items to return (1-%d)", MaxPageLimit),
+			Type:        new(int),
+			Default:     DefaultPageLimit,
+		},
+		PageCursorParam: {
+			In:          router.ParameterInQuery,
+			Description: "Cursor returned as nextCursor by the previous page",
+			Type:        new(string),
+		},
+	}
+
+	maps.Copy(result, params)
+
+	return result
+}
@@ -0,0 +1,137 @@
+package apicommon
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+	"http-mqtt-boilerplate/backend/pkg/generate"
+	"http-mqtt-boilerplate/backend/pkg/router"
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+func TestParsePageParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantCursor string
+		wantErr    bool
+	}{
+		{name: "defaults", query: "", wantLimit: DefaultPageLimit},
+		{name: "limit and cursor", query: "?limit=5&cursor=abc", wantLimit: 5, wantCursor: "abc"},
+		{name: "max limit", query: "?limit=" + strconv.Itoa(MaxPageLimit), wantLimit: MaxPageLimit},
+		{name: "limit above max", query: "?limit=" + strconv.Itoa(MaxPageLimit+1), wantErr: true},
+		{name: "zero limit", query: "?limit=0", wantErr: true},
+		{name: "non-numeric limit", query: "?limit=ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+			limit, cursor, err := ParsePageParams(r)
+			if tt.wantErr {
+				var apiErr *types.ErrorResponse
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+					t.Errorf("ParsePageParams() error = %v, want 400 *types.ErrorResponse", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParsePageParams() unexpected error: %v", err)
+			}
+
+			if limit != tt.wantLimit || cursor != tt.wantCursor {
+				t.Errorf("ParsePageParams() = (%d, %q), want (%d, %q)", limit, cursor, tt.wantLimit, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	t.Parallel()
+
+	cursorOf := strconv.Itoa
+
+	tests := []struct {
+		name           string
+		items          []int
+		limit          int
+		wantItems      int
+		wantNextCursor string
+		wantHasMore    bool
+	}{
+		{name: "empty", items: nil, limit: 2, wantItems: 0},
+		{name: "last page", items: []int{1, 2}, limit: 2, wantItems: 2},
+		{name: "more items", items: []int{1, 2, 3}, limit: 2, wantItems: 2, wantNextCursor: "2", wantHasMore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page := NewPage(tt.items, tt.limit, cursorOf)
+
+			if len(page.Items) != tt.wantItems || page.HasMore != tt.wantHasMore {
+				t.Errorf("NewPage() = %d items, hasMore %v, want %d items, hasMore %v", len(page.Items), page.HasMore, tt.wantItems, tt.wantHasMore)
+			}
+
+			gotCursor := ""
+			if page.NextCursor != nil {
+				gotCursor = *page.NextCursor
+			}
+
+			if gotCursor != tt.wantNextCursor {
+				t.Errorf("NewPage() nextCursor = %q, want %q", gotCursor, tt.wantNextCursor)
+			}
+
+			// Items must always encode as an array, never null
+			data, err := utils.ToJSON(page)
+			if err != nil {
+				t.Fatalf("failed to encode page: %v", err)
+			}
+
+			if tt.wantItems == 0 && string(data) != `{"items":[],"nextCursor":null,"hasMore":false}` {
+				t.Errorf("empty page JSON = %s", data)
+			}
+		})
+	}
+}
+
+func TestPageParametersRegister(t *testing.T) {
+	t.Parallel()
+
+	rb, err := router.NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{})
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	err = rb.Get("/teams/{teamID}/users", router.RouteSpec{
+		OperationID: "listTeamUsers",
+		Summary:     "List team users",
+		Description: "List the users of a team, one page at a time",
+		Group:       "Team",
+		Handler:     func(http.ResponseWriter, *http.Request) {},
+		Parameters: PageParameters(map[string]router.ParameterSpec{
+			"teamID": {In: router.ParameterInPath, Description: "ID of the team", Required: true, Type: new(string)},
+		}),
+		Responses: GenerateResponses(map[int]router.ResponseSpec{
+			200: {Description: "A page of users", Type: types.PingResponse{}},
+		}),
+	})
+	if err != nil {
+		t.Errorf("Get() with PageParameters error = %v", err)
+	}
+}
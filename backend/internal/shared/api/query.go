@@ -0,0 +1,100 @@
+package apicommon
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// QueryParamType is the set of types that can be read with [QueryParam] and [CookieParam].
+type QueryParamType interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// QueryParam reads a query parameter and converts it to T.
+// Returns def if the parameter is missing or empty, and a 400 API error if it cannot be converted.
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func QueryParam[T QueryParamType](r *http.Request, name string, def T) (T, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	var value T
+
+	if err := setFromString(reflect.ValueOf(&value).Elem(), raw); err != nil {
+		return def, NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid value for query parameter '%s': %s", name, err))
+	}
+
+	return value, nil
+}
+
+// CookieParam reads a cookie and converts its value to T.
+// Returns def if the cookie is missing or empty, and a 400 API error if it cannot be converted.
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func CookieParam[T QueryParamType](r *http.Request, name string, def T) (T, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil || cookie.Value == "" {
+		return def, nil
+	}
+
+	var value T
+
+	if err := setFromString(reflect.ValueOf(&value).Elem(), cookie.Value); err != nil {
+		return def, NewAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid value for cookie '%s': %s", name, err))
+	}
+
+	return value, nil
+}
+
+// setFromString parses raw according to the kind of v and stores the result in v.
+func setFromString(v reflect.Value, raw string) error {
+	//nolint:exhaustive // Only the kinds allowed by QueryParamType are handled
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected a boolean, got %q", raw)
+		}
+
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", raw)
+		}
+
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a non-negative integer, got %q", raw)
+		}
+
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", raw)
+		}
+
+		v.SetFloat(f)
+
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
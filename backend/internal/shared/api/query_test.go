@@ -0,0 +1,141 @@
+package apicommon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+func TestQueryParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    string
+		def      int
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:     "missing uses default",
+			query:    "",
+			def:      20,
+			expected: 20,
+		},
+		{
+			name:     "empty uses default",
+			query:    "limit=",
+			def:      20,
+			expected: 20,
+		},
+		{
+			name:     "valid value",
+			query:    "limit=50",
+			def:      20,
+			expected: 50,
+		},
+		{
+			name:    "invalid value",
+			query:   "limit=abc",
+			def:     20,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil)
+
+			got, err := QueryParam(r, "limit", tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				var apiErr *types.ErrorResponse
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+					t.Errorf("QueryParam() error = %v, want 400 API error", err)
+				}
+
+				return
+			}
+
+			if got != tt.expected {
+				t.Errorf("QueryParam() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryParamTypes(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/items?cursor=abc&active=true&ratio=0.5&offset=7", nil)
+
+	if got, err := QueryParam(r, "cursor", ""); err != nil || got != "abc" {
+		t.Errorf("QueryParam(cursor) = %q, %v", got, err)
+	}
+
+	if got, err := QueryParam(r, "active", false); err != nil || !got {
+		t.Errorf("QueryParam(active) = %v, %v", got, err)
+	}
+
+	if got, err := QueryParam(r, "ratio", 0.0); err != nil || got != 0.5 {
+		t.Errorf("QueryParam(ratio) = %v, %v", got, err)
+	}
+
+	if got, err := QueryParam(r, "offset", uint(0)); err != nil || got != 7 {
+		t.Errorf("QueryParam(offset) = %v, %v", got, err)
+	}
+}
+
+func TestCookieParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cookie   *http.Cookie
+		def      int
+		expected int
+		wantErr  bool
+	}{
+		{name: "missing uses default", cookie: nil, def: 20, expected: 20},
+		{name: "empty uses default", cookie: &http.Cookie{Name: "limit", Value: ""}, def: 20, expected: 20},
+		{name: "valid value", cookie: &http.Cookie{Name: "limit", Value: "50"}, def: 20, expected: 50},
+		{name: "invalid value", cookie: &http.Cookie{Name: "limit", Value: "abc"}, def: 20, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tt.cookie != nil {
+				r.AddCookie(tt.cookie)
+			}
+
+			got, err := CookieParam(r, "limit", tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CookieParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				var apiErr *types.ErrorResponse
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+					t.Errorf("CookieParam() error = %v, want 400 API error", err)
+				}
+
+				return
+			}
+
+			if got != tt.expected {
+				t.Errorf("CookieParam() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package apicommon
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oasdiff/yaml"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+const contentTypeYAML = "application/yaml"
+
+// Respond sends a response with given status code, negotiated from the Accept header.
+// YAML is sent when application/yaml or text/yaml is preferred over application/json,
+// otherwise it behaves like [RespondJSON]. YAML bodies follow the JSON field names.
+// Every response varies by Accept, so caches never serve a JSON body to a YAML client or the other way around.
+func Respond(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	w.Header().Add("Vary", "Accept")
+
+	if data == nil || !prefersYAML(r.Header.Get("Accept")) {
+		RespondJSON(w, r, statusCode, data)
+
+		return
+	}
+
+	body, ok := encodeJSONResponse(w, r, data)
+	if !ok {
+		return
+	}
+
+	yamlBody, err := yaml.JSONToYAML(body)
+	if err != nil {
+		GetLoggerFromContext(r.Context()).Error("failed to convert response to YAML", utils.ErrAttr(err))
+		writeJSONResponse(w, r, http.StatusInternalServerError, []byte(`{"message":"Internal Server Error"}`))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeYAML)
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(yamlBody); err != nil {
+		GetLoggerFromContext(r.Context()).Error("failed to write YAML response", utils.ErrAttr(err))
+	}
+}
+
+// prefersYAML reports whether the Accept header ranks a YAML media type at least as high as application/json.
+// Wildcards are not considered, so JSON stays the default for generic clients.
+func prefersYAML(accept string) bool {
+	var yamlQ, jsonQ float64
+
+	for mediaRange := range strings.SplitSeq(accept, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		q := 1.0
+
+		for param := range strings.SplitSeq(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		switch mediaType {
+		case contentTypeYAML, "text/yaml", "application/x-yaml":
+			yamlQ = max(yamlQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	return yamlQ > 0 && yamlQ >= jsonQ
+}
@@ -0,0 +1,95 @@
+package apicommon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+
+	"github.com/oasdiff/yaml"
+)
+
+func TestRespondNegotiation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		accept          string
+		wantContentType string
+	}{
+		{name: "no accept", accept: "", wantContentType: "application/json"},
+		{name: "json", accept: "application/json", wantContentType: "application/json"},
+		{name: "wildcard", accept: "*/*", wantContentType: "application/json"},
+		{name: "application yaml", accept: "application/yaml", wantContentType: contentTypeYAML},
+		{name: "text yaml", accept: "text/yaml", wantContentType: contentTypeYAML},
+		{name: "yaml preferred by q", accept: "application/json;q=0.5, application/yaml", wantContentType: contentTypeYAML},
+		{name: "json preferred by q", accept: "application/json, application/yaml;q=0.5", wantContentType: "application/json"},
+		{name: "yaml refused", accept: "application/yaml;q=0", wantContentType: "application/json"},
+	}
+
+	data := types.PingResponse{Message: "Pong", Status: types.PingStatusOK}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			w := httptest.NewRecorder()
+			Respond(w, r, http.StatusOK, data)
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+
+			if got := w.Header().Values("Vary"); len(got) != 1 || got[0] != "Accept" {
+				t.Errorf("Vary = %q, want Accept", got)
+			}
+
+			// Both JSON and YAML bodies must be valid YAML with the JSON field names
+			var got map[string]any
+			if err := yaml.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("body is not valid YAML: %v\n%s", err, w.Body.String())
+			}
+
+			if got["message"] != "Pong" || got["status"] != string(types.PingStatusOK) {
+				t.Errorf("body = %v, want message Pong and status OK", got)
+			}
+		})
+	}
+}
+
+func TestRespondJSONPretty(t *testing.T) {
+	t.Parallel()
+
+	data := types.PingResponse{Message: "Pong", Status: types.PingStatusOK}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "compact by default", url: "/", want: "{\"message\":\"Pong\",\"status\":\"OK\"}\n"},
+		{name: "pretty", url: "/?pretty=1", want: "{\n  \"message\": \"Pong\",\n  \"status\": \"OK\"\n}\n"},
+		{name: "pretty true", url: "/?pretty=true", want: "{\n  \"message\": \"Pong\",\n  \"status\": \"OK\"\n}\n"},
+		{name: "pretty disabled", url: "/?pretty=0", want: "{\"message\":\"Pong\",\"status\":\"OK\"}\n"},
+		{name: "pretty invalid", url: "/?pretty=yes", want: "{\"message\":\"Pong\",\"status\":\"OK\"}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			RespondJSON(w, httptest.NewRequest(http.MethodGet, tt.url, nil), http.StatusOK, data)
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
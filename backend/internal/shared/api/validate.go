@@ -0,0 +1,135 @@
+package apicommon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+// validate is the validator shared by all requests, it caches the parsed tags of each struct type.
+//
+//nolint:gochecknoglobals // Shared validator, safe for concurrent use
+var validate = newValidator()
+
+// newValidator creates a validator that reports fields by their JSON name.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	return v
+}
+
+// NewValidationError creates a validation error response with field-level errors keyed by JSON field path.
+func NewValidationError(fieldErrors map[string]string) *types.ErrorResponse {
+	return &types.ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Validation failed",
+		Errors:     fieldErrors,
+	}
+}
+
+// DecodeAndValidateJSON decodes JSON from request body like [DecodeJSON] and then validates the result
+// against its `validate` struct tags (go-playground/validator syntax). T must be a struct.
+// Field errors are returned as a [NewValidationError] keyed by JSON field path (e.g., "address.city", "tags[0]").
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func DecodeAndValidateJSON[T any](r *http.Request) (T, error) {
+	res, err := DecodeJSON[T](r)
+	if err != nil {
+		return res, err
+	}
+
+	return res, validateStruct(res)
+}
+
+// DecodeAndValidatePatchJSON applies the JSON body of a patch request (see [router.RequestBodySpec.Patch]) onto current,
+// then validates the result like [DecodeAndValidateJSON]. Fields absent from the body are left unchanged,
+// as are fields set to null unless they are pointers, slices or maps (which are cleared).
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func DecodeAndValidatePatchJSON[T any](r *http.Request, current T) (T, error) {
+	if err := decodeJSONInto(r, &current); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return current, validateStruct(current)
+}
+
+// validateStruct validates a struct against its `validate` struct tags, returning field errors as a [NewValidationError].
+func validateStruct(s any) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return fmt.Errorf("failed to validate request body: %w", err)
+	}
+
+	fieldErrors := make(map[string]string, len(validationErrors))
+
+	for _, fieldErr := range validationErrors {
+		// The namespace starts with the struct type name, the rest is the JSON field path
+		_, path, _ := strings.Cut(fieldErr.Namespace(), ".")
+		fieldErrors[path] = validationMessage(fieldErr)
+	}
+
+	return NewValidationError(fieldErrors)
+}
+
+// validationMessage describes a failed validation rule for API clients.
+func validationMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s%s", param, sizeUnit(fieldErr.Kind()))
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s%s", param, sizeUnit(fieldErr.Kind()))
+	case "len":
+		return fmt.Sprintf("must be exactly %s%s", param, sizeUnit(fieldErr.Kind()))
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "unique":
+		return "must contain unique items"
+	case "alpha", "alphanum", "numeric", "number":
+		return fmt.Sprintf("must match the %s format", fieldErr.Tag())
+	default:
+		if param != "" {
+			return fmt.Sprintf("failed the %s=%s rule", fieldErr.Tag(), param)
+		}
+
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
+
+// sizeUnit returns the unit of a size constraint, lengths for strings and collections and values for numbers.
+func sizeUnit(kind reflect.Kind) string {
+	//nolint:exhaustive // Other kinds are compared by value
+	switch kind {
+	case reflect.String:
+		return " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,177 @@
+package apicommon
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/internal/shared/types"
+)
+
+type testAddress struct {
+	City    string `json:"city"    validate:"required"`
+	ZipCode string `json:"zipCode" validate:"omitempty,len=5,numeric"`
+}
+
+type testValidatedPayload struct {
+	Name     string        `json:"name"               validate:"required,min=3,max=10"`
+	Age      int           `json:"age"                validate:"gte=18,lte=120"`
+	Role     string        `json:"role,omitempty"     validate:"omitempty,oneof=admin user"`
+	Tags     []string      `json:"tags,omitempty"     validate:"max=3,unique,dive,alphanum"`
+	Address  testAddress   `json:"address"`
+	Previous []testAddress `json:"previous,omitempty" validate:"dive"`
+	Nickname *string       `json:"nickname,omitempty" validate:"omitempty,min=2"`
+}
+
+func TestDecodeAndValidateJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantErrors map[string]string
+	}{
+		{
+			name: "valid",
+			body: `{"name": "alice", "age": 30, "role": "admin", "tags": ["a1"], "address": {"city": "Athens", "zipCode": "10431"}}`,
+		},
+		{
+			name: "required",
+			body: `{"age": 30, "address": {"city": "Athens"}}`,
+			wantErrors: map[string]string{
+				"name": "is required",
+			},
+		},
+		{
+			name: "min and max",
+			body: `{"name": "al", "age": 12, "address": {"city": "Athens"}, "tags": ["a", "b", "c", "d"], "nickname": "x"}`,
+			wantErrors: map[string]string{
+				"name":     "must be at least 3 characters long",
+				"age":      "must be at least 18",
+				"tags":     "must be at most 3 items",
+				"nickname": "must be at least 2 characters long",
+			},
+		},
+		{
+			name: "oneof and dive",
+			body: `{"name": "alice", "age": 30, "role": "root", "tags": ["ok", "not ok"], "address": {"city": "Athens"}}`,
+			wantErrors: map[string]string{
+				"role":    "must be one of: admin, user",
+				"tags[1]": "must match the alphanum format",
+			},
+		},
+		{
+			name: "unique",
+			body: `{"name": "alice", "age": 30, "tags": ["a1", "b2", "a1"], "address": {"city": "Athens"}}`,
+			wantErrors: map[string]string{
+				"tags": "must contain unique items",
+			},
+		},
+		{
+			name: "nested struct",
+			body: `{"name": "alice", "age": 30, "address": {"zipCode": "123"}, "previous": [{"city": "Patras", "zipCode": "abcde"}]}`,
+			wantErrors: map[string]string{
+				"address.city":        "is required",
+				"address.zipCode":     "must be exactly 5 characters long",
+				"previous[0].zipCode": "must match the numeric format",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			_, err := DecodeAndValidateJSON[testValidatedPayload](r)
+			if tt.wantErrors == nil {
+				if err != nil {
+					t.Fatalf("DecodeAndValidateJSON() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var apiErr *types.ErrorResponse
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("DecodeAndValidateJSON() error = %v, want *types.ErrorResponse", err)
+			}
+
+			if apiErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("DecodeAndValidateJSON() status = %d, want %d", apiErr.StatusCode, http.StatusBadRequest)
+			}
+
+			if !maps.Equal(apiErr.Errors, tt.wantErrors) {
+				t.Errorf("DecodeAndValidateJSON() errors = %v, want %v", apiErr.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestDecodeAndValidatePatchJSON(t *testing.T) {
+	t.Parallel()
+
+	current := testValidatedPayload{Name: "alice", Age: 30, Role: "user", Address: testAddress{City: "Athens"}}
+
+	tests := []struct {
+		name       string
+		body       string
+		want       testValidatedPayload
+		wantErrors map[string]string
+	}{
+		{
+			name: "empty patch",
+			body: `{}`,
+			want: current,
+		},
+		{
+			name: "absent fields unchanged",
+			body: `{"age": 40, "address": {"city": "Patras"}}`,
+			want: testValidatedPayload{Name: "alice", Age: 40, Role: "user", Address: testAddress{City: "Patras"}},
+		},
+		{
+			name: "null unchanged",
+			body: `{"name": null}`,
+			want: current,
+		},
+		{
+			name: "invalid value",
+			body: `{"name": "", "age": 12}`,
+			wantErrors: map[string]string{
+				"name": "is required",
+				"age":  "must be at least 18",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+
+			got, err := DecodeAndValidatePatchJSON(r, current)
+			if tt.wantErrors == nil {
+				if err != nil {
+					t.Fatalf("DecodeAndValidatePatchJSON() unexpected error: %v", err)
+				}
+
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("DecodeAndValidatePatchJSON() = %+v, want %+v", got, tt.want)
+				}
+
+				return
+			}
+
+			var apiErr *types.ErrorResponse
+			if !errors.As(err, &apiErr) || !maps.Equal(apiErr.Errors, tt.wantErrors) {
+				t.Errorf("DecodeAndValidatePatchJSON() error = %v, want errors %v", err, tt.wantErrors)
+			}
+		})
+	}
+}
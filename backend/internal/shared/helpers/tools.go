@@ -16,7 +16,8 @@ import (
 func GetLogger(config *config.Config) *slog.Logger {
 	logOptions := slog.HandlerOptions{
 		Level:       config.LogLevel,
-		ReplaceAttr: utils.SlogReplacer,
+		AddSource:   config.LogSource,
+		ReplaceAttr: utils.NewSlogRedactor(config.LogRedactKeys, utils.SlogReplacer),
 	}
 
 	var logHandler slog.Handler = slog.NewJSONHandler(config.LogOutput, &logOptions)
@@ -41,7 +42,23 @@ func RunMigrations(l *slog.Logger, connString string, dirs ...string) error {
 		return fmt.Errorf("failed to migrate: %w", err)
 	}
 
-	l.Info("database migrations completed successfully")
+	// Log the resulting migration status for visibility at startup
+	statuses, err := mig.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	pending := 0
+
+	for _, status := range statuses {
+		if !status.Applied {
+			pending++
+		}
+
+		l.Debug("migration status", slog.String("file", status.FileName), slog.Bool("applied", status.Applied))
+	}
+
+	l.Info("database migrations completed successfully", slog.Int("total", len(statuses)), slog.Int("pending", pending))
 
 	return nil
 }
@@ -13,6 +13,8 @@ type ErrorResponse struct {
 	RequestID string `json:"requestID"`
 	// High-level error message
 	Message string `json:"message"`
+	// Machine-readable error code (optional)
+	Code string `json:"code,omitempty"`
 	// Field-level validation errors
 	Errors map[string]string `json:"errors,omitempty"`
 }
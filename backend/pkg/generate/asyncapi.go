@@ -0,0 +1,317 @@
+package generate
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+)
+
+const (
+	asyncAPIVersion            = "2.6.0"
+	asyncAPIMQTTBindingVersion = "0.1.0"
+	asyncAPISchemaFormat       = "application/vnd.oai.openapi;version=3.0.0"
+)
+
+// AsyncAPISpec is the root of an AsyncAPI 2.6 document.
+type AsyncAPISpec struct {
+	AsyncAPI   string                     `json:"asyncapi"`
+	Info       AsyncAPIInfo               `json:"info"`
+	Channels   map[string]AsyncAPIChannel `json:"channels"`
+	Components AsyncAPIComponents         `json:"components"`
+}
+
+// AsyncAPIInfo contains the AsyncAPI document metadata.
+type AsyncAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// AsyncAPIChannel describes a single topic.
+// In AsyncAPI 2.x, "subscribe" describes messages the application publishes (clients subscribe to them),
+// and "publish" describes messages the application consumes (clients publish them).
+type AsyncAPIChannel struct {
+	Description string                       `json:"description,omitempty"`
+	Parameters  map[string]AsyncAPIParameter `json:"parameters,omitempty"`
+	Subscribe   *AsyncAPIOperation           `json:"subscribe,omitempty"`
+	Publish     *AsyncAPIOperation           `json:"publish,omitempty"`
+}
+
+// AsyncAPIParameter describes a channel parameter.
+type AsyncAPIParameter struct {
+	Description string              `json:"description,omitempty"`
+	Schema      *openapi3.SchemaRef `json:"schema"`
+}
+
+// AsyncAPIOperation describes a publish or subscribe operation on a channel.
+type AsyncAPIOperation struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Tags        []AsyncAPITag             `json:"tags,omitempty"`
+	Bindings    AsyncAPIOperationBindings `json:"bindings"`
+	Message     AsyncAPIRef               `json:"message"`
+}
+
+// AsyncAPITag is a tag attached to an operation.
+type AsyncAPITag struct {
+	Name string `json:"name"`
+}
+
+// AsyncAPIOperationBindings contains protocol specific operation bindings.
+type AsyncAPIOperationBindings struct {
+	MQTT AsyncAPIMQTTOperationBinding `json:"mqtt"`
+}
+
+// AsyncAPIMQTTOperationBinding contains the MQTT operation binding.
+type AsyncAPIMQTTOperationBinding struct {
+	QoS            byte   `json:"qos"`
+	Retain         bool   `json:"retain"`
+	BindingVersion string `json:"bindingVersion"`
+}
+
+// AsyncAPIRef is a JSON reference.
+type AsyncAPIRef struct {
+	Ref string `json:"$ref"`
+}
+
+// AsyncAPIMessage describes a message payload.
+type AsyncAPIMessage struct {
+	Name         string            `json:"name"`
+	Title        string            `json:"title,omitempty"`
+	Summary      string            `json:"summary,omitempty"`
+	ContentType  string            `json:"contentType"`
+	SchemaFormat string            `json:"schemaFormat"`
+	Payload      AsyncAPIRef       `json:"payload"`
+	Examples     []AsyncAPIExample `json:"examples,omitempty"`
+}
+
+// AsyncAPIExample is a named message example.
+type AsyncAPIExample struct {
+	Name    string `json:"name"`
+	Payload any    `json:"payload"`
+}
+
+// AsyncAPIComponents holds reusable messages and schemas.
+type AsyncAPIComponents struct {
+	Messages map[string]AsyncAPIMessage `json:"messages,omitempty"`
+	Schemas  openapi3.Schemas           `json:"schemas,omitempty"`
+}
+
+// mqttOperation is the common view of a publication or subscription used to build channels.
+type mqttOperation struct {
+	operationID     string
+	topic           string
+	topicParameters []MQTTTopicParameter
+	summary         string
+	description     string
+	group           string
+	qos             byte
+	retained        bool
+	typeName        string
+	examples        map[string]any
+}
+
+// generateAsyncAPISpec generates an AsyncAPI specification from the MQTT operations in the documentation.
+func generateAsyncAPISpec(doc *APIDocumentation) (*AsyncAPISpec, error) {
+	spec := &AsyncAPISpec{
+		AsyncAPI: asyncAPIVersion,
+		Info: AsyncAPIInfo{
+			Title:       doc.Info.Title,
+			Version:     doc.Info.Version,
+			Description: doc.Info.Description,
+		},
+		Channels: make(map[string]AsyncAPIChannel),
+		Components: AsyncAPIComponents{
+			Messages: make(map[string]AsyncAPIMessage),
+			Schemas:  make(openapi3.Schemas),
+		},
+	}
+
+	// Build schemas only for types marked as used by MQTT
+	for name, typeInfo := range doc.Types {
+		if !typeInfo.UsedByMQTT {
+			continue
+		}
+
+		schema, err := toOpenAPISchema(typeInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build schema for %s: %w", name, err)
+		}
+
+		spec.Components.Schemas[name] = &openapi3.SchemaRef{Value: schema}
+	}
+
+	// Application publications are "subscribe" operations from the client's perspective
+	for _, pub := range doc.MQTTPublications {
+		op := mqttOperation{
+			operationID:     pub.OperationID,
+			topic:           pub.Topic,
+			topicParameters: pub.TopicParameters,
+			summary:         pub.Summary,
+			description:     pub.Description,
+			group:           pub.Group,
+			qos:             pub.QoS,
+			retained:        pub.Retained,
+			typeName:        pub.TypeName,
+			examples:        pub.Examples,
+		}
+		if err := addAsyncAPIOperation(spec, op, doc.Types, true); err != nil {
+			return nil, fmt.Errorf("failed to add publication %s: %w", pub.OperationID, err)
+		}
+	}
+
+	// Application subscriptions are "publish" operations from the client's perspective
+	for _, sub := range doc.MQTTSubscriptions {
+		op := mqttOperation{
+			operationID:     sub.OperationID,
+			topic:           sub.Topic,
+			topicParameters: sub.TopicParameters,
+			summary:         sub.Summary,
+			description:     sub.Description,
+			group:           sub.Group,
+			qos:             sub.QoS,
+			typeName:        sub.TypeName,
+			examples:        sub.Examples,
+		}
+		if err := addAsyncAPIOperation(spec, op, doc.Types, false); err != nil {
+			return nil, fmt.Errorf("failed to add subscription %s: %w", sub.OperationID, err)
+		}
+	}
+
+	return spec, nil
+}
+
+// addAsyncAPIOperation adds an MQTT operation and its message to the spec.
+// isPublication selects whether the operation is placed under "subscribe" (application publishes)
+// or "publish" (application subscribes).
+func addAsyncAPIOperation(spec *AsyncAPISpec, op mqttOperation, types map[string]*TypeInfo, isPublication bool) error {
+	if _, ok := types[op.typeName]; !ok {
+		return fmt.Errorf("message type %s not found in types map", op.typeName)
+	}
+
+	channel := spec.Channels[op.topic]
+
+	parameters, err := buildAsyncAPIParameters(op.topicParameters, types)
+	if err != nil {
+		return err
+	}
+
+	channel.Parameters = parameters
+
+	spec.Components.Messages[op.operationID] = AsyncAPIMessage{
+		Name:         op.typeName,
+		Title:        op.typeName,
+		Summary:      op.summary,
+		ContentType:  ContentTypeJSON,
+		SchemaFormat: asyncAPISchemaFormat,
+		Payload:      AsyncAPIRef{Ref: "#/components/schemas/" + op.typeName},
+		Examples:     convertExamplesToAsyncAPI(op.examples),
+	}
+
+	operation := &AsyncAPIOperation{
+		OperationID: op.operationID,
+		Summary:     op.summary,
+		Description: op.description,
+		Bindings: AsyncAPIOperationBindings{
+			MQTT: AsyncAPIMQTTOperationBinding{
+				QoS:            op.qos,
+				Retain:         op.retained,
+				BindingVersion: asyncAPIMQTTBindingVersion,
+			},
+		},
+		Message: AsyncAPIRef{Ref: "#/components/messages/" + op.operationID},
+	}
+
+	if op.group != "" {
+		operation.Tags = []AsyncAPITag{{Name: op.group}}
+	}
+
+	if isPublication {
+		if channel.Subscribe != nil {
+			return fmt.Errorf("topic %s already has a publication (operationID: %s)", op.topic, channel.Subscribe.OperationID)
+		}
+
+		channel.Subscribe = operation
+	} else {
+		if channel.Publish != nil {
+			return fmt.Errorf("topic %s already has a subscription (operationID: %s)", op.topic, channel.Publish.OperationID)
+		}
+
+		channel.Publish = operation
+	}
+
+	spec.Channels[op.topic] = channel
+
+	return nil
+}
+
+// buildAsyncAPIParameters converts topic parameters to AsyncAPI channel parameters.
+func buildAsyncAPIParameters(params []MQTTTopicParameter, types map[string]*TypeInfo) (map[string]AsyncAPIParameter, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]AsyncAPIParameter, len(params))
+
+	for _, param := range params {
+		var schema *openapi3.SchemaRef
+
+		switch {
+		case types[param.TypeName] != nil:
+			schema = createSchemaRef(param.TypeName)
+		case isPrimitiveType(param.TypeName):
+			schema = &openapi3.SchemaRef{
+				Value: &openapi3.Schema{Type: &openapi3.Types{param.TypeName}},
+			}
+		default:
+			return nil, fmt.Errorf("topic parameter %s has unregistered type %s (not found in types map and not a valid primitive type)", param.Name, param.TypeName)
+		}
+
+		result[param.Name] = AsyncAPIParameter{
+			Description: param.Description,
+			Schema:      schema,
+		}
+	}
+
+	return result, nil
+}
+
+// convertExamplesToAsyncAPI converts examples map to AsyncAPI format, sorted by name for deterministic output.
+func convertExamplesToAsyncAPI(examples map[string]any) []AsyncAPIExample {
+	if len(examples) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	result := make([]AsyncAPIExample, 0, len(names))
+	for _, name := range names {
+		result = append(result, AsyncAPIExample{Name: name, Payload: examples[name]})
+	}
+
+	return result
+}
+
+// writeAsyncAPIYAML writes the AsyncAPI specification to a YAML file.
+func (g *OpenAPICollector) writeAsyncAPIYAML(filename string) error {
+	spec, err := generateAsyncAPISpec(g.getDocumentation())
+	if err != nil {
+		return fmt.Errorf("failed to generate spec: %w", err)
+	}
+
+	yamlData, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, yamlData, 0600)
+}
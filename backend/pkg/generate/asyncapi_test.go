@@ -0,0 +1,96 @@
+package generate
+
+import (
+	"testing"
+)
+
+func TestGenerateAsyncAPISpec(t *testing.T) {
+	t.Parallel()
+
+	doc := &APIDocumentation{
+		Info: APIInfo{Title: "Test", Version: "1.0.0"},
+		Types: map[string]*TypeInfo{
+			"Temperature": {Name: "Temperature", Kind: TypeKindObject, UsedByMQTT: true},
+			"Command":     {Name: "Command", Kind: TypeKindObject, UsedByMQTT: true},
+			"User":        {Name: "User", Kind: TypeKindObject, UsedByHTTP: true},
+		},
+		MQTTPublications: map[string]*MQTTPublicationInfo{
+			"publishTemperature": {
+				OperationID:     "publishTemperature",
+				Topic:           "devices/{deviceID}/temperature",
+				TopicParameters: []MQTTTopicParameter{{Name: "deviceID", Description: "Device ID", TypeName: "string"}},
+				Group:           "Devices",
+				QoS:             1,
+				Retained:        true,
+				TypeName:        "Temperature",
+				Examples:        map[string]any{"b": 2, "a": 1},
+			},
+		},
+		MQTTSubscriptions: map[string]*MQTTSubscriptionInfo{
+			"deviceCommand": {
+				OperationID:     "deviceCommand",
+				Topic:           "devices/{deviceID}/temperature",
+				TopicParameters: []MQTTTopicParameter{{Name: "deviceID", Description: "Device ID", TypeName: "string"}},
+				QoS:             2,
+				TypeName:        "Command",
+			},
+		},
+	}
+
+	spec, err := generateAsyncAPISpec(doc)
+	if err != nil {
+		t.Fatalf("generateAsyncAPISpec() error = %v", err)
+	}
+
+	if len(spec.Components.Schemas) != 2 || spec.Components.Schemas["User"] != nil {
+		t.Errorf("schemas = %v, want only MQTT types", spec.Components.Schemas)
+	}
+
+	channel, ok := spec.Channels["devices/{deviceID}/temperature"]
+	if !ok {
+		t.Fatalf("channel not found in %v", spec.Channels)
+	}
+
+	if _, ok := channel.Parameters["deviceID"]; !ok {
+		t.Errorf("channel parameters = %v, want deviceID", channel.Parameters)
+	}
+
+	if channel.Subscribe == nil || channel.Subscribe.OperationID != "publishTemperature" {
+		t.Fatalf("subscribe operation = %+v, want publishTemperature", channel.Subscribe)
+	}
+
+	if got := channel.Subscribe.Bindings.MQTT; got.QoS != 1 || !got.Retain {
+		t.Errorf("subscribe mqtt binding = %+v, want qos 1 retained", got)
+	}
+
+	if channel.Publish == nil || channel.Publish.OperationID != "deviceCommand" || channel.Publish.Bindings.MQTT.QoS != 2 {
+		t.Errorf("publish operation = %+v, want deviceCommand with qos 2", channel.Publish)
+	}
+
+	examples := spec.Components.Messages["publishTemperature"].Examples
+	if len(examples) != 2 || examples[0].Name != "a" || examples[1].Name != "b" {
+		t.Errorf("examples = %+v, want sorted by name", examples)
+	}
+}
+
+func TestGenerateAsyncAPISpecUnknownParameterType(t *testing.T) {
+	t.Parallel()
+
+	doc := &APIDocumentation{
+		Types: map[string]*TypeInfo{
+			"Temperature": {Name: "Temperature", Kind: TypeKindObject, UsedByMQTT: true},
+		},
+		MQTTPublications: map[string]*MQTTPublicationInfo{
+			"publishTemperature": {
+				OperationID:     "publishTemperature",
+				Topic:           "devices/{deviceID}/temperature",
+				TopicParameters: []MQTTTopicParameter{{Name: "deviceID", TypeName: "DeviceID"}},
+				TypeName:        "Temperature",
+			},
+		},
+	}
+
+	if _, err := generateAsyncAPISpec(doc); err == nil {
+		t.Error("generateAsyncAPISpec() expected error for unregistered parameter type")
+	}
+}
@@ -5,6 +5,8 @@ package generate
 // Go source representations with full metadata.
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -12,7 +14,10 @@ import (
 	"log/slog"
 	"os"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/coder/guts"
 	"github.com/coder/guts/bindings"
@@ -27,32 +32,98 @@ var (
 	ErrFieldSkipped = errors.New("field skipped")
 )
 
-// externalType represents a type from outside the types directories.
-type externalType struct {
-	fullPath      string                         // e.g., "http-mqtt-boilerplate/backend/pkg/utils.URL"
-	openAPIFormat string                         // OpenAPI format (e.g., FormatURI)
-	gutsOverride  func() bindings.ExpressionType // Custom guts type override function
+// ExternalTypeFormat describes how a type from outside the types directories is represented.
+type ExternalTypeFormat struct {
+	Type    string // OpenAPI type: "string", "integer", "number" or "boolean"
+	Format  string // OpenAPI format (e.g., FormatURI), optional
+	Pattern string // Regex pattern the value must match, optional
+	Note    string // Note appended to the field description (e.g., units), optional
 }
 
-// getExternalTypeMappings returns the mappings for external types.
+// getExternalTypeMappings returns the built-in mappings for external types, keyed by full type path.
 // These types are not defined in the types directories but need special handling.
-func getExternalTypeMappings() []externalType {
-	return []externalType{
-		{
-			fullPath:      "time.Time",
-			openAPIFormat: FormatDateTime,
-			gutsOverride: func() bindings.ExpressionType {
-				return new(bindings.KeywordString)
-			},
+func getExternalTypeMappings() map[string]ExternalTypeFormat {
+	return map[string]ExternalTypeFormat{
+		"time.Time": {
+			Type:   typeString,
+			Format: FormatDateTime,
 		},
-		{
-			fullPath:      "http-mqtt-boilerplate/backend/pkg/utils.URL",
-			openAPIFormat: FormatURI,
-			gutsOverride: func() bindings.ExpressionType {
-				return new(bindings.KeywordString)
-			},
+		"time.Duration": {
+			Type:   typeInteger,
+			Format: "int64",
+			Note:   "Duration in nanoseconds.",
 		},
+		"http-mqtt-boilerplate/backend/pkg/utils.URL": {
+			Type:   typeString,
+			Format: FormatURI,
+		},
+		"http-mqtt-boilerplate/backend/pkg/utils.UUID": {
+			Type:   typeString,
+			Format: FormatUUID,
+		},
+	}
+}
+
+// buildExternalTypeFormats merges the custom external type formats with the built-in defaults.
+// Custom entries take precedence over built-in ones.
+func buildExternalTypeFormats(custom map[string]ExternalTypeFormat) (map[string]ExternalTypeFormat, error) {
+	formats := getExternalTypeMappings()
+
+	for fullPath, format := range custom {
+		if !strings.Contains(fullPath, ".") {
+			return nil, fmt.Errorf("external type %q must be a full type path (e.g., github.com/google/uuid.UUID)", fullPath)
+		}
+
+		if _, err := gutsKeywordForType(format.Type); err != nil {
+			return nil, fmt.Errorf("external type %s: %w", fullPath, err)
+		}
+
+		if format.Pattern != "" {
+			if format.Type != typeString {
+				return nil, fmt.Errorf("external type %s: pattern is only supported for string types", fullPath)
+			}
+
+			if _, err := regexp.Compile(format.Pattern); err != nil {
+				return nil, fmt.Errorf("external type %s: invalid pattern: %w", fullPath, err)
+			}
+		}
+
+		formats[fullPath] = format
+	}
+
+	return formats, nil
+}
+
+// gutsKeywordForType returns the TypeScript keyword matching the given OpenAPI primitive type.
+func gutsKeywordForType(openAPIType string) (bindings.LiteralKeyword, error) {
+	switch openAPIType {
+	case typeString:
+		return bindings.KeywordString, nil
+	case typeInteger, typeNumber:
+		return bindings.KeywordNumber, nil
+	case typeBoolean:
+		return bindings.KeywordBoolean, nil
+	default:
+		return "", fmt.Errorf("unsupported OpenAPI type %q (must be string, integer, number or boolean)", openAPIType)
+	}
+}
+
+// buildGutsOverrides builds guts type overrides so external types render as their TypeScript primitives.
+func buildGutsOverrides(formats map[string]ExternalTypeFormat) (map[string]guts.TypeOverride, error) {
+	overrides := make(map[string]guts.TypeOverride, len(formats))
+
+	for fullPath, format := range formats {
+		keyword, err := gutsKeywordForType(format.Type)
+		if err != nil {
+			return nil, fmt.Errorf("external type %s: %w", fullPath, err)
+		}
+
+		overrides[fullPath] = func() bindings.ExpressionType {
+			return new(keyword)
+		}
 	}
+
+	return overrides, nil
 }
 
 // isNilOrNilPointer checks if a value is nil or a nil pointer.
@@ -98,8 +169,16 @@ func isZeroValueStruct(value any) bool {
 const (
 	FormatDateTime = "date-time"
 	FormatURI      = "uri"
+	FormatUUID     = "uuid"
+	FormatBinary   = "binary"
 )
 
+// defaultDatabaseSchemaTimeout bounds database schema generation when no timeout is configured.
+const defaultDatabaseSchemaTimeout = 5 * time.Minute
+
+// ContentTypeJSON is the default media type for request and response bodies.
+const ContentTypeJSON = "application/json"
+
 // GoParser holds the parsed Go AST and type information.
 type GoParser struct {
 	fset     *token.FileSet
@@ -117,7 +196,11 @@ type TSParser struct {
 type OpenAPICollector struct {
 	goParser            *GoParser
 	tsParser            *TSParser
-	externalTypeFormats map[string]string
+	externalTypeFormats map[string]ExternalTypeFormat
+	allowAny            bool // Whether any/interface{} is documented as a free-form object
+	strictTags          bool // Whether every route group must have a tag description
+	warnUnusedTypes     bool // Whether types used by no operation are logged during generation
+	strictUnused        bool // Whether types used by no operation fail generation
 	l                   *slog.Logger
 
 	types             map[string]*TypeInfo             // Extracted type information, keyed by type name
@@ -130,16 +213,24 @@ type OpenAPICollector struct {
 	typeASTs  map[string]*ast.GenDecl // Type declaration AST nodes, keyed by type name
 	constASTs map[string]*ast.GenDecl // Const block AST nodes for enums, keyed by type name
 
+	patchTypes map[string]string // Base type names of registered patch types, keyed by patch type name
+
 	// Import resolution for current file being processed
 	currentFileImports map[string]string // Maps package alias to full import path
 
-	docsFilePath        string // Path to write documentation JSON file
-	openAPISpecFilePath string // Path to write OpenAPI YAML file
+	docsFilePath         string // Path to write documentation JSON file
+	openAPISpecFilePath  string // Path to write OpenAPI YAML file
+	asyncAPISpecFilePath string // Path to write AsyncAPI YAML file
+	markdownFilePath     string // Path to write Markdown API reference
 
 	apiInfo     APIInfo
 	openapiSpec string
 
 	primitiveTypeMapping map[string]FieldType
+
+	// Resolved schemas for payload validation, built lazily on first use
+	validationMu      sync.Mutex
+	validationSchemas openapi3.Schemas
 }
 
 // normalizeLocalPackagePath normalizes a path to be recognized as a local package.
@@ -152,11 +243,20 @@ func normalizeLocalPackagePath(path string) string {
 }
 
 type OpenAPICollectorOptions struct {
-	GoTypesDirPaths              []string // Paths to Go types directories for parsing (e.g., common types + API-specific types)
-	DocsFileOutputPath           string   // Path for generated API docs JSON file
-	DatabaseSchemaFileOutputPath string   // Path for generated DB schema SQL file
-	OpenAPISpecOutputPath        string   // Path for generated OpenAPI YAML file
-	Deployment                   string   // Deployment type: "local" or "cloud"
+	GoTypesDirPaths              []string                      // Paths to Go types directories for parsing (e.g., common types + API-specific types)
+	DocsFileOutputPath           string                        // Path for generated API docs JSON file
+	DatabaseSchemaFileOutputPath string                        // Path for generated DB schema SQL file, the JSON schema is written next to it
+	DatabaseSchemaTimeout        time.Duration                 // Deadline for generating the DB schema (optional, defaults to 5 minutes)
+	OpenAPISpecOutputPath        string                        // Path for generated OpenAPI YAML file
+	AsyncAPISpecOutputPath       string                        // Path for generated AsyncAPI YAML file (optional, MQTT operations only)
+	MarkdownOutputPath           string                        // Path for generated Markdown API reference (optional)
+	ExternalTypeFormats          map[string]ExternalTypeFormat // Additional external types keyed by full type path (e.g., "github.com/google/uuid.UUID")
+	AllowAny                     bool                          // Document any/interface{} as a free-form object instead of rejecting it (optional)
+	StrictTags                   bool                          // Reject routes whose group has no entry in APIInfo.TagDescriptions (optional)
+	WarnUnusedTypes              bool                          // Log extracted types used by no operation during generation (optional)
+	StrictUnused                 bool                          // Fail generation on extracted types used by no operation, implies WarnUnusedTypes (optional)
+	ParseCache                   *ParseCache                   // Shares parsed directories and database schemas with other collectors (optional)
+	Deployment                   string                        // Deployment type: "local" or "cloud"
 	APIInfo                      APIInfo
 }
 
@@ -182,6 +282,10 @@ func NewOpenAPICollector(l *slog.Logger, opts OpenAPICollectorOptions) (*OpenAPI
 		return nil, errors.New("OpenAPI spec file path is required")
 	}
 
+	if err := validateSecuritySchemes(opts.APIInfo); err != nil {
+		return nil, fmt.Errorf("invalid API info: %w", err)
+	}
+
 	// Normalize all paths to be recognized as local packages
 	var goTypesDirPaths []string
 	for _, path := range opts.GoTypesDirPaths {
@@ -190,12 +294,14 @@ func NewOpenAPICollector(l *slog.Logger, opts OpenAPICollectorOptions) (*OpenAPI
 
 	l.Debug("Creating doc collector", slog.Any("goTypesDirPaths", goTypesDirPaths))
 
-	externalTypeFormats := make(map[string]string, len(getExternalTypeMappings()))
+	externalTypeFormats, err := buildExternalTypeFormats(opts.ExternalTypeFormats)
+	if err != nil {
+		return nil, fmt.Errorf("invalid external type formats: %w", err)
+	}
 
-	gutsOverrides := make(map[string]guts.TypeOverride, len(getExternalTypeMappings()))
-	for _, m := range getExternalTypeMappings() {
-		externalTypeFormats[m.fullPath] = m.openAPIFormat
-		gutsOverrides[m.fullPath] = m.gutsOverride
+	gutsOverrides, err := buildGutsOverrides(externalTypeFormats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TypeScript overrides: %w", err)
 	}
 
 	docCollector := &OpenAPICollector{
@@ -206,15 +312,28 @@ func NewOpenAPICollector(l *slog.Logger, opts OpenAPICollectorOptions) (*OpenAPI
 		mqttSubscriptions:    make(map[string]*MQTTSubscriptionInfo),
 		typeASTs:             make(map[string]*ast.GenDecl),
 		constASTs:            make(map[string]*ast.GenDecl),
+		patchTypes:           make(map[string]string),
 		currentFileImports:   make(map[string]string),
 		externalTypeFormats:  externalTypeFormats,
+		allowAny:             opts.AllowAny,
+		strictTags:           opts.StrictTags,
+		warnUnusedTypes:      opts.WarnUnusedTypes || opts.StrictUnused,
+		strictUnused:         opts.StrictUnused,
 		docsFilePath:         opts.DocsFileOutputPath,
 		openAPISpecFilePath:  opts.OpenAPISpecOutputPath,
+		asyncAPISpecFilePath: opts.AsyncAPISpecOutputPath,
+		markdownFilePath:     opts.MarkdownOutputPath,
 		apiInfo:              opts.APIInfo,
 		primitiveTypeMapping: getPrimitiveTypeMappings(),
 	}
 
-	dbSchema, err := docCollector.GenerateDatabaseSchema(opts.Deployment, opts.DatabaseSchemaFileOutputPath)
+	dbCtx, cancel := context.WithTimeout(context.Background(), cmp.Or(opts.DatabaseSchemaTimeout, defaultDatabaseSchemaTimeout))
+	defer cancel()
+
+	// Migrate a temporary database, reusing the cached schema when the deployment was already migrated
+	dbSchema, err := opts.ParseCache.databaseSchema(opts.Deployment, opts.DatabaseSchemaFileOutputPath, func() (string, error) {
+		return docCollector.GenerateDatabaseSchema(dbCtx, opts.Deployment, opts.DatabaseSchemaFileOutputPath)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate database schema: %w", err)
 	}
@@ -222,8 +341,8 @@ func NewOpenAPICollector(l *slog.Logger, opts OpenAPICollectorOptions) (*OpenAPI
 	docCollector.database.Dialect = "postgres"
 	docCollector.database.Schema = dbSchema
 
-	// Parse all directories at once using parseGoTypesDirs
-	goParser, err := docCollector.parseGoTypesDirs(goTypesDirPaths)
+	// Parse the directories using parseGoTypesDirs, reusing the cached packages of already parsed directories
+	goParser, err := opts.ParseCache.goParser(goTypesDirPaths, docCollector.parseGoTypesDirs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Go types directories: %w", err)
 	}
@@ -231,7 +350,14 @@ func NewOpenAPICollector(l *slog.Logger, opts OpenAPICollectorOptions) (*OpenAPI
 	docCollector.goParser = goParser
 
 	// Create TypeScript parser for all directories
-	tsParser, err := newTSParser(l, goTypesDirPaths, gutsOverrides)
+	ts, err := opts.ParseCache.typescript(goTypesDirPaths, externalTypeFormats, func() (*guts.Typescript, error) {
+		return parseTypescript(l, goTypesDirPaths, gutsOverrides)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TypeScript parser: %w", err)
+	}
+
+	tsParser, err := newTSParser(ts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TypeScript parser: %w", err)
 	}
@@ -248,8 +374,8 @@ func NewOpenAPICollector(l *slog.Logger, opts OpenAPICollectorOptions) (*OpenAPI
 	return docCollector, nil
 }
 
-// newTSParser creates a TypeScript parser using guts for the specified Go types directories.
-func newTSParser(l *slog.Logger, goTypesDirPaths []string, gutsOverrides map[string]guts.TypeOverride) (*TSParser, error) {
+// parseTypescript generates a TypeScript AST using guts for the specified Go types directories.
+func parseTypescript(l *slog.Logger, goTypesDirPaths []string, gutsOverrides map[string]guts.TypeOverride) (*guts.Typescript, error) {
 	l.Debug("Parsing Go types directories", slog.Any("paths", goTypesDirPaths))
 
 	goParser, err := guts.NewGolangParser()
@@ -302,6 +428,12 @@ func newTSParser(l *slog.Logger, goTypesDirPaths []string, gutsOverrides map[str
 
 	l.Debug("TypeScript AST generated successfully")
 
+	return ts, nil
+}
+
+// newTSParser creates a TypeScript parser for the given AST.
+// Each parser gets its own bindings, so collectors sharing a cached AST can serialize independently.
+func newTSParser(ts *guts.Typescript) (*TSParser, error) {
 	vm, err := bindings.New()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bindings: %w", err)
@@ -315,11 +447,15 @@ func newTSParser(l *slog.Logger, goTypesDirPaths []string, gutsOverrides map[str
 	return tsParser, nil
 }
 
-// Generate generates both the OpenAPI spec YAML and the docs JSON file.
+// Generate generates the OpenAPI spec YAML, the optional AsyncAPI spec YAML, the docs JSON file and the optional Markdown reference.
 func (g *OpenAPICollector) Generate() error {
 	// Compute type relationships
 	g.computeTypeRelationships()
 
+	if err := g.checkUnusedTypes(); err != nil {
+		return err
+	}
+
 	// Generate type representations
 	if err := g.generateTypesRepresentations(); err != nil {
 		return fmt.Errorf("failed to generate types representations: %w", err)
@@ -340,11 +476,29 @@ func (g *OpenAPICollector) Generate() error {
 
 	g.l.Info("openapi spec written", slog.String("file", g.openAPISpecFilePath))
 
+	// Write AsyncAPI spec
+	if g.asyncAPISpecFilePath != "" {
+		if err := g.writeAsyncAPIYAML(g.asyncAPISpecFilePath); err != nil {
+			return fmt.Errorf("failed to write AsyncAPI spec: %w", err)
+		}
+
+		g.l.Info("asyncapi spec written", slog.String("file", g.asyncAPISpecFilePath))
+	}
+
 	// Write docs JSON
 	if err := g.writeDocsJSON(); err != nil {
 		return fmt.Errorf("failed to write docs JSON: %w", err)
 	}
 
+	// Write Markdown reference, after the docs JSON so it shares the sorted documentation
+	if g.markdownFilePath != "" {
+		if err := GenerateMarkdown(g.getDocumentation(), g.markdownFilePath); err != nil {
+			return fmt.Errorf("failed to write markdown reference: %w", err)
+		}
+
+		g.l.Info("markdown reference written", slog.String("file", g.markdownFilePath))
+	}
+
 	g.l.Info("api documentation generated")
 
 	return nil
@@ -376,11 +530,9 @@ func (g *OpenAPICollector) generateOpenAPISpec() (*openapi3.T, error) {
 	spec.Info.Version = g.apiInfo.Version
 	spec.Info.Description = g.apiInfo.Description
 
-	for _, server := range g.apiInfo.Servers {
-		spec.Servers = append(spec.Servers, &openapi3.Server{
-			URL:         server.URL,
-			Description: server.Description,
-		})
+	spec.Servers, err = buildServers(g.apiInfo.Servers)
+	if err != nil {
+		return nil, err
 	}
 
 	return spec, nil
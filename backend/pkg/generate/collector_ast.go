@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"log/slog"
 	"maps"
 	"os"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	"golang.org/x/text/cases"
@@ -21,9 +23,20 @@ import (
 
 const DEPRECATED_PREFIX = "deprecated:"
 
+// directivePrefix starts type doc comment directives (e.g., //openapi:additionalProperties).
+// Like Go directives, they have no space after the slashes and are not part of the description.
+const directivePrefix = "//openapi:"
+
+// directiveAdditionalProperties allows properties not declared by a struct in its schema.
+// Such types must be decoded leniently (e.g., with utils.FromJSONLenient), strict decoding rejects unknown fields.
+const directiveAdditionalProperties = "additionalProperties"
+
+// inlineStructName names anonymous struct types in error messages.
+const inlineStructName = "struct{...}"
+
 // parseGoTypesDirs parses Go type definitions from multiple directories using go/packages.
-// All packages are loaded together so they can reference each other properly.
-func (g *OpenAPICollector) parseGoTypesDirs(goTypesDirPaths []string) (*GoParser, error) {
+// The positions of the parsed files are recorded in fset, which may be shared with other parsers.
+func (g *OpenAPICollector) parseGoTypesDirs(fset *token.FileSet, goTypesDirPaths []string) (*GoParser, error) {
 	g.l.Debug("Parsing Go types directories", slog.Any("paths", goTypesDirPaths))
 
 	// Validate all paths exist
@@ -37,13 +50,9 @@ func (g *OpenAPICollector) parseGoTypesDirs(goTypesDirPaths []string) (*GoParser
 		}
 	}
 
-	// Use a shared file set for all packages
-	fset := token.NewFileSet()
-
-	// Use go/packages to load and type-check all packages
-	// We load them all at once so they can properly reference each other
+	// Use go/packages to load and type-check all packages at once
 	cfg := &packages.Config{
-		Fset: fset, // Use our shared file set
+		Fset: fset, // Use the shared file set
 		Mode: packages.NeedName |
 			packages.NeedFiles |
 			packages.NeedSyntax |
@@ -220,13 +229,27 @@ func (g *OpenAPICollector) extractTypeNames(file *ast.File) error {
 				return fmt.Errorf("failed to parse deprecation info for type %s: %w", typeName, err)
 			}
 
+			directives, err := parseTypeDirectives(descDoc)
+			if err != nil {
+				return fmt.Errorf("invalid directive for type %s: %w", typeName, err)
+			}
+
 			// Create stub TypeInfo (no field analysis yet)
-			g.types[typeName] = &TypeInfo{
-				Name:        typeName,
-				Description: cleanedDesc,
-				Deprecated:  deprecated,
+			typeInfo := &TypeInfo{
+				Name:                      typeName,
+				Description:               cleanedDesc,
+				Deprecated:                deprecated,
+				AllowAdditionalProperties: directives.additionalProperties,
 				// Kind, Fields, UnderlyingType, etc. will be set in Pass 2
 			}
+
+			// Keep the enum values of a const block from a file processed before this declaration
+			if existing, exists := g.types[typeName]; exists && isEnumKind(existing.Kind) {
+				typeInfo.Kind = existing.Kind
+				typeInfo.EnumValues = existing.EnumValues
+			}
+
+			g.types[typeName] = typeInfo
 		}
 	}
 
@@ -284,6 +307,25 @@ func (g *OpenAPICollector) processTypeSpecFields(typeSpec *ast.TypeSpec) error {
 		typeInfo.UnderlyingType = &underlyingType
 		typeInfo.References = refs
 
+	case *ast.SelectorExpr:
+		// Alias to an external type (e.g., type Timestamp = time.Time), documented with the external type format
+		typeInfo.Kind = TypeKindAlias
+
+		underlyingType, refs, err := g.analyzeSelectorType(t)
+		if err != nil {
+			return fmt.Errorf("failed to analyze underlying type for alias %s: %w", typeName, err)
+		}
+
+		// A defined type (type Timestamp time.Time) does not inherit the methods of the external type,
+		// so it does not inherit its JSON encoding either, unless it implements its own
+		if !typeSpec.Assign.IsValid() && !g.hasOwnJSONEncoding(typeSpec.Name) {
+			return fmt.Errorf("defined type %s does not inherit the JSON encoding of %s.%s - declare an alias (type %s = %s.%s) or implement json.Marshaler",
+				typeName, t.X, t.Sel.Name, typeName, t.X, t.Sel.Name)
+		}
+
+		typeInfo.UnderlyingType = &underlyingType
+		typeInfo.References = refs
+
 	case *ast.ArrayType, *ast.MapType:
 		// Arrays and maps as top-level types are treated as aliases
 		if !isEnumKind(typeInfo.Kind) {
@@ -302,9 +344,38 @@ func (g *OpenAPICollector) processTypeSpecFields(typeSpec *ast.TypeSpec) error {
 		return fmt.Errorf("unsupported type %s: %T (please use struct, type alias, or basic types)", typeName, typeSpec.Type)
 	}
 
+	if typeInfo.AllowAdditionalProperties && typeInfo.Kind != TypeKindObject {
+		return fmt.Errorf("directive %s%s is only supported on struct types, %s is %s", directivePrefix, directiveAdditionalProperties, typeName, typeInfo.Kind)
+	}
+
 	return nil
 }
 
+// hasOwnJSONEncoding reports whether a defined type implements json.Marshaler or encoding.TextMarshaler.
+// Returns true when type information is not available, the type cannot be checked.
+func (g *OpenAPICollector) hasOwnJSONEncoding(name *ast.Ident) bool {
+	if g.goParser == nil {
+		return true
+	}
+
+	for _, pkg := range g.goParser.packages {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+
+		obj, ok := pkg.TypesInfo.Defs[name]
+		if !ok || obj == nil {
+			continue
+		}
+
+		methods := types.NewMethodSet(types.NewPointer(obj.Type()))
+
+		return methods.Lookup(obj.Pkg(), "MarshalJSON") != nil || methods.Lookup(obj.Pkg(), "MarshalText") != nil
+	}
+
+	return true
+}
+
 // extractStructTypeFields extracts struct fields and populates the TypeInfo.
 // Wrapper for extractStructType that matches the error-only return signature.
 func (g *OpenAPICollector) extractStructTypeFields(name string, structType *ast.StructType, typeInfo *TypeInfo) error {
@@ -320,6 +391,7 @@ func (g *OpenAPICollector) extractStructType(name string, structType *ast.Struct
 	typeInfo.References = []string{}
 
 	refs := make(map[string]struct{})
+	goFieldsByJSONName := make(map[string]string) // JSON name to Go field name, to detect collisions
 
 	for _, field := range structType.Fields.List {
 		if len(field.Names) == 0 {
@@ -342,6 +414,13 @@ func (g *OpenAPICollector) extractStructType(name string, structType *ast.Struct
 				return nil, err
 			}
 
+			// encoding/json drops conflicting fields, the schema would document a property that is never sent
+			if other, exists := goFieldsByJSONName[fieldInfo.Name]; exists {
+				return nil, fmt.Errorf("fields %s.%s and %s.%s have the same JSON name %q", name, other, name, fieldName.Name, fieldInfo.Name)
+			}
+
+			goFieldsByJSONName[fieldInfo.Name] = fieldName.Name
+
 			typeInfo.Fields = append(typeInfo.Fields, fieldInfo)
 
 			// Collect references
@@ -377,6 +456,11 @@ func (g *OpenAPICollector) extractFieldInfo(parentName, fieldName string, field
 		return FieldInfo{}, nil, fmt.Errorf("failed to analyze field type for %s.%s (type: %T): %w", parentName, fieldName, field.Type, err)
 	}
 
+	// Apply validation constraints from the validate tag (go-playground/validator syntax)
+	if err := applyValidateConstraints(&fieldType, parseValidateTag(field)); err != nil {
+		return FieldInfo{}, nil, fmt.Errorf("invalid validate tag for field %s.%s: %w", parentName, fieldName, err)
+	}
+
 	// Determine if field is required
 	// In Go: pointer types (*T) are optional, non-pointer types are required unless omitempty is set
 	required := !fieldType.Nullable && !tagInfo.omitempty
@@ -395,12 +479,25 @@ func (g *OpenAPICollector) extractFieldInfo(parentName, fieldName string, field
 		return FieldInfo{}, nil, fmt.Errorf("failed to generate display type for field %s.%s: %w", parentName, fieldName, err)
 	}
 
+	defaultValue, err := g.parseDefaultTag(field, fieldType)
+	if err != nil {
+		return FieldInfo{}, nil, fmt.Errorf("invalid default tag for field %s.%s: %w", parentName, fieldName, err)
+	}
+
+	openAPITag, err := parseOpenAPITag(field)
+	if err != nil {
+		return FieldInfo{}, nil, fmt.Errorf("invalid openapi tag for field %s.%s: %w", parentName, fieldName, err)
+	}
+
 	fieldInfo := FieldInfo{
 		Name:        tagInfo.name,
 		DisplayType: displayType,
 		TypeInfo:    fieldType,
 		Description: cleanedFieldDesc,
 		Deprecated:  fieldDeprecated,
+		Default:     defaultValue,
+		ReadOnly:    openAPITag.readOnly,
+		WriteOnly:   openAPITag.writeOnly,
 	}
 
 	return fieldInfo, refs, nil
@@ -420,9 +517,9 @@ func (g *OpenAPICollector) analyzeGoType(expr ast.Expr) (FieldType, []string, er
 			return primitiveType, refs, nil
 		}
 
-		// Reject 'any' explicitly
+		// Reject 'any' explicitly, unless free-form objects are allowed
 		if typeName == "any" {
-			return FieldType{}, nil, errors.New("type 'any' is not allowed in API types - use concrete types instead. Check struct fields and type aliases for 'any' usage")
+			return g.analyzeAnyType()
 		}
 
 		// Check if it's a defined type in our types map (will be populated after first pass)
@@ -446,11 +543,50 @@ func (g *OpenAPICollector) analyzeGoType(expr ast.Expr) (FieldType, []string, er
 	case *ast.SelectorExpr:
 		return g.analyzeSelectorType(t)
 
+	case *ast.InterfaceType:
+		// Only the empty interface is equivalent to 'any'
+		if t.Methods != nil && len(t.Methods.List) > 0 {
+			return FieldType{}, nil, errors.New("non-empty interface types are not allowed in API types - use concrete types instead")
+		}
+
+		return g.analyzeAnyType()
+
+	case *ast.StructType:
+		return g.analyzeInlineStructType(t)
+
 	default:
 		return FieldType{}, nil, fmt.Errorf("unsupported type expression: %T (check for unsupported Go language features like interfaces, channels, or functions)", expr)
 	}
 }
 
+// analyzeAnyType handles any/interface{}, which is only allowed with [OpenAPICollectorOptions.AllowAny].
+// It becomes a free-form object that accepts any properties.
+func (g *OpenAPICollector) analyzeAnyType() (FieldType, []string, error) {
+	if !g.allowAny {
+		return FieldType{}, nil, errors.New("type 'any' is not allowed in API types - use concrete types instead. Check struct fields and type aliases for 'any' usage")
+	}
+
+	return FieldType{
+		Kind: FieldKindObject,
+		Type: freeFormObjectType,
+	}, []string{}, nil
+}
+
+// analyzeInlineStructType handles anonymous struct types (struct{ ... }), which become inline objects.
+// The fields are analyzed like the fields of a named struct, without registering a component.
+func (g *OpenAPICollector) analyzeInlineStructType(t *ast.StructType) (FieldType, []string, error) {
+	inline, err := g.extractStructType(inlineStructName, t, &TypeInfo{})
+	if err != nil {
+		return FieldType{}, nil, err
+	}
+
+	return FieldType{
+		Kind:   FieldKindObject,
+		Type:   "object",
+		Fields: inline.Fields,
+	}, inline.References, nil
+}
+
 // analyzePointerType handles pointer types (*T) which become nullable.
 func (g *OpenAPICollector) analyzePointerType(t *ast.StarExpr) (FieldType, []string, error) {
 	inner, innerRefs, err := g.analyzeGoType(t.X)
@@ -525,13 +661,15 @@ func (g *OpenAPICollector) analyzeSelectorType(t *ast.SelectorExpr) (FieldType,
 	// Look up the type format using the full import path
 	format, exists := g.externalTypeFormats[fullTypeKey]
 	if !exists {
-		return FieldType{}, nil, fmt.Errorf("unknown external type %s.%s (resolved to %s) - please add it to externalTypeFormats map in NewOpenAPICollector using the full import path as the key", pkgAlias, typeName, fullTypeKey)
+		return FieldType{}, nil, fmt.Errorf("unknown external type %s.%s (resolved to %s) - please add it to OpenAPICollectorOptions.ExternalTypeFormats using the full import path as the key", pkgAlias, typeName, fullTypeKey)
 	}
 
 	return FieldType{
-		Kind:   FieldKindPrimitive,
-		Type:   "string",
-		Format: format,
+		Kind:    FieldKindPrimitive,
+		Type:    format.Type,
+		Format:  format.Format,
+		Pattern: format.Pattern,
+		Note:    format.Note,
 	}, nil, nil
 }
 
@@ -556,8 +694,8 @@ func (g *OpenAPICollector) extractCommentsFromDoc(doc *ast.CommentGroup) string
 			continue
 		}
 
-		// Skip nolint comments
-		if strings.HasPrefix(text, "nolint:") {
+		// Skip nolint comments and directives
+		if strings.HasPrefix(text, "nolint:") || strings.HasPrefix(comment.Text, directivePrefix) {
 			continue
 		}
 
@@ -567,6 +705,36 @@ func (g *OpenAPICollector) extractCommentsFromDoc(doc *ast.CommentGroup) string
 	return strings.TrimSpace(builder.String())
 }
 
+// typeDirectives holds the parsed //openapi: directives of a type doc comment.
+type typeDirectives struct {
+	additionalProperties bool
+}
+
+// parseTypeDirectives parses the //openapi: directives of a type doc comment.
+func parseTypeDirectives(doc *ast.CommentGroup) (typeDirectives, error) {
+	var directives typeDirectives
+
+	if doc == nil {
+		return directives, nil
+	}
+
+	for _, comment := range doc.List {
+		directive, ok := strings.CutPrefix(comment.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(directive) {
+		case directiveAdditionalProperties:
+			directives.additionalProperties = true
+		default:
+			return directives, fmt.Errorf("unknown directive %q (supported: %s%s)", comment.Text, directivePrefix, directiveAdditionalProperties)
+		}
+	}
+
+	return directives, nil
+}
+
 // parseDeprecation extracts deprecation info from comments and returns cleaned description.
 // It looks for "Deprecated:" anywhere in the text (case-insensitive) and captures the message.
 // Returns (deprecationInfo, cleanedDescription, error).
@@ -620,6 +788,10 @@ func generateDisplayType(ft FieldType) (string, error) {
 		return "Array", nil
 
 	case FieldKindObject:
+		if ft.Type == freeFormObjectType {
+			return freeFormObjectType, nil
+		}
+
 		return "Object", nil
 
 	default:
@@ -715,3 +887,358 @@ func parseJSONTag(field *ast.Field, defaultName string) jsonTagInfo {
 
 	return info
 }
+
+// validateTagInfo holds the subset of validate struct tag constraints that map to OpenAPI.
+type validateTagInfo struct {
+	min      string
+	max      string
+	oneOf    []string
+	patterns []string
+	unique   bool
+}
+
+// isEmpty returns true if no constraints were parsed.
+func (v validateTagInfo) isEmpty() bool {
+	return v.min == "" && v.max == "" && len(v.oneOf) == 0 && len(v.patterns) == 0 && !v.unique
+}
+
+// getValidateTagPatterns returns validator tags that can be expressed as an OpenAPI pattern.
+func getValidateTagPatterns() map[string]string {
+	return map[string]string{
+		"alpha":    "^[a-zA-Z]+$",
+		"alphanum": "^[a-zA-Z0-9]+$",
+		"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+		"number":   "^[0-9]+$",
+	}
+}
+
+// openAPITagInfo holds parsed openapi struct tag options.
+type openAPITagInfo struct {
+	readOnly  bool
+	writeOnly bool
+}
+
+// parseOpenAPITag parses the openapi struct tag, a comma-separated list of options (readOnly, writeOnly).
+// A field cannot be both readOnly and writeOnly.
+func parseOpenAPITag(field *ast.Field) (openAPITagInfo, error) {
+	var info openAPITagInfo
+
+	if field.Tag == nil {
+		return info, nil
+	}
+
+	tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+
+	openAPITag, ok := tag.Lookup("openapi")
+	if !ok {
+		return info, nil
+	}
+
+	for option := range strings.SplitSeq(openAPITag, ",") {
+		switch strings.TrimSpace(option) {
+		case "readOnly":
+			info.readOnly = true
+		case "writeOnly":
+			info.writeOnly = true
+		default:
+			return info, fmt.Errorf("unknown option %q (supported: readOnly, writeOnly)", option)
+		}
+	}
+
+	if info.readOnly && info.writeOnly {
+		return info, errors.New("field cannot be both readOnly and writeOnly")
+	}
+
+	return info, nil
+}
+
+// parseValidateTag parses a validate struct tag (go-playground/validator syntax).
+// Only constraints that can be represented in OpenAPI are extracted, others (e.g., "required") are ignored.
+// Parsing stops at "dive", since the following tags apply to the elements and not the field itself.
+func parseValidateTag(field *ast.Field) validateTagInfo {
+	var info validateTagInfo
+
+	if field.Tag == nil {
+		return info
+	}
+
+	tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+
+	validateTag, ok := tag.Lookup("validate")
+	if !ok {
+		return info
+	}
+
+	patterns := getValidateTagPatterns()
+
+	for part := range strings.SplitSeq(validateTag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch name {
+		case "dive":
+			return info
+		case "min", "gte":
+			info.min = param
+		case "max", "lte":
+			info.max = param
+		case "len":
+			info.min = param
+			info.max = param
+		case "oneof":
+			info.oneOf = strings.Fields(param)
+		case "unique":
+			info.unique = true
+		default:
+			if pattern, ok := patterns[name]; ok {
+				info.patterns = append(info.patterns, pattern)
+			}
+		}
+	}
+
+	return info
+}
+
+// applyValidateConstraints applies parsed validate tag constraints to a field type.
+// For strings min/max map to length bounds, for numbers to value bounds and oneof becomes an inline enum.
+// For arrays min/max map to item count bounds and unique to unique items.
+func applyValidateConstraints(ft *FieldType, info validateTagInfo) error {
+	if info.isEmpty() {
+		return nil
+	}
+
+	if ft.Kind == FieldKindArray {
+		return applyArrayConstraints(ft, info)
+	}
+
+	if info.unique {
+		return fmt.Errorf("unique is only supported on array fields, got %s", ft.Kind)
+	}
+
+	if ft.Kind != FieldKindPrimitive {
+		return fmt.Errorf("validate constraints are only supported on primitive fields, got %s", ft.Kind)
+	}
+
+	switch ft.Type {
+	case typeString:
+		return applyStringConstraints(ft, info)
+	case typeInteger, typeNumber:
+		return applyNumberConstraints(ft, info)
+	default:
+		return fmt.Errorf("validate constraints are not supported on %s fields", ft.Type)
+	}
+}
+
+// applyStringConstraints applies length, enum and pattern constraints to a string field type.
+func applyStringConstraints(ft *FieldType, info validateTagInfo) error {
+	if info.min != "" {
+		minLength, err := strconv.ParseUint(info.min, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid min length %q: %w", info.min, err)
+		}
+
+		ft.MinLength = &minLength
+	}
+
+	if info.max != "" {
+		maxLength, err := strconv.ParseUint(info.max, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max length %q: %w", info.max, err)
+		}
+
+		ft.MaxLength = &maxLength
+	}
+
+	if ft.MinLength != nil && ft.MaxLength != nil && *ft.MinLength > *ft.MaxLength {
+		return fmt.Errorf("min length %d is greater than max length %d", *ft.MinLength, *ft.MaxLength)
+	}
+
+	if len(info.patterns) > 1 {
+		return fmt.Errorf("multiple pattern constraints are not supported, got %d", len(info.patterns))
+	}
+
+	if len(info.patterns) == 1 {
+		ft.Pattern = info.patterns[0]
+	}
+
+	for _, v := range info.oneOf {
+		ft.Enum = append(ft.Enum, v)
+	}
+
+	return nil
+}
+
+// applyArrayConstraints applies item count and uniqueness constraints to an array field type.
+func applyArrayConstraints(ft *FieldType, info validateTagInfo) error {
+	if len(info.oneOf) > 0 || len(info.patterns) > 0 {
+		return errors.New("oneof and pattern constraints apply to array items, use dive before them")
+	}
+
+	if info.min != "" {
+		minItems, err := strconv.ParseUint(info.min, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid min items %q: %w", info.min, err)
+		}
+
+		ft.MinItems = &minItems
+	}
+
+	if info.max != "" {
+		maxItems, err := strconv.ParseUint(info.max, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max items %q: %w", info.max, err)
+		}
+
+		ft.MaxItems = &maxItems
+	}
+
+	if ft.MinItems != nil && ft.MaxItems != nil && *ft.MinItems > *ft.MaxItems {
+		return fmt.Errorf("min items %d is greater than max items %d", *ft.MinItems, *ft.MaxItems)
+	}
+
+	ft.UniqueItems = info.unique
+
+	return nil
+}
+
+// applyNumberConstraints applies value bounds and enum constraints to an integer or number field type.
+func applyNumberConstraints(ft *FieldType, info validateTagInfo) error {
+	if len(info.patterns) > 0 {
+		return fmt.Errorf("pattern constraints are not supported on %s fields", ft.Type)
+	}
+
+	if info.min != "" {
+		minimum, err := strconv.ParseFloat(info.min, 64)
+		if err != nil {
+			return fmt.Errorf("invalid minimum %q: %w", info.min, err)
+		}
+
+		ft.Minimum = &minimum
+	}
+
+	if info.max != "" {
+		maximum, err := strconv.ParseFloat(info.max, 64)
+		if err != nil {
+			return fmt.Errorf("invalid maximum %q: %w", info.max, err)
+		}
+
+		ft.Maximum = &maximum
+	}
+
+	if ft.Minimum != nil && ft.Maximum != nil && *ft.Minimum > *ft.Maximum {
+		return fmt.Errorf("minimum %v is greater than maximum %v", *ft.Minimum, *ft.Maximum)
+	}
+
+	for _, v := range info.oneOf {
+		if ft.Type == typeInteger {
+			intVal, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid integer oneof value %q: %w", v, err)
+			}
+
+			ft.Enum = append(ft.Enum, intVal)
+
+			continue
+		}
+
+		floatVal, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number oneof value %q: %w", v, err)
+		}
+
+		ft.Enum = append(ft.Enum, floatVal)
+	}
+
+	return nil
+}
+
+// parseDefaultTag parses the default struct tag and converts it to a value matching the field type.
+// Returns nil if no default tag is set.
+func (g *OpenAPICollector) parseDefaultTag(field *ast.Field, ft FieldType) (any, error) {
+	if field.Tag == nil {
+		return nil, nil //nolint:nilnil // No default is a valid outcome
+	}
+
+	tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+
+	raw, ok := tag.Lookup("default")
+	if !ok {
+		return nil, nil //nolint:nilnil // No default is a valid outcome
+	}
+
+	switch ft.Kind {
+	case FieldKindPrimitive:
+		value, err := convertDefaultValue(raw, ft.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ft.Enum) > 0 && !slices.Contains(ft.Enum, value) {
+			return nil, fmt.Errorf("default value %q is not one of the allowed values %v", raw, ft.Enum)
+		}
+
+		return value, nil
+
+	case FieldKindReference, FieldKindEnum:
+		// Enum types are known after pass 1, so defaults can be validated against their values
+		typeInfo, exists := g.types[ft.Type]
+		if !exists || !isEnumKind(typeInfo.Kind) {
+			return nil, fmt.Errorf("default values are only supported on primitive and enum fields, got reference to %s", ft.Type)
+		}
+
+		schemaType := typeString
+		if typeInfo.Kind == TypeKindNumberEnum {
+			schemaType = typeInteger
+		}
+
+		value, err := convertDefaultValue(raw, schemaType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ev := range typeInfo.EnumValues {
+			if ev.Value == value {
+				return value, nil
+			}
+		}
+
+		return nil, fmt.Errorf("default value %q is not a valid value of enum %s", raw, ft.Type)
+
+	default:
+		return nil, fmt.Errorf("default values are not supported on %s fields", ft.Kind)
+	}
+}
+
+// convertDefaultValue converts a raw default tag value to the Go value matching the OpenAPI type.
+func convertDefaultValue(raw string, schemaType string) (any, error) {
+	switch schemaType {
+	case typeString:
+		return raw, nil
+
+	case typeBoolean:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("default value %q is not a valid boolean: %w", raw, err)
+		}
+
+		return value, nil
+
+	case typeInteger:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("default value %q is not a valid integer: %w", raw, err)
+		}
+
+		return value, nil
+
+	case typeNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("default value %q is not a valid number: %w", raw, err)
+		}
+
+		return value, nil
+
+	default:
+		return nil, fmt.Errorf("default values are not supported on %s fields", schemaType)
+	}
+}
@@ -0,0 +1,862 @@
+package generate
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// newTaggedField creates an AST field with the given raw struct tag (without backticks).
+func newTaggedField(tag string) *ast.Field {
+	return &ast.Field{
+		Tag: &ast.BasicLit{Kind: token.STRING, Value: "`" + tag + "`"},
+	}
+}
+
+func TestApplyValidateConstraints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tag      string
+		input    FieldType
+		expected FieldType
+		wantErr  bool
+	}{
+		{
+			name:     "no validate tag",
+			tag:      `json:"name"`,
+			input:    FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			expected: FieldType{Kind: FieldKindPrimitive, Type: typeString},
+		},
+		{
+			name:  "string length bounds",
+			tag:   `json:"name" validate:"required,min=3,max=64"`,
+			input: FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			expected: FieldType{
+				Kind: FieldKindPrimitive, Type: typeString,
+				MinLength: new(uint64(3)), MaxLength: new(uint64(64)),
+			},
+		},
+		{
+			name:  "string oneof",
+			tag:   `validate:"oneof=celsius fahrenheit"`,
+			input: FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			expected: FieldType{
+				Kind: FieldKindPrimitive, Type: typeString,
+				Enum: []any{"celsius", "fahrenheit"},
+			},
+		},
+		{
+			name:  "string pattern",
+			tag:   `validate:"alphanum"`,
+			input: FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			expected: FieldType{
+				Kind: FieldKindPrimitive, Type: typeString,
+				Pattern: "^[a-zA-Z0-9]+$",
+			},
+		},
+		{
+			name:  "integer bounds and oneof",
+			tag:   `validate:"gte=0,lte=100,oneof=0 50 100"`,
+			input: FieldType{Kind: FieldKindPrimitive, Type: typeInteger},
+			expected: FieldType{
+				Kind: FieldKindPrimitive, Type: typeInteger,
+				Minimum: new(float64(0)), Maximum: new(float64(100)),
+				Enum: []any{int64(0), int64(50), int64(100)},
+			},
+		},
+		{
+			name:     "dive stops parsing",
+			tag:      `validate:"dive,min=3"`,
+			input:    FieldType{Kind: FieldKindArray, Type: "array"},
+			expected: FieldType{Kind: FieldKindArray, Type: "array"},
+		},
+		{
+			name:  "array item bounds and unique",
+			tag:   `json:"tags" validate:"required,min=1,max=10,unique,dive,min=3"`,
+			input: FieldType{Kind: FieldKindArray, Type: "array"},
+			expected: FieldType{
+				Kind: FieldKindArray, Type: "array",
+				MinItems: new(uint64(1)), MaxItems: new(uint64(10)), UniqueItems: true,
+			},
+		},
+		{
+			name:    "array min items greater than max items",
+			tag:     `validate:"min=5,max=1"`,
+			input:   FieldType{Kind: FieldKindArray, Type: "array"},
+			wantErr: true,
+		},
+		{
+			name:    "array oneof before dive",
+			tag:     `validate:"oneof=a b"`,
+			input:   FieldType{Kind: FieldKindArray, Type: "array"},
+			wantErr: true,
+		},
+		{
+			name:    "unique on string",
+			tag:     `validate:"unique"`,
+			input:   FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			wantErr: true,
+		},
+		{
+			name:    "min greater than max",
+			tag:     `validate:"min=10,max=1"`,
+			input:   FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			wantErr: true,
+		},
+		{
+			name:    "invalid integer oneof",
+			tag:     `validate:"oneof=a b"`,
+			input:   FieldType{Kind: FieldKindPrimitive, Type: typeInteger},
+			wantErr: true,
+		},
+		{
+			name:    "boolean not supported",
+			tag:     `validate:"min=1"`,
+			input:   FieldType{Kind: FieldKindPrimitive, Type: typeBoolean},
+			wantErr: true,
+		},
+		{
+			name:    "reference not supported",
+			tag:     `validate:"min=1"`,
+			input:   FieldType{Kind: FieldKindReference, Type: "User"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ft := tt.input
+
+			err := applyValidateConstraints(&ft, parseValidateTag(newTaggedField(tt.tag)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyValidateConstraints(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(ft, tt.expected) {
+				t.Errorf("applyValidateConstraints(%q) = %+v, want %+v", tt.tag, ft, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildPrimitiveSchemaWithConstraints(t *testing.T) {
+	t.Parallel()
+
+	ft := FieldType{
+		Kind:      FieldKindPrimitive,
+		Type:      typeString,
+		MinLength: new(uint64(3)),
+		MaxLength: new(uint64(64)),
+		Pattern:   "^[a-z]+$",
+		Enum:      []any{"abc", "def"},
+	}
+
+	schemaRef, err := buildPrimitiveSchemaFromFieldType(ft, "")
+	if err != nil {
+		t.Fatalf("buildPrimitiveSchemaFromFieldType() error = %v", err)
+	}
+
+	schema := schemaRef.Value
+	if schema.MinLength != 3 || schema.MaxLength == nil || *schema.MaxLength != 64 {
+		t.Errorf("length bounds = (%d, %v), want (3, 64)", schema.MinLength, schema.MaxLength)
+	}
+
+	if schema.Pattern != ft.Pattern {
+		t.Errorf("pattern = %q, want %q", schema.Pattern, ft.Pattern)
+	}
+
+	if !reflect.DeepEqual(schema.Enum, ft.Enum) {
+		t.Errorf("enum = %v, want %v", schema.Enum, ft.Enum)
+	}
+}
+
+func TestBuildArraySchemaWithConstraints(t *testing.T) {
+	t.Parallel()
+
+	ft := FieldType{
+		Kind:        FieldKindArray,
+		Type:        "array",
+		ItemsType:   &FieldType{Kind: FieldKindPrimitive, Type: typeString},
+		MinItems:    new(uint64(1)),
+		MaxItems:    new(uint64(10)),
+		UniqueItems: true,
+	}
+
+	schemaRef, err := buildArraySchemaFromFieldType(ft, "")
+	if err != nil {
+		t.Fatalf("buildArraySchemaFromFieldType() error = %v", err)
+	}
+
+	schema := schemaRef.Value
+	if schema.MinItems != 1 || schema.MaxItems == nil || *schema.MaxItems != 10 {
+		t.Errorf("item bounds = (%d, %v), want (1, 10)", schema.MinItems, schema.MaxItems)
+	}
+
+	if !schema.UniqueItems {
+		t.Error("uniqueItems = false, want true")
+	}
+
+	unconstrained, err := buildArraySchemaFromFieldType(FieldType{Kind: FieldKindArray, Type: "array", ItemsType: ft.ItemsType}, "")
+	if err != nil {
+		t.Fatalf("buildArraySchemaFromFieldType() error = %v", err)
+	}
+
+	if unconstrained.Value.MinItems != 0 || unconstrained.Value.MaxItems != nil || unconstrained.Value.UniqueItems {
+		t.Errorf("unconstrained array schema = %+v, want no item constraints", unconstrained.Value)
+	}
+}
+
+func TestParseDefaultTag(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		types: map[string]*TypeInfo{
+			"Unit": {
+				Name: "Unit", Kind: TypeKindStringEnum,
+				EnumValues: []EnumValue{{Value: "celsius"}, {Value: "fahrenheit"}},
+			},
+			"Level": {
+				Name: "Level", Kind: TypeKindNumberEnum,
+				EnumValues: []EnumValue{{Value: int64(1)}, {Value: int64(2)}},
+			},
+			"User": {Name: "User", Kind: TypeKindObject},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		tag      string
+		input    FieldType
+		expected any
+		wantErr  bool
+	}{
+		{
+			name:     "no default tag",
+			tag:      `json:"name"`,
+			input:    FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			expected: nil,
+		},
+		{
+			name:     "string default",
+			tag:      `default:"hello"`,
+			input:    FieldType{Kind: FieldKindPrimitive, Type: typeString},
+			expected: "hello",
+		},
+		{
+			name:     "boolean default",
+			tag:      `default:"true"`,
+			input:    FieldType{Kind: FieldKindPrimitive, Type: typeBoolean},
+			expected: true,
+		},
+		{
+			name:     "integer default",
+			tag:      `default:"42"`,
+			input:    FieldType{Kind: FieldKindPrimitive, Type: typeInteger},
+			expected: int64(42),
+		},
+		{
+			name:     "number default",
+			tag:      `default:"1.5"`,
+			input:    FieldType{Kind: FieldKindPrimitive, Type: typeNumber},
+			expected: 1.5,
+		},
+		{
+			name:     "string enum default",
+			tag:      `default:"celsius"`,
+			input:    FieldType{Kind: FieldKindReference, Type: "Unit"},
+			expected: "celsius",
+		},
+		{
+			name:     "number enum default",
+			tag:      `default:"2"`,
+			input:    FieldType{Kind: FieldKindReference, Type: "Level"},
+			expected: int64(2),
+		},
+		{
+			name:    "invalid integer default",
+			tag:     `default:"abc"`,
+			input:   FieldType{Kind: FieldKindPrimitive, Type: typeInteger},
+			wantErr: true,
+		},
+		{
+			name:    "default not in oneof",
+			tag:     `default:"kelvin"`,
+			input:   FieldType{Kind: FieldKindPrimitive, Type: typeString, Enum: []any{"celsius"}},
+			wantErr: true,
+		},
+		{
+			name:    "default not in enum",
+			tag:     `default:"kelvin"`,
+			input:   FieldType{Kind: FieldKindReference, Type: "Unit"},
+			wantErr: true,
+		},
+		{
+			name:    "default on object reference",
+			tag:     `default:"x"`,
+			input:   FieldType{Kind: FieldKindReference, Type: "User"},
+			wantErr: true,
+		},
+		{
+			name:    "default on array",
+			tag:     `default:"x"`,
+			input:   FieldType{Kind: FieldKindArray, Type: "array"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := g.parseDefaultTag(newTaggedField(tt.tag), tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDefaultTag(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseDefaultTag(%q) = %#v, want %#v", tt.tag, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyDefault(t *testing.T) {
+	t.Parallel()
+
+	inline, err := applyDefault(&openapi3.SchemaRef{Value: openapi3.NewStringSchema()}, "hello")
+	if err != nil {
+		t.Fatalf("applyDefault() error = %v", err)
+	}
+
+	if inline.Value.Default != "hello" {
+		t.Errorf("inline default = %v, want %q", inline.Value.Default, "hello")
+	}
+
+	ref, err := applyDefault(openapi3.NewSchemaRef("#/components/schemas/Unit", nil), "celsius")
+	if err != nil {
+		t.Fatalf("applyDefault() error = %v", err)
+	}
+
+	if ref.Ref != "" || len(ref.Value.AllOf) != 1 || ref.Value.Default != "celsius" {
+		t.Errorf("ref default was not wrapped in allOf: %+v", ref.Value)
+	}
+}
+
+func TestBuildExternalTypeFormats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		custom  map[string]ExternalTypeFormat
+		key     string
+		want    ExternalTypeFormat
+		wantErr bool
+	}{
+		{
+			name: "built-in defaults",
+			key:  "time.Time",
+			want: ExternalTypeFormat{Type: typeString, Format: FormatDateTime},
+		},
+		{
+			name: "custom uuid",
+			custom: map[string]ExternalTypeFormat{
+				"github.com/google/uuid.UUID": {Type: typeString, Format: "uuid"},
+			},
+			key:  "github.com/google/uuid.UUID",
+			want: ExternalTypeFormat{Type: typeString, Format: "uuid"},
+		},
+		{
+			name: "custom overrides built-in",
+			custom: map[string]ExternalTypeFormat{
+				"time.Time": {Type: typeInteger, Format: "int64"},
+			},
+			key:  "time.Time",
+			want: ExternalTypeFormat{Type: typeInteger, Format: "int64"},
+		},
+		{
+			name: "unsupported type",
+			custom: map[string]ExternalTypeFormat{
+				"github.com/shopspring/decimal.Decimal": {Type: "object"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pattern on non-string type",
+			custom: map[string]ExternalTypeFormat{
+				"github.com/shopspring/decimal.Decimal": {Type: typeNumber, Pattern: "^[0-9]+$"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid pattern",
+			custom: map[string]ExternalTypeFormat{
+				"cloud.google.com/go/civil.Date": {Type: typeString, Pattern: "["},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing package path",
+			custom: map[string]ExternalTypeFormat{
+				"UUID": {Type: typeString},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := buildExternalTypeFormats(tt.custom)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildExternalTypeFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got[tt.key] != tt.want {
+				t.Errorf("buildExternalTypeFormats()[%q] = %+v, want %+v", tt.key, got[tt.key], tt.want)
+			}
+		})
+	}
+}
+
+func TestExternalDurationField(t *testing.T) {
+	t.Parallel()
+
+	src := `package telemetry
+
+import "time"
+
+type Report struct {
+	// Interval between samples.
+	Interval time.Duration ` + "`json:\"interval\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	schema, err := toOpenAPISchema(g.types["Report"])
+	if err != nil {
+		t.Fatalf("toOpenAPISchema() error = %v", err)
+	}
+
+	interval := schema.Properties["interval"].Value
+	if !interval.Type.Is(typeInteger) || interval.Format != "int64" {
+		t.Errorf("interval schema = %s/%s, want integer/int64", interval.Type, interval.Format)
+	}
+
+	if interval.Description != "Interval between samples. Duration in nanoseconds." {
+		t.Errorf("interval description = %q", interval.Description)
+	}
+}
+
+func TestReadOnlyWriteOnlyFields(t *testing.T) {
+	t.Parallel()
+
+	src := `package accounts
+
+type Account struct {
+	// ID of the account
+	ID string ` + "`json:\"id\" openapi:\"readOnly\"`" + `
+	// Password of the account
+	Password string ` + "`json:\"password\" openapi:\"writeOnly\"`" + `
+	// Owner of the account
+	Owner *Owner ` + "`json:\"owner,omitempty\" openapi:\"readOnly\"`" + `
+	// Name of the account
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Owner struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	schema, err := toOpenAPISchema(g.types["Account"])
+	if err != nil {
+		t.Fatalf("toOpenAPISchema() error = %v", err)
+	}
+
+	tests := []struct {
+		property      string
+		wantReadOnly  bool
+		wantWriteOnly bool
+	}{
+		{property: "id", wantReadOnly: true},
+		{property: "password", wantWriteOnly: true},
+		{property: "owner", wantReadOnly: true},
+		{property: "name"},
+	}
+
+	for _, tt := range tests {
+		prop := schema.Properties[tt.property].Value
+		if prop == nil {
+			t.Fatalf("property %s is not inline, want an inline or allOf-wrapped schema", tt.property)
+		}
+
+		if prop.ReadOnly != tt.wantReadOnly || prop.WriteOnly != tt.wantWriteOnly {
+			t.Errorf("%s readOnly/writeOnly = %v/%v, want %v/%v", tt.property, prop.ReadOnly, prop.WriteOnly, tt.wantReadOnly, tt.wantWriteOnly)
+		}
+	}
+}
+
+func TestParseOpenAPITag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tag     string
+		want    openAPITagInfo
+		wantErr bool
+	}{
+		{name: "no tag", tag: `json:"name"`},
+		{name: "readOnly", tag: `openapi:"readOnly"`, want: openAPITagInfo{readOnly: true}},
+		{name: "writeOnly", tag: `openapi:"writeOnly"`, want: openAPITagInfo{writeOnly: true}},
+		{name: "both", tag: `openapi:"readOnly,writeOnly"`, wantErr: true},
+		{name: "unknown option", tag: `openapi:"hidden"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseOpenAPITag(newTaggedField(tt.tag))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOpenAPITag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseOpenAPITag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowAny(t *testing.T) {
+	t.Parallel()
+
+	src := `package events
+
+type Event struct {
+	// Payload of the event
+	Payload any ` + "`json:\"payload\"`" + `
+	// Metadata of the event
+	Metadata map[string]any ` + "`json:\"metadata\"`" + `
+	// Extra data of the event
+	Extra interface{} ` + "`json:\"extra\"`" + `
+}
+`
+
+	t.Run("rejected by default", func(t *testing.T) {
+		t.Parallel()
+
+		g, _ := newSourceTestCollector(t, src)
+
+		if err := g.extractAllTypesFromGo(g.goParser); err == nil {
+			t.Fatal("extractAllTypesFromGo() error = nil, want error for 'any'")
+		}
+	})
+
+	t.Run("free-form object when allowed", func(t *testing.T) {
+		t.Parallel()
+
+		g, _ := newSourceTestCollector(t, src)
+		g.allowAny = true
+
+		if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+			t.Fatalf("extractAllTypesFromGo() error = %v", err)
+		}
+
+		schema, err := toOpenAPISchema(g.types["Event"])
+		if err != nil {
+			t.Fatalf("toOpenAPISchema() error = %v", err)
+		}
+
+		for _, property := range []string{"payload", "extra"} {
+			prop := schema.Properties[property].Value
+			if prop == nil || !prop.Type.Is("object") {
+				t.Fatalf("%s schema = %+v, want an object", property, prop)
+			}
+
+			if prop.AdditionalProperties.Has == nil || !*prop.AdditionalProperties.Has {
+				t.Errorf("%s additionalProperties = %+v, want true", property, prop.AdditionalProperties)
+			}
+		}
+
+		metadata := schema.Properties["metadata"].Value
+		if metadata == nil || metadata.AdditionalProperties.Schema == nil {
+			t.Fatalf("metadata schema = %+v, want a map schema", metadata)
+		}
+
+		value := metadata.AdditionalProperties.Schema.Value
+		if !value.Type.Is("object") || value.AdditionalProperties.Has == nil || !*value.AdditionalProperties.Has {
+			t.Errorf("metadata value schema = %+v, want a free-form object", value)
+		}
+
+		if got := g.types["Event"].Fields[0].DisplayType; got != "any" {
+			t.Errorf("payload display type = %q, want %q", got, "any")
+		}
+	})
+}
+
+func TestDuplicateJSONFieldNames(t *testing.T) {
+	t.Parallel()
+
+	src := `package users
+
+type User struct {
+	// Name of the user
+	Name string ` + "`json:\"name\"`" + `
+	// DisplayName of the user
+	DisplayName string ` + "`json:\"name\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	err := g.extractAllTypesFromGo(g.goParser)
+	if err == nil {
+		t.Fatal("extractAllTypesFromGo() error = nil, want duplicate JSON name error")
+	}
+
+	for _, want := range []string{"User.Name", "User.DisplayName", `"name"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to mention %s", err, want)
+		}
+	}
+}
+
+func TestAdditionalPropertiesDirective(t *testing.T) {
+	t.Parallel()
+
+	src := `package types
+
+// Strict is a struct that rejects unknown properties.
+type Strict struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// Labels is a struct that accepts unknown properties.
+//
+//openapi:additionalProperties
+type Labels struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	tests := []struct {
+		typeName string
+		wantHas  bool
+		wantDesc string
+	}{
+		{typeName: "Strict", wantHas: false, wantDesc: "Strict is a struct that rejects unknown properties."},
+		{typeName: "Labels", wantHas: true, wantDesc: "Labels is a struct that accepts unknown properties."},
+	}
+
+	for _, tt := range tests {
+		schema, err := toOpenAPISchema(g.types[tt.typeName])
+		if err != nil {
+			t.Fatalf("toOpenAPISchema(%s) error = %v", tt.typeName, err)
+		}
+
+		if has := schema.AdditionalProperties.Has; has == nil || *has != tt.wantHas {
+			t.Errorf("%s additionalProperties = %v, want %v", tt.typeName, has, tt.wantHas)
+		}
+
+		if schema.Description != tt.wantDesc {
+			t.Errorf("%s description = %q, want %q", tt.typeName, schema.Description, tt.wantDesc)
+		}
+	}
+
+	invalid := map[string]string{
+		"unknown directive": `package types
+
+//openapi:strict
+type Item struct{}
+`,
+		"non-struct type": `package types
+
+//openapi:additionalProperties
+type Name string
+`,
+	}
+
+	for name, src := range invalid {
+		g, _ := newSourceTestCollector(t, src)
+
+		if err := g.extractAllTypesFromGo(g.goParser); err == nil {
+			t.Errorf("%s: extractAllTypesFromGo() error = nil, want error", name)
+		}
+	}
+}
+
+func TestInlineStructField(t *testing.T) {
+	t.Parallel()
+
+	src := `package types
+
+// Status is a status.
+type Status string
+
+// Device is a device.
+type Device struct {
+	// Meta holds device metadata
+	Meta struct {
+		// Vendor of the device
+		Vendor string ` + "`json:\"vendor\"`" + `
+		Model  *string ` + "`json:\"model\"`" + `
+		Status Status ` + "`json:\"status\"`" + `
+	} ` + "`json:\"meta\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	if !slices.Equal(g.types["Device"].References, []string{"Status"}) {
+		t.Errorf("Device references = %v, want [Status]", g.types["Device"].References)
+	}
+
+	schema, err := toOpenAPISchema(g.types["Device"])
+	if err != nil {
+		t.Fatalf("toOpenAPISchema() error = %v", err)
+	}
+
+	meta := schema.Properties["meta"].Value
+	if !meta.Type.Is("object") || meta.Description != "Meta holds device metadata" {
+		t.Fatalf("meta schema = %+v, want a described inline object", meta)
+	}
+
+	if has := meta.AdditionalProperties.Has; has == nil || *has {
+		t.Errorf("meta additionalProperties = %v, want false", has)
+	}
+
+	if !slices.Equal(meta.Required, []string{"vendor", "status"}) {
+		t.Errorf("meta required = %v, want [vendor status]", meta.Required)
+	}
+
+	if vendor := meta.Properties["vendor"].Value; !vendor.Type.Is(typeString) || vendor.Description != "Vendor of the device" {
+		t.Errorf("meta.vendor schema = %+v, want a described string", vendor)
+	}
+
+	if model := meta.Properties["model"].Value; !model.Nullable {
+		t.Errorf("meta.model schema = %+v, want nullable", model)
+	}
+
+	if status := meta.Properties["status"]; status.Ref != "#/components/schemas/Status" {
+		t.Errorf("meta.status $ref = %q, want #/components/schemas/Status", status.Ref)
+	}
+}
+
+func TestExternalTypeAlias(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		src     string
+		wantErr bool
+	}{
+		{
+			name: "alias declaration",
+			src: `package types
+
+import "time"
+
+// Timestamp is a point in time.
+type Timestamp = time.Time
+`,
+		},
+		{
+			name: "defined type with its own JSON encoding",
+			src: `package types
+
+import "time"
+
+// Timestamp is a point in time.
+type Timestamp time.Time
+
+func (t Timestamp) MarshalJSON() ([]byte, error) { return time.Time(t).MarshalJSON() }
+`,
+		},
+		{
+			name: "defined type without JSON encoding",
+			src: `package types
+
+import "time"
+
+// Timestamp is a point in time.
+type Timestamp time.Time
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, _ := newSourceTestCollector(t, tt.src)
+
+			err := g.extractAllTypesFromGo(g.goParser)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractAllTypesFromGo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), "json.Marshaler") {
+					t.Errorf("extractAllTypesFromGo() error = %v, want a JSON encoding error", err)
+				}
+
+				return
+			}
+
+			typeInfo := g.types["Timestamp"]
+			if typeInfo.Kind != TypeKindAlias {
+				t.Errorf("kind = %q, want %q", typeInfo.Kind, TypeKindAlias)
+			}
+
+			schema, err := toOpenAPISchema(typeInfo)
+			if err != nil {
+				t.Fatalf("toOpenAPISchema() error = %v", err)
+			}
+
+			if !schema.Type.Is(typeString) || schema.Format != "date-time" || schema.Description != "Timestamp is a point in time." {
+				t.Errorf("schema = %+v, want a described date-time string", schema)
+			}
+		})
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
+	"go/types"
 	"log/slog"
 	"strconv"
+	"strings"
 )
 
 // Sentinel errors for enum processing.
@@ -66,9 +69,17 @@ func (g *OpenAPICollector) extractEnumsFromConstBlock(constDecl *ast.GenDecl) er
 		}
 
 		// Check if all names in this spec are exported
+		// Blank identifiers are allowed to skip iota positions
 		allExported := true
+		allBlank := true
 
 		for _, name := range valueSpec.Names {
+			if name.Name == "_" {
+				continue
+			}
+
+			allBlank = false
+
 			if !name.IsExported() {
 				allExported = false
 
@@ -76,6 +87,11 @@ func (g *OpenAPICollector) extractEnumsFromConstBlock(constDecl *ast.GenDecl) er
 			}
 		}
 
+		// Untyped blank specs only skip iota positions, there is nothing to process
+		if allBlank && valueSpec.Type == nil {
+			continue
+		}
+
 		// If we've identified this as an enum block, all constants must be exported
 		if enumTypeName != "" && !allExported {
 			return fmt.Errorf("enum %s: const blocks must not contain unexported constants", enumTypeName)
@@ -86,6 +102,18 @@ func (g *OpenAPICollector) extractEnumsFromConstBlock(constDecl *ast.GenDecl) er
 			continue
 		}
 
+		// Implicit repetition of the previous spec (e.g. iota sequences) inherits the enum type
+		if valueSpec.Type == nil && len(valueSpec.Values) == 0 && enumTypeName != "" {
+			values, err := g.processValueSpec(valueSpec, enumTypeName)
+			if err != nil {
+				return err
+			}
+
+			enumValues = append(enumValues, values...)
+
+			continue
+		}
+
 		// All exported constants must have explicit type declaration
 		if valueSpec.Type == nil {
 			if enumTypeName == "" {
@@ -149,48 +177,27 @@ func (g *OpenAPICollector) processValueSpec(valueSpec *ast.ValueSpec, enumTypeNa
 // processEnumValue processes a single enum constant value and returns the EnumValue.
 // The index parameter maps the const name to its corresponding value in valueSpec.Values.
 // For example, in `const (Foo = "foo"; Bar = "bar")`, index 0 maps Foo to "foo".
+// Constants using iota, or implicitly repeating the previous iota expression, are evaluated with go/types.
 func (g *OpenAPICollector) processEnumValue(valueSpec *ast.ValueSpec, index int, name *ast.Ident, enumTypeName string) (EnumValue, error) {
-	if index >= len(valueSpec.Values) {
-		return EnumValue{}, fmt.Errorf("enum constant %s.%s is missing a value", enumTypeName, name.Name)
-	}
-
-	basicLit, ok := valueSpec.Values[index].(*ast.BasicLit)
-	if !ok {
-		return EnumValue{}, fmt.Errorf("enum constant %s.%s must have a literal value, got %T", enumTypeName, name.Name, valueSpec.Values[index])
-	}
-
-	var value any
-
-	//nolint:exhaustive // Only STRING and INT literals are valid for enum constants
-	switch basicLit.Kind {
-	case token.STRING:
-		strVal, err := strconv.Unquote(basicLit.Value)
-		if err != nil {
-			return EnumValue{}, fmt.Errorf("enum constant %s.%s has invalid string value %s: %w", enumTypeName, name.Name, basicLit.Value, err)
-		}
-
-		value = strVal
-
-	case token.INT:
-		intVal, err := strconv.ParseInt(basicLit.Value, 10, 64)
-		if err != nil {
-			return EnumValue{}, fmt.Errorf("enum constant %s.%s has invalid integer value %s: %w", enumTypeName, name.Name, basicLit.Value, err)
-		}
-
-		value = intVal
+	var (
+		value any
+		err   error
+	)
 
+	switch {
+	case len(valueSpec.Values) == 0 || containsIota(valueSpec.Values[index]):
+		value, err = g.evaluateIotaConstant(name, enumTypeName)
 	default:
-		return EnumValue{}, fmt.Errorf("enum constant %s.%s must be a string or integer, got %v", enumTypeName, name.Name, basicLit.Kind)
+		value, err = parseEnumLiteral(valueSpec.Values[index], name, enumTypeName)
 	}
 
-	// Extract documentation
-	desc := ""
-	if valueSpec.Doc != nil {
-		desc = g.extractCommentsFromDoc(valueSpec.Doc)
-	} else if valueSpec.Comment != nil {
-		desc = g.extractCommentsFromDoc(valueSpec.Comment)
+	if err != nil {
+		return EnumValue{}, err
 	}
 
+	// Extract documentation from the comment above the constant and its trailing comment, in that order
+	desc := strings.TrimSpace(g.extractCommentsFromDoc(valueSpec.Doc) + " " + g.extractCommentsFromDoc(valueSpec.Comment))
+
 	deprecated, cleanedDesc, err := g.parseDeprecation(desc)
 	if err != nil {
 		return EnumValue{}, fmt.Errorf("failed to parse deprecation for enum value %s.%s: %w", enumTypeName, name.Name, err)
@@ -246,3 +253,84 @@ func (g *OpenAPICollector) storeEnumType(enumTypeName string, enumValues []EnumV
 
 	return nil
 }
+
+// parseEnumLiteral parses a literal enum constant value into a string or int64.
+func parseEnumLiteral(expr ast.Expr, name *ast.Ident, enumTypeName string) (any, error) {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return nil, fmt.Errorf("enum constant %s.%s must have a literal or iota value, got %T", enumTypeName, name.Name, expr)
+	}
+
+	//nolint:exhaustive // Only STRING and INT literals are valid for enum constants
+	switch basicLit.Kind {
+	case token.STRING:
+		strVal, err := strconv.Unquote(basicLit.Value)
+		if err != nil {
+			return nil, fmt.Errorf("enum constant %s.%s has invalid string value %s: %w", enumTypeName, name.Name, basicLit.Value, err)
+		}
+
+		return strVal, nil
+
+	case token.INT:
+		intVal, err := strconv.ParseInt(basicLit.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("enum constant %s.%s has invalid integer value %s: %w", enumTypeName, name.Name, basicLit.Value, err)
+		}
+
+		return intVal, nil
+
+	default:
+		return nil, fmt.Errorf("enum constant %s.%s must be a string or integer, got %v", enumTypeName, name.Name, basicLit.Kind)
+	}
+}
+
+// containsIota reports whether the expression references the predeclared iota identifier.
+func containsIota(expr ast.Expr) bool {
+	found := false
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == "iota" {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// evaluateIotaConstant resolves the computed value of an iota-based constant from the type-checked packages.
+func (g *OpenAPICollector) evaluateIotaConstant(name *ast.Ident, enumTypeName string) (any, error) {
+	if g.goParser == nil {
+		return nil, fmt.Errorf("enum constant %s.%s: type information is not available to evaluate iota", enumTypeName, name.Name)
+	}
+
+	for _, pkg := range g.goParser.packages {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+
+		obj, ok := pkg.TypesInfo.Defs[name]
+		if !ok {
+			continue
+		}
+
+		c, ok := obj.(*types.Const)
+		if !ok {
+			return nil, fmt.Errorf("enum constant %s.%s is not a constant", enumTypeName, name.Name)
+		}
+
+		if c.Val().Kind() != constant.Int {
+			return nil, fmt.Errorf("enum constant %s.%s: iota expressions must evaluate to an integer, got %v", enumTypeName, name.Name, c.Val().Kind())
+		}
+
+		intVal, exact := constant.Int64Val(c.Val())
+		if !exact {
+			return nil, fmt.Errorf("enum constant %s.%s: value %s overflows int64", enumTypeName, name.Name, c.Val().ExactString())
+		}
+
+		return intVal, nil
+	}
+
+	return nil, fmt.Errorf("enum constant %s.%s: no type information found", enumTypeName, name.Name)
+}
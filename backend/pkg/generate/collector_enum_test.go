@@ -0,0 +1,275 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// newSourceTestCollector type-checks the given source and returns a collector ready to extract its declarations.
+func newSourceTestCollector(t *testing.T, src string) (*OpenAPICollector, *ast.File) {
+	t.Helper()
+
+	g, files := newSourcesTestCollector(t, src)
+
+	return g, files[0]
+}
+
+// newSourcesTestCollector type-checks the given sources as files of one package, in order,
+// and returns a collector ready to extract their declarations.
+func newSourcesTestCollector(t *testing.T, srcs ...string) (*OpenAPICollector, []*ast.File) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	files := make([]*ast.File, 0, len(srcs))
+
+	for i, src := range srcs {
+		file, err := parser.ParseFile(fset, fmt.Sprintf("source%d.go", i), src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed to parse source: %v", err)
+		}
+
+		files = append(files, file)
+	}
+
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{Importer: importer.Default()}
+
+	typesPkg, err := conf.Check(files[0].Name.Name, fset, files, info)
+	if err != nil {
+		t.Fatalf("failed to type-check source: %v", err)
+	}
+
+	imports := make(map[string]*packages.Package)
+	for _, imp := range typesPkg.Imports() {
+		imports[imp.Path()] = &packages.Package{Name: imp.Name(), PkgPath: imp.Path()}
+	}
+
+	g := &OpenAPICollector{
+		l:                    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		types:                make(map[string]*TypeInfo),
+		typeASTs:             make(map[string]*ast.GenDecl),
+		constASTs:            make(map[string]*ast.GenDecl),
+		patchTypes:           make(map[string]string),
+		currentFileImports:   make(map[string]string),
+		externalTypeFormats:  getExternalTypeMappings(),
+		primitiveTypeMapping: getPrimitiveTypeMappings(),
+		goParser: &GoParser{
+			fset:     fset,
+			files:    files,
+			packages: []*packages.Package{{Syntax: files, TypesInfo: info, Imports: imports}},
+		},
+	}
+
+	return g, files
+}
+
+func TestExtractConstDeclarations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      string
+		enumName string
+		expected []any
+		wantErr  bool
+	}{
+		{
+			name: "string literals",
+			src: `package enums
+type Unit string
+const (
+	UnitCelsius Unit = "celsius"
+	UnitFahrenheit Unit = "fahrenheit"
+)`,
+			enumName: "Unit",
+			expected: []any{"celsius", "fahrenheit"},
+		},
+		{
+			name: "iota sequence",
+			src: `package enums
+type Priority int
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	PriorityHigh
+)`,
+			enumName: "Priority",
+			expected: []any{int64(0), int64(1), int64(2)},
+		},
+		{
+			name: "iota plus one",
+			src: `package enums
+type Priority int
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+)`,
+			enumName: "Priority",
+			expected: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name: "skipped positions",
+			src: `package enums
+type Priority int
+const (
+	_ Priority = iota
+	PriorityLow
+	_
+	PriorityHigh
+)`,
+			enumName: "Priority",
+			expected: []any{int64(1), int64(3)},
+		},
+		{
+			name: "non literal expression",
+			src: `package enums
+type Size int
+const base = 10
+const (
+	SizeSmall Size = base * 2
+)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, file := newSourceTestCollector(t, tt.src)
+
+			err := g.extractConstDeclarations(file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractConstDeclarations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			typeInfo, ok := g.types[tt.enumName]
+			if !ok {
+				t.Fatalf("enum %s not extracted", tt.enumName)
+			}
+
+			var got []any
+			for _, ev := range typeInfo.EnumValues {
+				got = append(got, ev.Value)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("enum values = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractNumberEnumComments(t *testing.T) {
+	t.Parallel()
+
+	src := `package enums
+type Priority int
+const (
+	// Lowest priority
+	PriorityLow Priority = iota + 1
+	PriorityMedium // Default priority
+	// Urgent work
+	PriorityHigh // Preempts other work
+	PriorityCritical // Deprecated: use PriorityHigh
+	// Old name of PriorityLow
+	PriorityMinor Priority = 10 // Deprecated: use PriorityLow
+	PriorityNone Priority = 0
+)`
+
+	g, file := newSourceTestCollector(t, src)
+
+	if err := g.extractConstDeclarations(file); err != nil {
+		t.Fatalf("extractConstDeclarations() error = %v", err)
+	}
+
+	typeInfo := g.types["Priority"]
+	if typeInfo == nil || typeInfo.Kind != TypeKindNumberEnum {
+		t.Fatalf("Priority = %+v, want a number enum", typeInfo)
+	}
+
+	expected := []EnumValue{
+		{Value: int64(1), Description: "Lowest priority"},
+		{Value: int64(2), Description: "Default priority"},
+		{Value: int64(3), Description: "Urgent work Preempts other work"},
+		{Value: int64(4), Deprecated: "use PriorityHigh"},
+		{Value: int64(10), Description: "Old name of PriorityLow", Deprecated: "use PriorityLow"},
+		{Value: int64(0)},
+	}
+
+	if !reflect.DeepEqual(typeInfo.EnumValues, expected) {
+		t.Errorf("enum values = %+v, want %+v", typeInfo.EnumValues, expected)
+	}
+}
+
+func TestExtractEnumAcrossFiles(t *testing.T) {
+	t.Parallel()
+
+	typeSrc := `package units
+
+// Unit is a temperature unit.
+type Unit string
+`
+
+	constSrc := `package units
+
+const (
+	Celsius    Unit = "celsius"    // Degrees Celsius
+	Fahrenheit Unit = "fahrenheit" // Degrees Fahrenheit
+)
+`
+
+	tests := []struct {
+		name string
+		srcs []string
+	}{
+		{name: "type first", srcs: []string{typeSrc, constSrc}},
+		{name: "consts first", srcs: []string{constSrc, typeSrc}},
+	}
+
+	expected := []EnumValue{
+		{Value: "celsius", Description: "Degrees Celsius"},
+		{Value: "fahrenheit", Description: "Degrees Fahrenheit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, _ := newSourcesTestCollector(t, tt.srcs...)
+
+			if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+				t.Fatalf("extractAllTypesFromGo() error = %v", err)
+			}
+
+			typeInfo := g.types["Unit"]
+			if typeInfo == nil || typeInfo.Kind != TypeKindStringEnum {
+				t.Fatalf("Unit = %+v, want a string enum", typeInfo)
+			}
+
+			if typeInfo.Description != "Unit is a temperature unit." {
+				t.Errorf("Unit.Description = %q, want the type doc comment", typeInfo.Description)
+			}
+
+			if !reflect.DeepEqual(typeInfo.EnumValues, expected) {
+				t.Errorf("enum values = %+v, want %+v", typeInfo.EnumValues, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package generate
+
+// This file handles patch types, the all-optional variants of object types used by partial updates.
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/printer"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// patchTypeSuffix is appended to the base type name to name its patch type (e.g., TeamPatch).
+const patchTypeSuffix = "Patch"
+
+// RegisterPatchType registers the patch type of an object type, named after it with a "Patch" suffix (e.g., TeamPatch).
+// Every field of the patch type is optional and nullable, as in a JSON merge patch.
+// Registering the same base type again is a no-op. Returns the patch type name.
+func (g *OpenAPICollector) RegisterPatchType(base any) (string, error) {
+	baseName, err := extractTypeNameFromValue(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract patch base type name: %w", err)
+	}
+
+	patchName := baseName + patchTypeSuffix
+
+	if registeredBase, ok := g.patchTypes[patchName]; ok && registeredBase == baseName {
+		return patchName, nil
+	}
+
+	if _, exists := g.types[patchName]; exists {
+		return "", fmt.Errorf("patch type %s of %s conflicts with an existing type", patchName, baseName)
+	}
+
+	baseInfo, ok := g.types[baseName]
+	if !ok {
+		return "", fmt.Errorf("patch base type %s not found in types map", baseName)
+	}
+
+	if baseInfo.Kind != TypeKindObject {
+		return "", fmt.Errorf("patch base type %s must be an object, got %s", baseName, baseInfo.Kind)
+	}
+
+	patchInfo, err := buildPatchTypeInfo(patchName, baseInfo)
+	if err != nil {
+		return "", err
+	}
+
+	goSource, err := g.generatePatchGoSource(patchName, baseName)
+	if err != nil {
+		return "", err
+	}
+
+	patchInfo.Representations = Representations{
+		JSON: string(utils.MustToJSONIndent(base)),
+		Go:   goSource,
+		TS:   fmt.Sprintf("export type %s = { [K in keyof %s]?: %s[K] | null };\n", patchName, baseName, baseName),
+	}
+
+	g.types[patchName] = patchInfo
+	g.patchTypes[patchName] = baseName
+
+	return patchName, nil
+}
+
+// buildPatchTypeInfo copies the base type info with every field made optional and nullable.
+// Defaults are dropped, an absent field leaves the current value unchanged.
+func buildPatchTypeInfo(patchName string, baseInfo *TypeInfo) (*TypeInfo, error) {
+	patchInfo := &TypeInfo{
+		Name:        patchName,
+		Kind:        TypeKindObject,
+		Description: fmt.Sprintf("Partial update of %s, absent fields are left unchanged.", baseInfo.Name),
+		Deprecated:  baseInfo.Deprecated,
+		Fields:      make([]FieldInfo, 0, len(baseInfo.Fields)),
+		References:  baseInfo.References,
+
+		AllowAdditionalProperties: baseInfo.AllowAdditionalProperties,
+	}
+
+	for _, field := range baseInfo.Fields {
+		field.TypeInfo.Required = false
+		field.TypeInfo.Nullable = true
+		field.Default = nil
+
+		displayType, err := generateDisplayType(field.TypeInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate display type for field %s.%s: %w", patchName, field.Name, err)
+		}
+
+		field.DisplayType = displayType
+		patchInfo.Fields = append(patchInfo.Fields, field)
+	}
+
+	return patchInfo, nil
+}
+
+// generatePatchGoSource generates the Go source of a patch type from the base type declaration.
+// Non-pointer fields become pointers and every field is omitempty.
+func (g *OpenAPICollector) generatePatchGoSource(patchName, baseName string) (string, error) {
+	structType, err := g.findStructType(baseName)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// %s is a partial update of %s.\ntype %s struct {\n", patchName, baseName, patchName)
+
+	for _, field := range structType.Fields.List {
+		for _, fieldName := range field.Names {
+			tagInfo := parseJSONTag(field, fieldName.Name)
+			if !fieldName.IsExported() || tagInfo.skip {
+				continue
+			}
+
+			var fieldType bytes.Buffer
+			if err := printer.Fprint(&fieldType, g.goParser.fset, field.Type); err != nil {
+				return "", fmt.Errorf("failed to print type of field %s.%s: %w", baseName, fieldName.Name, err)
+			}
+
+			pointer := "*"
+			if _, isPointer := field.Type.(*ast.StarExpr); isPointer {
+				pointer = ""
+			}
+
+			if field.Doc != nil {
+				for _, comment := range field.Doc.List {
+					buf.WriteString(comment.Text + "\n")
+				}
+			}
+
+			fmt.Fprintf(&buf, "%s %s%s `json:\"%s,omitempty\"`\n", fieldName.Name, pointer, fieldType.String(), tagInfo.name)
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format Go source for patch type %s: %w", patchName, err)
+	}
+
+	return string(formatted), nil
+}
+
+// findStructType returns the struct declaration of a type.
+func (g *OpenAPICollector) findStructType(typeName string) (*ast.StructType, error) {
+	genDecl, ok := g.typeASTs[typeName]
+	if !ok {
+		return nil, fmt.Errorf("no type declaration AST found for type %s", typeName)
+	}
+
+	for _, spec := range genDecl.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if !ok || typeSpec.Name.Name != typeName {
+			continue
+		}
+
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct", typeName)
+		}
+
+		return structType, nil
+	}
+
+	return nil, fmt.Errorf("no type spec found for type %s", typeName)
+}
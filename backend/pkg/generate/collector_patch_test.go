@@ -0,0 +1,116 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+// Team mirrors the Team type in the patch test source, so its value resolves to that type.
+type Team struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	Owner   *string  `json:"owner"`
+}
+
+func TestRegisterPatchType(t *testing.T) {
+	t.Parallel()
+
+	src := `package types
+
+// Role is a role.
+type Role string
+
+// Team is a team.
+type Team struct {
+	// ID of the team
+	ID string ` + "`json:\"id\" openapi:\"readOnly\"`" + `
+	// Name of the team
+	Name string ` + "`json:\"name\" validate:\"min=3\" default:\"My Team\"`" + `
+	// Members of the team
+	Members []string ` + "`json:\"members\"`" + `
+	// Owner of the team
+	Owner *string ` + "`json:\"owner\"`" + `
+	// Role of the team
+	Role Role ` + "`json:\"role\"`" + `
+	internal string
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	patchName, err := g.RegisterPatchType(Team{})
+	if err != nil {
+		t.Fatalf("RegisterPatchType() error = %v", err)
+	}
+
+	if patchName != "TeamPatch" {
+		t.Fatalf("RegisterPatchType() = %q, want TeamPatch", patchName)
+	}
+
+	if again, err := g.RegisterPatchType(Team{}); err != nil || again != patchName {
+		t.Errorf("RegisterPatchType() again = %q, %v, want %q, nil", again, err, patchName)
+	}
+
+	patchInfo := g.types[patchName]
+	if len(patchInfo.Fields) != len(g.types["Team"].Fields) {
+		t.Fatalf("patch fields = %d, want %d", len(patchInfo.Fields), len(g.types["Team"].Fields))
+	}
+
+	for _, field := range patchInfo.Fields {
+		if field.TypeInfo.Required || !field.TypeInfo.Nullable || field.Default != nil {
+			t.Errorf("patch field %s = %+v, want optional, nullable and no default", field.Name, field.TypeInfo)
+		}
+	}
+
+	if g.types["Team"].Fields[1].TypeInfo.Nullable {
+		t.Error("registering the patch type changed the base type fields")
+	}
+
+	schema, err := toOpenAPISchema(patchInfo)
+	if err != nil {
+		t.Fatalf("toOpenAPISchema() error = %v", err)
+	}
+
+	if len(schema.Required) != 0 {
+		t.Errorf("patch schema required = %v, want none", schema.Required)
+	}
+
+	if name := schema.Properties["name"].Value; !name.Nullable || name.MinLength != 3 {
+		t.Errorf("patch name schema = %+v, want nullable with minLength 3", name)
+	}
+
+	for _, want := range []string{"type TeamPatch struct", "ID *string `json:\"id,omitempty\"`", "Owner *string `json:\"owner,omitempty\"`", "// Role of the team"} {
+		if !strings.Contains(patchInfo.Representations.Go, want) {
+			t.Errorf("patch Go source missing %q:\n%s", want, patchInfo.Representations.Go)
+		}
+	}
+
+	if strings.Contains(patchInfo.Representations.Go, "internal") {
+		t.Errorf("patch Go source contains unexported field:\n%s", patchInfo.Representations.Go)
+	}
+
+	route := &RouteInfo{
+		OperationID: "patchTeam",
+		Method:      "PATCH",
+		Path:        "/teams/{teamID}",
+		Request:     &RequestInfo{TypeName: patchName, Required: true},
+	}
+
+	op, err := buildOperation(route, g.types)
+	if err != nil {
+		t.Fatalf("buildOperation() error = %v", err)
+	}
+
+	if ref := op.RequestBody.Value.Content.Get(ContentTypeJSON).Schema.Ref; ref != "#/components/schemas/TeamPatch" {
+		t.Errorf("request body $ref = %q, want #/components/schemas/TeamPatch", ref)
+	}
+
+	if _, err := g.RegisterPatchType(testFilter{}); err == nil {
+		t.Error("RegisterPatchType() of an unknown type expected error")
+	}
+}
@@ -3,9 +3,13 @@ package generate
 // This file handles registration and validation of HTTP routes and MQTT operations.
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"http-mqtt-boilerplate/backend/pkg/utils"
+	"maps"
+	"reflect"
+	"slices"
 )
 
 func (g *OpenAPICollector) RegisterRoute(route *RouteInfo) error {
@@ -27,7 +31,12 @@ func (g *OpenAPICollector) RegisterRoute(route *RouteInfo) error {
 			return fmt.Errorf("request TypeValue must not be nil when Request is provided in route [%s]", route.OperationID)
 		}
 
-		typeName, stringifiedExamples, err := g.processHTTPType(route.Request.TypeValue, route.Request.Examples, "request")
+		processType := g.processHTTPType
+		if route.Request.Patch {
+			processType = g.processHTTPPatchType
+		}
+
+		typeName, stringifiedExamples, err := processType(route.Request.TypeValue, route.Request.Examples, "request")
 		if err != nil {
 			return fmt.Errorf("failed to process request type in route [%s]: %w", route.OperationID, err)
 		}
@@ -40,6 +49,32 @@ func (g *OpenAPICollector) RegisterRoute(route *RouteInfo) error {
 	// Response TypeValue must be a zero-value struct (e.g., MyResponse{})
 	// This indicates the type without providing actual data (examples provide the data)
 	for statusCode, response := range route.Responses {
+		for i := range response.Headers {
+			if err := g.processHTTPHeader(&response.Headers[i]); err != nil {
+				return fmt.Errorf("failed to process header %s in route [%s] for status %d: %w", response.Headers[i].Name, route.OperationID, statusCode, err)
+			}
+		}
+
+		// Responses with several content types document each media type with its own type and examples
+		if len(response.Variants) > 0 {
+			if err := g.processContentVariants(&response); err != nil {
+				return fmt.Errorf("invalid content variants in route [%s] for status %d: %w", route.OperationID, statusCode, err)
+			}
+
+			route.Responses[statusCode] = response
+
+			continue
+		}
+
+		// Binary responses have no Go type, the body is documented as raw bytes of the given content type
+		if response.Binary {
+			if err := validateBinaryResponse(response); err != nil {
+				return fmt.Errorf("invalid binary response in route [%s] for status %d: %w", route.OperationID, statusCode, err)
+			}
+
+			continue
+		}
+
 		if isNilOrNilPointer(response.TypeValue) {
 			return fmt.Errorf("response TypeValue must not be nil in route [%s] for status %d", route.OperationID, statusCode)
 		}
@@ -60,13 +95,18 @@ func (g *OpenAPICollector) RegisterRoute(route *RouteInfo) error {
 		route.Responses[statusCode] = resp
 	}
 
+	if _, ok := g.apiInfo.TagDescriptions[route.Group]; g.strictTags && !ok {
+		return fmt.Errorf("group %q of route [%s] has no description in APIInfo.TagDescriptions", route.Group, route.OperationID)
+	}
+
+	if err := g.validateRouteSecurity(route); err != nil {
+		return fmt.Errorf("invalid security in route [%s]: %w", route.OperationID, err)
+	}
+
 	for i := range route.Parameters {
-		typeName, _, err := g.processHTTPType(route.Parameters[i].TypeValue, nil, "parameter")
-		if err != nil {
-			return fmt.Errorf("failed to process parameter type in route [%s]: %w", route.OperationID, err)
+		if err := g.processHTTPParameter(&route.Parameters[i]); err != nil {
+			return fmt.Errorf("failed to process parameter %s in route [%s]: %w", route.Parameters[i].Name, route.OperationID, err)
 		}
-
-		route.Parameters[i].TypeName = typeName
 	}
 
 	// Add operation keyed by operationID
@@ -188,20 +228,51 @@ func (g *OpenAPICollector) processHTTPType(typeValue any, examples map[string]an
 		return "", nil, fmt.Errorf("failed to register JSON representation for %s type [%s]: %w", contextMsg, typeName, err)
 	}
 
-	// Register and stringify examples if provided
-	var stringifiedExamples map[string]string
+	stringifiedExamples, err := g.processHTTPExamples(typeValue, examples, contextMsg)
+	if err != nil {
+		return "", nil, err
+	}
 
-	if examples != nil {
-		if err := g.registerExamples(examples); err != nil {
-			return "", nil, fmt.Errorf("failed to register JSON representation for %s example: %w", contextMsg, err)
-		}
+	return typeName, stringifiedExamples, nil
+}
+
+// processHTTPPatchType registers the patch type of typeValue and marks it as HTTP.
+// Examples are validated against typeValue, partial examples are valid since fields are optional.
+// Returns the patch type name.
+func (g *OpenAPICollector) processHTTPPatchType(typeValue any, examples map[string]any, contextMsg string) (string, map[string]string, error) {
+	typeName, err := g.RegisterPatchType(typeValue)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to register %s patch type: %w", contextMsg, err)
+	}
 
-		stringifiedExamples = stringifyExamples(examples)
+	// Mark as used by HTTP (for OpenAPI spec filtering), the base type is only used if referenced elsewhere
+	g.markTypeAsHTTP(typeName)
+
+	stringifiedExamples, err := g.processHTTPExamples(typeValue, examples, contextMsg)
+	if err != nil {
+		return "", nil, err
 	}
 
 	return typeName, stringifiedExamples, nil
 }
 
+// processHTTPExamples registers, validates and stringifies the examples of an HTTP type, if provided.
+func (g *OpenAPICollector) processHTTPExamples(typeValue any, examples map[string]any, contextMsg string) (map[string]string, error) {
+	if examples == nil {
+		return nil, nil
+	}
+
+	if err := g.registerExamples(examples); err != nil {
+		return nil, fmt.Errorf("failed to register JSON representation for %s example: %w", contextMsg, err)
+	}
+
+	if err := validateExamplesMatchType(typeValue, examples); err != nil {
+		return nil, fmt.Errorf("invalid %s example: %w", contextMsg, err)
+	}
+
+	return stringifyExamples(examples), nil
+}
+
 // processMQTTMessageType extracts type information and registers representations for an MQTT message.
 // Returns the type name and stringified examples.
 func (g *OpenAPICollector) processMQTTMessageType(operationID string, typeValue any, examples map[string]any, messageKind string) (typeName string, stringifiedExamples map[string]string, err error) {
@@ -229,6 +300,10 @@ func (g *OpenAPICollector) processMQTTMessageType(operationID string, typeValue
 		return "", nil, fmt.Errorf("failed to register JSON representation for example: %w", err)
 	}
 
+	if err := validateExamplesMatchType(typeValue, examples); err != nil {
+		return "", nil, fmt.Errorf("invalid example in %s [%s]: %w", messageKind, operationID, err)
+	}
+
 	// Stringify examples
 	stringifiedExamples = stringifyExamples(examples)
 
@@ -275,6 +350,25 @@ func (g *OpenAPICollector) registerExamples(examples map[string]any) error {
 	return nil
 }
 
+// validateExamplesMatchType checks that each example round-trips into the declared type.
+// This catches stale examples (e.g., of a different type) whose fields are not part of the declared type.
+func validateExamplesMatchType(typeValue any, examples map[string]any) error {
+	rt := derefType(reflect.TypeOf(typeValue))
+
+	for _, name := range slices.Sorted(maps.Keys(examples)) {
+		data, err := utils.ToJSON(examples[name])
+		if err != nil {
+			return fmt.Errorf("failed to marshal example [%s]: %w", name, err)
+		}
+
+		if err := utils.FromJSONStreamInto(bytes.NewReader(data), reflect.New(rt).Interface()); err != nil {
+			return fmt.Errorf("example [%s] does not match type %s: %w", name, rt.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 // validateUniqueOperationID checks that an operationID is not already used.
 func (g *OpenAPICollector) validateUniqueOperationID(operationID string) error {
 	if _, exists := g.mqttPublications[operationID]; exists {
@@ -365,3 +459,210 @@ func validateOperationIDFormat(operationID string) error {
 
 	return nil
 }
+
+// validateRouteSecurity validates that a route only references declared security schemes.
+func (g *OpenAPICollector) validateRouteSecurity(route *RouteInfo) error {
+	if route.Public && len(route.Security) > 0 {
+		return errors.New("public routes must not list security schemes")
+	}
+
+	for _, name := range route.Security {
+		if _, ok := g.apiInfo.SecuritySchemes[name]; !ok {
+			return fmt.Errorf("security scheme %q is not declared in APIInfo.SecuritySchemes", name)
+		}
+	}
+
+	return nil
+}
+
+// validateSecuritySchemes validates the declared security schemes and the default security.
+func validateSecuritySchemes(info APIInfo) error {
+	for name, scheme := range info.SecuritySchemes {
+		if name == "" {
+			return errors.New("security scheme name required")
+		}
+
+		switch scheme.Type {
+		case SecuritySchemeTypeHTTP:
+			if scheme.Scheme == "" {
+				return fmt.Errorf("security scheme %q: Scheme required for http schemes (e.g., bearer)", name)
+			}
+
+		case SecuritySchemeTypeAPIKey:
+			if scheme.Name == "" {
+				return fmt.Errorf("security scheme %q: Name required for apiKey schemes", name)
+			}
+
+			if !slices.Contains([]string{"header", "query", "cookie"}, scheme.In) {
+				return fmt.Errorf("security scheme %q: In must be one of header, query or cookie, got %q", name, scheme.In)
+			}
+
+		default:
+			return fmt.Errorf("security scheme %q: unsupported type %q (must be %s or %s)", name, scheme.Type, SecuritySchemeTypeHTTP, SecuritySchemeTypeAPIKey)
+		}
+	}
+
+	for _, name := range info.Security {
+		if _, ok := info.SecuritySchemes[name]; !ok {
+			return fmt.Errorf("default security scheme %q is not declared in SecuritySchemes", name)
+		}
+	}
+
+	return nil
+}
+
+// validateBinaryResponse validates a response documented as raw bytes.
+func validateBinaryResponse(response ResponseInfo) error {
+	if response.TypeValue != nil {
+		return errors.New("TypeValue must be nil for binary responses")
+	}
+
+	if len(response.Examples) > 0 {
+		return errors.New("examples are not supported for binary responses")
+	}
+
+	if response.ContentType == "" || response.ContentType == ContentTypeJSON {
+		return fmt.Errorf("ContentType must be set to a non-JSON media type for binary responses, got %q", response.ContentType)
+	}
+
+	return nil
+}
+
+// processContentVariants validates and processes the content variants of a response.
+// Each variant needs a distinct media type and a zero value struct or a string (e.g., "" for CSV) type.
+func (g *OpenAPICollector) processContentVariants(response *ResponseInfo) error {
+	if response.TypeValue != nil || response.ContentType != "" || response.Binary || len(response.Examples) > 0 {
+		return errors.New("TypeValue, ContentType, Binary and Examples must be unset when Variants are set")
+	}
+
+	contentTypes := make(map[string]struct{}, len(response.Variants))
+
+	for i, variant := range response.Variants {
+		if variant.ContentType == "" {
+			return fmt.Errorf("variant %d: ContentType required", i)
+		}
+
+		if _, exists := contentTypes[variant.ContentType]; exists {
+			return fmt.Errorf("variant %d: duplicate ContentType %s", i, variant.ContentType)
+		}
+
+		contentTypes[variant.ContentType] = struct{}{}
+
+		if isNilOrNilPointer(variant.TypeValue) {
+			return fmt.Errorf("variant %s: TypeValue must not be nil", variant.ContentType)
+		}
+
+		if _, isString := variant.TypeValue.(string); !isString && !isZeroValueStruct(variant.TypeValue) {
+			return fmt.Errorf("variant %s: TypeValue must be a zero value struct (e.g., MyResponse{}) or a string - use Examples for actual data", variant.ContentType)
+		}
+	}
+
+	for i := range response.Variants {
+		variant := &response.Variants[i]
+
+		typeName, stringifiedExamples, err := g.processHTTPType(variant.TypeValue, variant.Examples, "response")
+		if err != nil {
+			return fmt.Errorf("variant %s: %w", variant.ContentType, err)
+		}
+
+		variant.TypeName = typeName
+		variant.ExamplesStringified = stringifiedExamples
+	}
+
+	return nil
+}
+
+// processHTTPParameter resolves the type of a path, query or header parameter.
+// Go primitives are mapped to their OpenAPI type and format, named types must be enums.
+func (g *OpenAPICollector) processHTTPParameter(param *ParameterInfo) error {
+	if isNilOrNilPointer(param.TypeValue) {
+		return errors.New("parameter TypeValue must not be nil")
+	}
+
+	if param.Default != nil {
+		if param.Required {
+			return errors.New("required parameters must not have a default value")
+		}
+
+		if reflect.TypeOf(param.Default) != derefType(reflect.TypeOf(param.TypeValue)) {
+			return fmt.Errorf("default value type %T does not match parameter type %T", param.Default, param.TypeValue)
+		}
+	}
+
+	typeName, format, err := g.processHTTPScalarType(param.TypeValue, "parameter")
+	if err != nil {
+		return err
+	}
+
+	param.TypeName = typeName
+	param.Format = format
+
+	return nil
+}
+
+// processHTTPHeader resolves the type of a response header.
+// Like parameters, Go primitives are mapped to their OpenAPI type and format, named types must be enums.
+func (g *OpenAPICollector) processHTTPHeader(header *HeaderInfo) error {
+	if header.Description == "" {
+		return errors.New("header Description required")
+	}
+
+	if isNilOrNilPointer(header.TypeValue) {
+		return errors.New("header TypeValue must not be nil")
+	}
+
+	typeName, format, err := g.processHTTPScalarType(header.TypeValue, "header")
+	if err != nil {
+		return err
+	}
+
+	header.TypeName = typeName
+	header.Format = format
+
+	return nil
+}
+
+// processHTTPScalarType resolves the type of a value carried in a path, query or header.
+// Returns the OpenAPI type and format for primitives and external types, or the registered type name for enums.
+func (g *OpenAPICollector) processHTTPScalarType(typeValue any, contextMsg string) (string, string, error) {
+	typeName, err := extractTypeNameFromValue(typeValue)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to extract %s type name: %w", contextMsg, err)
+	}
+
+	if ft, isPrimitive := g.primitiveTypeMapping[typeName]; isPrimitive {
+		return ft.Type, ft.Format, nil
+	}
+
+	// External types (e.g., utils.UUID) are documented with their configured type and format
+	if rt := derefType(reflect.TypeOf(typeValue)); rt.PkgPath() != "" {
+		if format, ok := g.externalTypeFormats[rt.PkgPath()+"."+typeName]; ok {
+			return format.Type, format.Format, nil
+		}
+	}
+
+	typeInfo, ok := g.types[typeName]
+	if !ok {
+		return "", "", fmt.Errorf("%s type %s not found in types map", contextMsg, typeName)
+	}
+
+	if !isEnumKind(typeInfo.Kind) {
+		return "", "", fmt.Errorf("%s type %s must be a primitive or enum, got %s", contextMsg, typeName, typeInfo.Kind)
+	}
+
+	typeName, _, err = g.processHTTPType(typeValue, nil, contextMsg)
+	if err != nil {
+		return "", "", err
+	}
+
+	return typeName, "", nil
+}
+
+// derefType returns the element type of a pointer type, or the type itself.
+func derefType(rt reflect.Type) reflect.Type {
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	return rt
+}
@@ -1,11 +1,15 @@
 package generate
 
-// This file handles computing type relationships (References, ReferencedBy, UsedBy).
+// This file handles computing type relationships (References, ReferencedBy, UsedBy, Cyclic) and the reverse lookups built on them.
 
 import (
+	"cmp"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"sort"
+	"strings"
 )
 
 // computeTypeRelationships computes ReferencedBy and UsedBy for all types
@@ -20,6 +24,86 @@ func (g *OpenAPICollector) computeTypeRelationships() {
 	// Build UsedBy from routes
 	g.buildUsedBy()
 	g.l.Debug("Computed UsedBy relationships")
+
+	// Mark types that are part of reference cycles
+	g.detectCycles()
+	g.l.Debug("Detected reference cycles")
+}
+
+// detectCycles marks every type that is part of a reference cycle as Cyclic.
+// It uses Tarjan's strongly connected components algorithm, each type is visited exactly once.
+// A type is cyclic if its component contains more than one type or it references itself.
+func (g *OpenAPICollector) detectCycles() {
+	var (
+		index   int
+		stack   []string
+		onStack = make(map[string]bool)
+		indices = make(map[string]int)
+		lowLink = make(map[string]int)
+	)
+
+	var strongConnect func(typeName string)
+
+	strongConnect = func(typeName string) {
+		indices[typeName] = index
+		lowLink[typeName] = index
+		index++
+
+		stack = append(stack, typeName)
+		onStack[typeName] = true
+
+		for _, ref := range g.types[typeName].References {
+			if _, exists := g.types[ref]; !exists {
+				continue // Primitive or external type
+			}
+
+			if _, visited := indices[ref]; !visited {
+				strongConnect(ref)
+				lowLink[typeName] = min(lowLink[typeName], lowLink[ref])
+			} else if onStack[ref] {
+				lowLink[typeName] = min(lowLink[typeName], indices[ref])
+			}
+		}
+
+		// Not the root of a component
+		if lowLink[typeName] != indices[typeName] {
+			return
+		}
+
+		// Pop the component off the stack
+		var component []string
+
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+
+			component = append(component, last)
+
+			if last == typeName {
+				break
+			}
+		}
+
+		cyclic := len(component) > 1 || slices.Contains(g.types[typeName].References, typeName)
+		for _, name := range component {
+			g.types[name].Cyclic = cyclic
+		}
+	}
+
+	// Iterate in sorted order for deterministic traversal
+	typeNames := make([]string, 0, len(g.types))
+	for typeName := range g.types {
+		typeNames = append(typeNames, typeName)
+	}
+
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		if _, visited := indices[typeName]; !visited {
+			strongConnect(typeName)
+		}
+	}
 }
 
 // buildReferencedBy builds the inverse of References for all types.
@@ -54,67 +138,194 @@ func (g *OpenAPICollector) buildUsedBy() {
 		typeInfo.UsedBy = nil
 	}
 
+	g.forEachOperationType(g.addUsage)
+
+	// Deduplicate UsedBy entries
+	for _, typ := range g.types {
+		usages := make(map[string]struct{})
+		dedupedUsages := []UsageInfo{}
+
+		for _, usage := range typ.UsedBy {
+			key := fmt.Sprintf("%s:%s", usage.OperationID, usage.Role)
+			if _, used := usages[key]; used {
+				continue
+			}
+
+			dedupedUsages = append(dedupedUsages, usage)
+			usages[key] = struct{}{}
+		}
+
+		if len(dedupedUsages) == 0 {
+			dedupedUsages = nil
+		}
+
+		typ.UsedBy = dedupedUsages
+	}
+}
+
+// forEachOperationType calls fn for every type an operation names directly, with the role the type plays in it.
+func (g *OpenAPICollector) forEachOperationType(fn func(typeName, operationID, role string)) {
 	// Track HTTP operations
 	for _, route := range g.httpOps {
 		// Track request type
 		if route.Request != nil {
-			g.addUsage(route.Request.TypeName, route.OperationID, "request")
+			fn(route.Request.TypeName, route.OperationID, "request")
 		}
 
 		// Track response types
 		for _, resp := range route.Responses {
-			g.addUsage(resp.TypeName, route.OperationID, "response")
+			fn(resp.TypeName, route.OperationID, "response")
+
+			for _, variant := range resp.Variants {
+				fn(variant.TypeName, route.OperationID, "response")
+			}
 		}
 
 		// Track parameter types
 		for _, param := range route.Parameters {
-			g.addUsage(param.TypeName, route.OperationID, "parameter")
+			fn(param.TypeName, route.OperationID, "parameter")
 		}
 	}
 
 	// Track MQTT publications
 	for _, pub := range g.mqttPublications {
-		g.addUsage(pub.TypeName, pub.OperationID, "mqtt_publication")
+		fn(pub.TypeName, pub.OperationID, "mqtt_publication")
 	}
 
 	// Track MQTT subscriptions
 	for _, sub := range g.mqttSubscriptions {
-		g.addUsage(sub.TypeName, sub.OperationID, "mqtt_subscription")
+		fn(sub.TypeName, sub.OperationID, "mqtt_subscription")
 	}
+}
 
-	// Deduplicate UsedBy entries
-	for _, typ := range g.types {
-		usages := make(map[string]struct{})
-		dedupedUsages := []UsageInfo{}
+// addUsage adds a UsageInfo entry to a type if it exists.
+func (g *OpenAPICollector) addUsage(typeName, operationID, role string) {
+	if typeName == "" {
+		return
+	}
 
-		for _, usage := range typ.UsedBy {
-			key := fmt.Sprintf("%s:%s", usage.OperationID, usage.Role)
-			if _, used := usages[key]; used {
+	if typeInfo, exists := g.types[typeName]; exists {
+		typeInfo.UsedBy = append(typeInfo.UsedBy, UsageInfo{
+			OperationID: operationID,
+			Role:        role,
+		})
+	}
+}
+
+// TypesUsingType returns the names of the types that reference the named type, directly or through other types, sorted.
+// These are the types affected by a change to it. The type itself is only included when it is part of a reference cycle.
+// It is computed from the collected types, so it can be called before [OpenAPICollector.Generate].
+func (g *OpenAPICollector) TypesUsingType(name string) []string {
+	// Invert References, ReferencedBy is only filled in by Generate
+	referencedBy := make(map[string][]string)
+
+	for typeName, typeInfo := range g.types {
+		for _, ref := range typeInfo.References {
+			referencedBy[ref] = append(referencedBy[ref], typeName)
+		}
+	}
+
+	users := make(map[string]struct{})
+	queue := []string{name}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, user := range referencedBy[current] {
+			if _, seen := users[user]; seen {
 				continue
 			}
 
-			dedupedUsages = append(dedupedUsages, usage)
-			usages[key] = struct{}{}
+			users[user] = struct{}{}
+			queue = append(queue, user)
 		}
+	}
 
-		if len(dedupedUsages) == 0 {
-			dedupedUsages = nil
+	if len(users) == 0 {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(users))
+}
+
+// OperationsUsingType returns the operations that use the named type, directly or through a type referencing it,
+// sorted by operation ID and role. Each operation and role pair is returned once.
+// It is computed from the registered operations, so it can be called before [OpenAPICollector.Generate].
+func (g *OpenAPICollector) OperationsUsingType(name string) []UsageInfo {
+	affected := map[string]struct{}{name: {}}
+	for _, typeName := range g.TypesUsingType(name) {
+		affected[typeName] = struct{}{}
+	}
+
+	seen := make(map[UsageInfo]struct{})
+
+	var usages []UsageInfo
+
+	g.forEachOperationType(func(typeName, operationID, role string) {
+		if _, ok := affected[typeName]; typeName == "" || !ok {
+			return
 		}
 
-		typ.UsedBy = dedupedUsages
+		usage := UsageInfo{OperationID: operationID, Role: role}
+		if _, ok := seen[usage]; ok {
+			return
+		}
+
+		seen[usage] = struct{}{}
+		usages = append(usages, usage)
+	})
+
+	slices.SortFunc(usages, func(a, b UsageInfo) int {
+		return cmp.Or(cmp.Compare(a.OperationID, b.OperationID), cmp.Compare(a.Role, b.Role))
+	})
+
+	return usages
+}
+
+// unusedTypes returns the names of the extracted types that no operation uses, directly or through another used type, sorted.
+// The base type of a used patch type counts as used.
+func (g *OpenAPICollector) unusedTypes() []string {
+	usedPatchBases := make(map[string]struct{})
+
+	for patchName, baseName := range g.patchTypes {
+		if typeInfo, ok := g.types[patchName]; ok && (typeInfo.UsedByHTTP || typeInfo.UsedByMQTT) {
+			usedPatchBases[baseName] = struct{}{}
+		}
 	}
+
+	var unused []string
+
+	for _, name := range slices.Sorted(maps.Keys(g.types)) {
+		typeInfo := g.types[name]
+		if typeInfo.UsedByHTTP || typeInfo.UsedByMQTT {
+			continue
+		}
+
+		if _, ok := usedPatchBases[name]; ok {
+			continue
+		}
+
+		unused = append(unused, name)
+	}
+
+	return unused
 }
 
-// addUsage adds a UsageInfo entry to a type if it exists.
-func (g *OpenAPICollector) addUsage(typeName, operationID, role string) {
-	if typeName == "" {
-		return
+// checkUnusedTypes logs the unused types when enabled, and fails in strict mode.
+func (g *OpenAPICollector) checkUnusedTypes() error {
+	if !g.warnUnusedTypes {
+		return nil
 	}
 
-	if typeInfo, exists := g.types[typeName]; exists {
-		typeInfo.UsedBy = append(typeInfo.UsedBy, UsageInfo{
-			OperationID: operationID,
-			Role:        role,
-		})
+	unused := g.unusedTypes()
+	for _, name := range unused {
+		g.l.Warn("type is not used by any operation", slog.String("type", name))
 	}
+
+	if g.strictUnused && len(unused) > 0 {
+		return fmt.Errorf("%d types are not used by any operation: %s", len(unused), strings.Join(unused, ", "))
+	}
+
+	return nil
 }
@@ -0,0 +1,239 @@
+package generate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectCycles(t *testing.T) {
+	t.Parallel()
+
+	src := `package cycles
+
+// Category is a directly recursive type.
+type Category struct {
+	Name     string     ` + "`json:\"name\"`" + `
+	Children []Category ` + "`json:\"children\"`" + `
+}
+
+// Person and Company reference each other.
+type Person struct {
+	Employer *Company ` + "`json:\"employer\"`" + `
+}
+
+type Company struct {
+	Owner *Person ` + "`json:\"owner\"`" + `
+}
+
+// Address is referenced but not cyclic.
+type Address struct {
+	Street string ` + "`json:\"street\"`" + `
+}
+
+type Site struct {
+	Address Address ` + "`json:\"address\"`" + `
+	Company Company ` + "`json:\"company\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	g.computeTypeRelationships()
+
+	// Marking must terminate on cycles
+	g.markTypeAsUsedBy("Site", ProtocolHTTP)
+
+	tests := []struct {
+		typeName     string
+		cyclic       bool
+		referencedBy []string
+	}{
+		{typeName: "Category", cyclic: true, referencedBy: []string{"Category"}},
+		{typeName: "Person", cyclic: true, referencedBy: []string{"Company"}},
+		{typeName: "Company", cyclic: true, referencedBy: []string{"Person", "Site"}},
+		{typeName: "Address", cyclic: false, referencedBy: []string{"Site"}},
+		{typeName: "Site", cyclic: false, referencedBy: nil},
+	}
+
+	for _, tt := range tests {
+		typeInfo, ok := g.types[tt.typeName]
+		if !ok {
+			t.Fatalf("type %s not extracted", tt.typeName)
+		}
+
+		if typeInfo.Cyclic != tt.cyclic {
+			t.Errorf("%s.Cyclic = %v, want %v", tt.typeName, typeInfo.Cyclic, tt.cyclic)
+		}
+
+		if !reflect.DeepEqual(typeInfo.ReferencedBy, tt.referencedBy) {
+			t.Errorf("%s.ReferencedBy = %v, want %v", tt.typeName, typeInfo.ReferencedBy, tt.referencedBy)
+		}
+	}
+
+	for _, typeName := range []string{"Site", "Address", "Company", "Person"} {
+		if !g.types[typeName].UsedByHTTP {
+			t.Errorf("%s.UsedByHTTP = false, want true", typeName)
+		}
+	}
+}
+
+func TestCheckUnusedTypes(t *testing.T) {
+	t.Parallel()
+
+	src := `package unused
+
+// Address is used through Order.
+type Address struct {
+	Street string ` + "`json:\"street\"`" + `
+}
+
+// Order is used by an operation.
+type Order struct {
+	Address Address ` + "`json:\"address\"`" + `
+}
+
+// Orphan is used by nothing.
+type Orphan struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+	tests := []struct {
+		name         string
+		warn         bool
+		strictUnused bool
+		wantErr      bool
+	}{
+		{name: "disabled", warn: false, strictUnused: false, wantErr: false},
+		{name: "warn", warn: true, strictUnused: false, wantErr: false},
+		{name: "strict", warn: true, strictUnused: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, _ := newSourceTestCollector(t, src)
+			g.warnUnusedTypes = tt.warn
+			g.strictUnused = tt.strictUnused
+
+			if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+				t.Fatalf("extractAllTypesFromGo() error = %v", err)
+			}
+
+			g.markTypeAsHTTP("Order")
+
+			if got := g.unusedTypes(); !reflect.DeepEqual(got, []string{"Orphan"}) {
+				t.Errorf("unusedTypes() = %v, want [Orphan]", got)
+			}
+
+			if err := g.checkUnusedTypes(); (err != nil) != tt.wantErr {
+				t.Errorf("checkUnusedTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReverseLookups(t *testing.T) {
+	t.Parallel()
+
+	src := `package reverse
+
+// User is embedded by Device and Team.
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Device struct {
+	Owner User ` + "`json:\"owner\"`" + `
+}
+
+type Team struct {
+	Members []User ` + "`json:\"members\"`" + `
+}
+
+// DeviceList references User through Device.
+type DeviceList struct {
+	Devices []Device ` + "`json:\"devices\"`" + `
+}
+
+// Node references itself.
+type Node struct {
+	Next *Node ` + "`json:\"next\"`" + `
+}
+
+type Unrelated struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	g.httpOps = map[string]*RouteInfo{
+		"createUser": {
+			OperationID: "createUser",
+			Request:     &RequestInfo{TypeName: "User"},
+			Responses:   map[int]ResponseInfo{201: {TypeName: "User"}, 204: {}},
+		},
+		"listDevices": {
+			OperationID: "listDevices",
+			Responses:   map[int]ResponseInfo{200: {TypeName: "DeviceList"}},
+		},
+		"getUnrelated": {
+			OperationID: "getUnrelated",
+			Responses:   map[int]ResponseInfo{200: {TypeName: "Unrelated"}},
+		},
+	}
+	g.mqttPublications = map[string]*MQTTPublicationInfo{
+		"teamUpdated": {OperationID: "teamUpdated", TypeName: "Team"},
+	}
+
+	typeTests := []struct {
+		typeName string
+		expected []string
+	}{
+		{typeName: "User", expected: []string{"Device", "DeviceList", "Team"}},
+		{typeName: "Device", expected: []string{"DeviceList"}},
+		{typeName: "DeviceList", expected: nil},
+		{typeName: "Node", expected: []string{"Node"}},
+		{typeName: "Missing", expected: nil},
+	}
+
+	for _, tt := range typeTests {
+		if got := g.TypesUsingType(tt.typeName); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("TypesUsingType(%q) = %v, want %v", tt.typeName, got, tt.expected)
+		}
+	}
+
+	operationTests := []struct {
+		typeName string
+		expected []UsageInfo
+	}{
+		{
+			typeName: "User",
+			expected: []UsageInfo{
+				{OperationID: "createUser", Role: "request"},
+				{OperationID: "createUser", Role: "response"},
+				{OperationID: "listDevices", Role: "response"},
+				{OperationID: "teamUpdated", Role: "mqtt_publication"},
+			},
+		},
+		{typeName: "Device", expected: []UsageInfo{{OperationID: "listDevices", Role: "response"}}},
+		{typeName: "Node", expected: nil},
+		{typeName: "", expected: nil},
+	}
+
+	for _, tt := range operationTests {
+		if got := g.OperationsUsingType(tt.typeName); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("OperationsUsingType(%q) = %v, want %v", tt.typeName, got, tt.expected)
+		}
+	}
+}
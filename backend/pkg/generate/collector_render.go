@@ -17,21 +17,24 @@ func (g *OpenAPICollector) generateTypesRepresentations() error {
 	g.l.Debug("Generating all representations for all types", slog.Int("typeCount", len(g.types)))
 
 	for name, typeInfo := range g.types {
-		// Go Representation
-		goSource, err := g.generateGoSource(typeInfo)
-		if err != nil {
-			return fmt.Errorf("failed to generate Go representation for %s: %w", name, err)
-		}
+		// Patch types have no Go declaration, their Go and TypeScript representations are set on registration
+		if _, isPatch := g.patchTypes[name]; !isPatch {
+			// Go Representation
+			goSource, err := g.generateGoSource(typeInfo)
+			if err != nil {
+				return fmt.Errorf("failed to generate Go representation for %s: %w", name, err)
+			}
 
-		typeInfo.Representations.Go = goSource
+			typeInfo.Representations.Go = goSource
 
-		// TypeScript Representation
-		tsSource, err := g.serializeTSNode(name)
-		if err != nil {
-			return fmt.Errorf("failed to serialize TS representation for %s: %w", name, err)
-		}
+			// TypeScript Representation
+			tsSource, err := g.serializeTSNode(name)
+			if err != nil {
+				return fmt.Errorf("failed to serialize TS representation for %s: %w", name, err)
+			}
 
-		typeInfo.Representations.TS = tsSource
+			typeInfo.Representations.TS = tsSource
+		}
 
 		// JSON Schema Representation
 		schema, err := toOpenAPISchema(typeInfo)
@@ -0,0 +1,81 @@
+package generate
+
+// This file handles validating MQTT payloads against the generated schemas.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ValidateMQTTPublicationPayload validates a JSON encoded payload against the schema
+// of the message type registered for the given publication.
+func (g *OpenAPICollector) ValidateMQTTPublicationPayload(operationID string, payload []byte) error {
+	pub, ok := g.mqttPublications[operationID]
+	if !ok {
+		return fmt.Errorf("publication not found for operationID %s", operationID)
+	}
+
+	schemas, err := g.getValidationSchemas(pub.TypeName)
+	if err != nil {
+		return fmt.Errorf("failed to build validation schemas: %w", err)
+	}
+
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+
+	if err := schemas[pub.TypeName].Value.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return fmt.Errorf("payload does not match schema of %s: %w", pub.TypeName, err)
+	}
+
+	return nil
+}
+
+// getValidationSchemas returns resolved schemas for all extracted types.
+// Schemas are built once and rebuilt only if the requested type was extracted afterwards.
+func (g *OpenAPICollector) getValidationSchemas(typeName string) (openapi3.Schemas, error) {
+	g.validationMu.Lock()
+	defer g.validationMu.Unlock()
+
+	if _, ok := g.validationSchemas[typeName]; ok {
+		return g.validationSchemas, nil
+	}
+
+	schemas := make(openapi3.Schemas, len(g.types))
+
+	for name, typeInfo := range g.types {
+		schema, err := toOpenAPISchema(typeInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build schema for %s: %w", name, err)
+		}
+
+		schemas[name] = &openapi3.SchemaRef{Value: schema}
+	}
+
+	if _, ok := schemas[typeName]; !ok {
+		return nil, fmt.Errorf("type %s not found in types map", typeName)
+	}
+
+	// Resolve $refs between component schemas so they can be used for validation
+	spec := &openapi3.T{
+		OpenAPI:    openAPIVersion,
+		Info:       &openapi3.Info{},
+		Paths:      openapi3.NewPaths(),
+		Components: &openapi3.Components{Schemas: schemas},
+	}
+
+	loader := openapi3.NewLoader()
+	loader.Context = context.Background()
+
+	if err := loader.ResolveRefsIn(spec, nil); err != nil {
+		return nil, fmt.Errorf("failed to resolve schema references: %w", err)
+	}
+
+	g.validationSchemas = schemas
+
+	return schemas, nil
+}
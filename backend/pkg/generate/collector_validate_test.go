@@ -0,0 +1,93 @@
+package generate
+
+import (
+	"testing"
+)
+
+func TestValidateMQTTPublicationPayload(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		types: map[string]*TypeInfo{
+			"Unit": {
+				Name: "Unit",
+				Kind: TypeKindStringEnum,
+				EnumValues: []EnumValue{
+					{Value: "celsius"},
+					{Value: "fahrenheit"},
+				},
+			},
+			"Temperature": {
+				Name: "Temperature",
+				Kind: TypeKindObject,
+				Fields: []FieldInfo{
+					{Name: "value", TypeInfo: FieldType{Kind: FieldKindPrimitive, Type: typeNumber, Required: true}},
+					{Name: "unit", TypeInfo: FieldType{Kind: FieldKindReference, Type: "Unit", Required: true}},
+				},
+			},
+		},
+		mqttPublications: map[string]*MQTTPublicationInfo{
+			"publishTemperature": {OperationID: "publishTemperature", TypeName: "Temperature"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		operationID string
+		payload     string
+		wantErr     bool
+	}{
+		{
+			name:        "valid payload",
+			operationID: "publishTemperature",
+			payload:     `{"value": 21.5, "unit": "celsius"}`,
+		},
+		{
+			name:        "missing required field",
+			operationID: "publishTemperature",
+			payload:     `{"value": 21.5}`,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid enum value",
+			operationID: "publishTemperature",
+			payload:     `{"value": 21.5, "unit": "kelvin"}`,
+			wantErr:     true,
+		},
+		{
+			name:        "additional property",
+			operationID: "publishTemperature",
+			payload:     `{"value": 21.5, "unit": "celsius", "extra": true}`,
+			wantErr:     true,
+		},
+		{
+			name:        "wrong field type",
+			operationID: "publishTemperature",
+			payload:     `{"value": "warm", "unit": "celsius"}`,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid JSON",
+			operationID: "publishTemperature",
+			payload:     `{`,
+			wantErr:     true,
+		},
+		{
+			name:        "unknown operation",
+			operationID: "publishHumidity",
+			payload:     `{}`,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := g.ValidateMQTTPublicationPayload(tt.operationID, []byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMQTTPublicationPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,18 +9,24 @@ import (
 	"http-mqtt-boilerplate/backend/pkg/utils"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 
 	postgrescontainer "github.com/testcontainers/testcontainers-go/modules/postgres"
 )
 
 // GenerateDatabaseSchema runs migrations on a temporary database and returns the resulting schema.
-// This generates a SQL schema dump from the application's migrations.
-func (g *OpenAPICollector) GenerateDatabaseSchema(deployment string, schemaOutputPath string) (string, error) {
+// This generates a SQL schema dump from the application's migrations, along with its structured JSON
+// form next to it (see [schemaJSONPath]).
+// The context bounds container startup and is checked between each step, so a hung database can be abandoned.
+func (g *OpenAPICollector) GenerateDatabaseSchema(ctx context.Context, deployment string, schemaOutputPath string) (string, error) {
 	g.l.Debug("Generating database schema from migrations", slog.String("deployment", deployment))
 
-	// Start a PostgreSQL container for schema generation
-	ctx := context.Background()
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("database schema generation cancelled: %w", err)
+	}
 
+	// Start a PostgreSQL container for schema generation
 	container, err := postgrescontainer.Run(ctx,
 		"postgres:18-alpine",
 		postgrescontainer.WithDatabase("testdb"),
@@ -33,7 +39,8 @@ func (g *OpenAPICollector) GenerateDatabaseSchema(deployment string, schemaOutpu
 	}
 
 	defer func() {
-		if err := container.Terminate(ctx); err != nil {
+		// Terminate even if ctx is already done, otherwise the container would leak
+		if err := container.Terminate(context.WithoutCancel(ctx)); err != nil {
 			g.l.Error("failed to terminate PostgreSQL container", utils.ErrAttr(err))
 		}
 	}()
@@ -64,16 +71,29 @@ func (g *OpenAPICollector) GenerateDatabaseSchema(deployment string, schemaOutpu
 		return "", fmt.Errorf("failed to create migrator: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("database schema generation cancelled: %w", err)
+	}
+
 	// Run migrations
 	if err := mig.Migrate(); err != nil {
 		return "", fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("database schema generation cancelled: %w", err)
+	}
+
 	// Dump the database schema to the specified output path
 	if err = mig.DumpSchema(schemaOutputPath); err != nil {
 		return "", fmt.Errorf("failed to dump schema: %w", err)
 	}
 
+	// Dump the structured schema next to the SQL one, for tools that should not parse SQL
+	if err = mig.DumpSchemaJSON(schemaJSONPath(schemaOutputPath)); err != nil {
+		return "", fmt.Errorf("failed to dump schema JSON: %w", err)
+	}
+
 	// Read the schema file
 	schemaBytes, err := os.ReadFile(schemaOutputPath)
 	if err != nil {
@@ -86,3 +106,9 @@ func (g *OpenAPICollector) GenerateDatabaseSchema(deployment string, schemaOutpu
 
 	return schema, nil
 }
+
+// schemaJSONPath returns the path of the structured schema of a SQL schema dump,
+// the same path with a .json extension (e.g., docs/local/schema.sql -> docs/local/schema.json).
+func schemaJSONPath(schemaOutputPath string) string {
+	return strings.TrimSuffix(schemaOutputPath, filepath.Ext(schemaOutputPath)) + ".json"
+}
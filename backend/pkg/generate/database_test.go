@@ -0,0 +1,50 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateDatabaseSchemaCancelled(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{l: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+
+	_, err := g.GenerateDatabaseSchema(ctx, "local", filepath.Join(t.TempDir(), "schema.sql"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateDatabaseSchema() error = %v, want context.Canceled", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GenerateDatabaseSchema() returned after %s, want prompt return", elapsed)
+	}
+}
+
+func TestSchemaJSONPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "docs/local/schema.sql", want: "docs/local/schema.json"},
+		{path: "docs/cloud/schema", want: "docs/cloud/schema.json"},
+		{path: "out.d/schema.dump.sql", want: "out.d/schema.dump.json"},
+	}
+
+	for _, tt := range tests {
+		if got := schemaJSONPath(tt.path); got != tt.want {
+			t.Errorf("schemaJSONPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
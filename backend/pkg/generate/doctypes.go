@@ -15,6 +15,9 @@ type TypeInfo struct {
 	UsedByHTTP      bool            `json:"usedByHTTP"`      // Whether this type is used by HTTP operations
 	UsedByMQTT      bool            `json:"usedByMQTT"`      // Whether this type is used by MQTT operations
 	UnderlyingType  *FieldType      `json:"underlyingType"`  // For alias types: the underlying type being aliased
+	Cyclic          bool            `json:"cyclic"`          // Whether this type is part of a reference cycle (e.g., a tree node)
+
+	AllowAdditionalProperties bool `json:"allowAdditionalProperties,omitempty"` // For object types: whether undeclared properties are allowed (from //openapi:additionalProperties)
 }
 
 type Representations struct {
@@ -27,23 +30,37 @@ type Representations struct {
 
 // FieldType represents the structured type information for a field.
 type FieldType struct {
-	Kind                 string     `json:"kind"`                 // "primitive", "array", "reference", "enum", "object", "unknown"
-	Type                 string     `json:"type"`                 // Base type: "string", "User", etc.
-	Format               string     `json:"format"`               // OpenAPI format (e.g., "date-time")
-	Required             bool       `json:"required"`             // Whether the field is required
-	Nullable             bool       `json:"nullable"`             // For nullable types (T | null)
-	ItemsType            *FieldType `json:"itemsType"`            // For arrays: type of array elements
-	AdditionalProperties *FieldType `json:"additionalProperties"` // For maps: type of map values
-	MapKeyType           *FieldType `json:"mapKeyType"`           // For maps: type of map keys
+	Kind                 string      `json:"kind"`                  // "primitive", "array", "reference", "enum", "object", "unknown"
+	Type                 string      `json:"type"`                  // Base type: "string", "User", etc.
+	Format               string      `json:"format"`                // OpenAPI format (e.g., "date-time")
+	Required             bool        `json:"required"`              // Whether the field is required
+	Nullable             bool        `json:"nullable"`              // For nullable types (T | null)
+	ItemsType            *FieldType  `json:"itemsType"`             // For arrays: type of array elements
+	AdditionalProperties *FieldType  `json:"additionalProperties"`  // For maps: type of map values
+	MapKeyType           *FieldType  `json:"mapKeyType"`            // For maps: type of map keys
+	MinLength            *uint64     `json:"minLength,omitempty"`   // For strings: minimum length (from validate tag)
+	MaxLength            *uint64     `json:"maxLength,omitempty"`   // For strings: maximum length (from validate tag)
+	Minimum              *float64    `json:"minimum,omitempty"`     // For numbers: minimum value (from validate tag)
+	Maximum              *float64    `json:"maximum,omitempty"`     // For numbers: maximum value (from validate tag)
+	Pattern              string      `json:"pattern,omitempty"`     // For strings: regex pattern (from validate tag)
+	MinItems             *uint64     `json:"minItems,omitempty"`    // For arrays: minimum number of items (from validate tag)
+	MaxItems             *uint64     `json:"maxItems,omitempty"`    // For arrays: maximum number of items (from validate tag)
+	UniqueItems          bool        `json:"uniqueItems,omitempty"` // For arrays: whether items must be unique (from validate tag)
+	Enum                 []any       `json:"enum,omitempty"`        // For primitives: inline allowed values (from validate oneof)
+	Note                 string      `json:"note,omitempty"`        // For external types: note about the representation (e.g., units)
+	Fields               []FieldInfo `json:"fields,omitempty"`      // For inline objects (anonymous structs): the object fields
 }
 
 // FieldInfo describes a field in a struct (used in high-level API documentation).
 type FieldInfo struct {
-	Name        string    `json:"name"`        // Field name
-	DisplayType string    `json:"displayType"` // Human-readable type string (e.g., "User[]", "string | null")
-	TypeInfo    FieldType `json:"typeInfo"`    // Structured type information
-	Description string    `json:"description"` // Field documentation
-	Deprecated  string    `json:"deprecated"`  // Deprecation information
+	Name        string    `json:"name"`                // Field name
+	DisplayType string    `json:"displayType"`         // Human-readable type string (e.g., "User[]", "string | null")
+	TypeInfo    FieldType `json:"typeInfo"`            // Structured type information
+	Description string    `json:"description"`         // Field documentation
+	Deprecated  string    `json:"deprecated"`          // Deprecation information
+	Default     any       `json:"default,omitempty"`   // Default value (from default tag), typed to match the field
+	ReadOnly    bool      `json:"readOnly,omitempty"`  // Whether the field is only sent in responses (from openapi tag)
+	WriteOnly   bool      `json:"writeOnly,omitempty"` // Whether the field is only sent in requests (from openapi tag)
 }
 
 // EnumValue represents an enum constant with its documentation.
@@ -70,7 +87,9 @@ type RouteInfo struct {
 	Deprecated  string               `json:"deprecated"`
 	Request     *RequestInfo         `json:"request"`
 	Parameters  []ParameterInfo      `json:"parameters"`
-	Responses   map[int]ResponseInfo `json:"responses"` // Keyed by status code
+	Responses   map[int]ResponseInfo `json:"responses"`          // Keyed by status code
+	Security    []string             `json:"security,omitempty"` // Names of the accepted security schemes, overrides APIInfo.Security
+	Public      bool                 `json:"public,omitempty"`   // Whether the route requires no authentication, overrides APIInfo.Security
 }
 
 // RequestInfo describes a request body.
@@ -78,6 +97,8 @@ type RequestInfo struct {
 	TypeName            string            `json:"type"` // Extracted type name (set by generator)
 	TypeValue           any               `json:"-"`    // Zero value of the type (set by route builder)
 	Description         string            `json:"description"`
+	Required            bool              `json:"required"` // Whether the body must be sent (set by route builder)
+	Patch               bool              `json:"-"`        // Whether the body is the patch type of TypeValue (set by route builder)
 	ExamplesStringified map[string]string `json:"examples"` // Keyed by example name
 	Examples            map[string]any    `json:"-"`        // Keyed by example name
 }
@@ -85,11 +106,13 @@ type RequestInfo struct {
 // ParameterInfo describes a route parameter.
 type ParameterInfo struct {
 	Name        string `json:"name"`
-	In          string `json:"in"`   // "path", "query", "header"
-	TypeName    string `json:"type"` // Extracted type name (set by generator)
-	TypeValue   any    `json:"-"`    // Zero value of the type (set by route builder)
+	In          string `json:"in"`               // "path", "query", "header", "cookie"
+	TypeName    string `json:"type"`             // Extracted type name, or OpenAPI type for primitives (set by generator)
+	Format      string `json:"format,omitempty"` // OpenAPI format for primitives (set by generator)
+	TypeValue   any    `json:"-"`                // Zero value of the type (set by route builder)
 	Description string `json:"description"`
 	Required    bool   `json:"required"`
+	Default     any    `json:"default,omitempty"` // Default value for optional parameters
 }
 
 // ResponseInfo describes a response.
@@ -98,10 +121,35 @@ type ResponseInfo struct {
 	TypeName            string            `json:"type"` // Extracted type name (set by generator), empty for responses without body
 	TypeValue           any               `json:"-"`    // Zero value of the type (set by route builder)
 	Description         string            `json:"description"`
+	ContentType         string            `json:"contentType"`       // Media type of the response body (e.g., application/json, text/csv)
+	Binary              bool              `json:"binary"`            // Whether the body is raw bytes rather than a JSON-encoded type
+	ExamplesStringified map[string]string `json:"examples"`          // Keyed by example name
+	Examples            map[string]any    `json:"-"`                 // Keyed by example name
+	Headers             []HeaderInfo      `json:"headers,omitempty"` // Response headers, sorted by name
+
+	// Variants documents the body in several media types (e.g., JSON and CSV), in declaration order.
+	// When set, TypeValue, ContentType, Binary and Examples must be unset.
+	Variants []ContentVariantInfo `json:"variants,omitempty"`
+}
+
+// ContentVariantInfo describes one media type of a response with several content types.
+type ContentVariantInfo struct {
+	ContentType         string            `json:"contentType"`
+	TypeName            string            `json:"type"`     // Extracted type name, or "string" for text bodies (set by generator)
+	TypeValue           any               `json:"-"`        // Zero value of the type (set by route builder)
 	ExamplesStringified map[string]string `json:"examples"` // Keyed by example name
 	Examples            map[string]any    `json:"-"`        // Keyed by example name
 }
 
+// HeaderInfo describes a response header.
+type HeaderInfo struct {
+	Name        string `json:"name"`
+	TypeName    string `json:"type"`             // Extracted type name, or OpenAPI type for primitives (set by generator)
+	Format      string `json:"format,omitempty"` // OpenAPI format for primitives (set by generator)
+	TypeValue   any    `json:"-"`                // Zero value of the type (set by route builder)
+	Description string `json:"description"`
+}
+
 // MQTTTopicParameter describes a parameter in an MQTT topic pattern.
 type MQTTTopicParameter struct {
 	Name        string `json:"name"`        // Parameter name (e.g., "deviceID")
@@ -162,12 +210,41 @@ type APIInfo struct {
 	Version     string       `json:"version"`
 	Description string       `json:"description"`
 	Servers     []ServerInfo `json:"servers"`
+
+	TagDescriptions map[string]string `json:"tagDescriptions,omitempty"` // Descriptions of the route groups, keyed by group name
+
+	SecuritySchemes map[string]SecuritySchemeInfo `json:"securitySchemes,omitempty"` // Keyed by scheme name
+	Security        []string                      `json:"security,omitempty"`        // Names of the security schemes accepted by default by all routes
+}
+
+// Security scheme type constants for SecuritySchemeInfo.
+const (
+	SecuritySchemeTypeHTTP   = "http"
+	SecuritySchemeTypeAPIKey = "apiKey"
+)
+
+// SecuritySchemeInfo describes how clients authenticate (e.g., bearer token or API key).
+type SecuritySchemeInfo struct {
+	Type         string `json:"type"`                   // "http" or "apiKey"
+	Description  string `json:"description"`            // Scheme documentation
+	Scheme       string `json:"scheme,omitempty"`       // For http: authorization scheme (e.g., "bearer")
+	BearerFormat string `json:"bearerFormat,omitempty"` // For http bearer: token format hint (e.g., "JWT")
+	Name         string `json:"name,omitempty"`         // For apiKey: header, query or cookie name
+	In           string `json:"in,omitempty"`           // For apiKey: "header", "query" or "cookie"
 }
 
 // ServerInfo contains server information.
 type ServerInfo struct {
-	URL         string `json:"url"`
-	Description string `json:"description"`
+	URL         string                    `json:"url"` // May be templated, e.g., https://{region}.api.example.com
+	Description string                    `json:"description"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"` // Keyed by variable name, one for each {variable} in the URL
+}
+
+// ServerVariable describes a variable in a templated server URL.
+type ServerVariable struct {
+	Default     string   `json:"default"`        // Value used when the client does not provide one
+	Enum        []string `json:"enum,omitempty"` // Allowed values (optional), must include the default
+	Description string   `json:"description"`
 }
 
 type Database struct {
@@ -42,3 +42,11 @@ const (
 	FieldKindEnum      = "enum"
 	FieldKindObject    = "object"
 )
+
+// freeFormObjectType is the FieldType.Type of object fields that accept any value (any/interface{} with AllowAny).
+const freeFormObjectType = "any"
+
+// MQTTPayloadValidator is implemented by collectors that can validate MQTT payloads against the generated schemas.
+type MQTTPayloadValidator interface {
+	ValidateMQTTPublicationPayload(operationID string, payload []byte) error
+}
@@ -0,0 +1,350 @@
+package generate
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// createTableRegexp matches CREATE TABLE statements and captures the table name.
+var createTableRegexp = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w."]+)`) //nolint:gochecknoglobals // Compiled once
+
+// GenerateMarkdown generates a human-readable Markdown API reference from the documentation.
+// It is meant for wikis that do not render OpenAPI.
+func GenerateMarkdown(doc *APIDocumentation, outputPath string) error {
+	if err := os.WriteFile(outputPath, []byte(renderMarkdown(doc)), 0600); err != nil {
+		return fmt.Errorf("failed to write markdown documentation: %w", err)
+	}
+
+	return nil
+}
+
+// renderMarkdown renders the documentation as Markdown, sorted for deterministic output.
+func renderMarkdown(doc *APIDocumentation) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", cmp.Or(doc.Info.Title, "API Reference"))
+
+	if doc.Info.Version != "" {
+		fmt.Fprintf(&b, "Version: `%s`\n\n", doc.Info.Version)
+	}
+
+	if doc.Info.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", doc.Info.Description)
+	}
+
+	if len(doc.Info.Servers) > 0 {
+		b.WriteString("Servers:\n\n")
+
+		for _, server := range doc.Info.Servers {
+			fmt.Fprintf(&b, "- `%s` %s\n", server.URL, server.Description)
+		}
+
+		b.WriteString("\n")
+	}
+
+	renderMarkdownHTTP(&b, doc.HTTPOperations, doc.Types)
+	renderMarkdownMQTT(&b, doc.MQTTPublications, doc.MQTTSubscriptions, doc.Types)
+	renderMarkdownTypes(&b, doc.Types)
+	renderMarkdownDatabase(&b, doc.Database)
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// renderMarkdownHTTP renders HTTP operations grouped by their group.
+func renderMarkdownHTTP(b *strings.Builder, ops map[string]*RouteInfo, types map[string]*TypeInfo) {
+	if len(ops) == 0 {
+		return
+	}
+
+	b.WriteString("## HTTP Operations\n\n")
+
+	groups := make(map[string][]*RouteInfo)
+	for _, op := range ops {
+		groups[op.Group] = append(groups[op.Group], op)
+	}
+
+	for _, group := range slices.Sorted(maps.Keys(groups)) {
+		fmt.Fprintf(b, "### %s\n\n", cmp.Or(group, "Other"))
+
+		routes := groups[group]
+		slices.SortFunc(routes, func(a, b *RouteInfo) int {
+			return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+		})
+
+		for _, op := range routes {
+			fmt.Fprintf(b, "#### `%s %s`%s\n\n", op.Method, op.Path, markdownDeprecatedTag(op.Deprecated))
+			fmt.Fprintf(b, "**%s** (`%s`)\n\n", op.Summary, op.OperationID)
+			renderMarkdownDeprecation(b, op.Deprecated)
+
+			if op.Description != "" {
+				fmt.Fprintf(b, "%s\n\n", op.Description)
+			}
+
+			if len(op.Parameters) > 0 {
+				b.WriteString("Parameters:\n\n")
+				b.WriteString("| Name | In | Type | Required | Description |\n")
+				b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+				for _, param := range op.Parameters {
+					fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s |\n",
+						param.Name, param.In, markdownTypeLink(types, param.TypeName), markdownBool(param.Required), markdownCell(param.Description))
+				}
+
+				b.WriteString("\n")
+			}
+
+			if op.Request != nil {
+				fmt.Fprintf(b, "Request body: %s\n\n", markdownTypeLink(types, op.Request.TypeName))
+			}
+
+			if len(op.Responses) > 0 {
+				b.WriteString("Responses:\n\n")
+				b.WriteString("| Status | Type | Content Type | Description |\n")
+				b.WriteString("| --- | --- | --- | --- |\n")
+
+				for _, status := range slices.Sorted(maps.Keys(op.Responses)) {
+					resp := op.Responses[status]
+
+					for _, variant := range resp.Variants {
+						fmt.Fprintf(b, "| %d | %s | %s | %s |\n", status, markdownTypeLink(types, variant.TypeName), variant.ContentType, markdownCell(resp.Description))
+					}
+
+					if len(resp.Variants) > 0 {
+						continue
+					}
+
+					typeName := markdownTypeLink(types, resp.TypeName)
+					if resp.Binary {
+						typeName = "binary"
+					}
+
+					fmt.Fprintf(b, "| %d | %s | %s | %s |\n", status, typeName, cmp.Or(resp.ContentType, "-"), markdownCell(resp.Description))
+				}
+
+				b.WriteString("\n")
+			}
+		}
+	}
+}
+
+// markdownMQTTOperation is the common view of MQTT publications and subscriptions.
+type markdownMQTTOperation struct {
+	action      string
+	operationID string
+	topic       string
+	params      []MQTTTopicParameter
+	summary     string
+	description string
+	deprecated  string
+	qos         byte
+	retained    bool
+	typeName    string
+}
+
+// renderMarkdownMQTT renders MQTT publications and subscriptions grouped by their group.
+func renderMarkdownMQTT(b *strings.Builder, pubs map[string]*MQTTPublicationInfo, subs map[string]*MQTTSubscriptionInfo, types map[string]*TypeInfo) {
+	if len(pubs) == 0 && len(subs) == 0 {
+		return
+	}
+
+	b.WriteString("## MQTT Operations\n\n")
+
+	groups := make(map[string][]markdownMQTTOperation)
+
+	for _, pub := range pubs {
+		groups[pub.Group] = append(groups[pub.Group], markdownMQTTOperation{
+			action: "Publish", operationID: pub.OperationID, topic: pub.Topic, params: pub.TopicParameters,
+			summary: pub.Summary, description: pub.Description, deprecated: pub.Deprecated,
+			qos: pub.QoS, retained: pub.Retained, typeName: pub.TypeName,
+		})
+	}
+
+	for _, sub := range subs {
+		groups[sub.Group] = append(groups[sub.Group], markdownMQTTOperation{
+			action: "Subscribe", operationID: sub.OperationID, topic: sub.Topic, params: sub.TopicParameters,
+			summary: sub.Summary, description: sub.Description, deprecated: sub.Deprecated,
+			qos: sub.QoS, typeName: sub.TypeName,
+		})
+	}
+
+	for _, group := range slices.Sorted(maps.Keys(groups)) {
+		fmt.Fprintf(b, "### %s\n\n", cmp.Or(group, "Other"))
+
+		ops := groups[group]
+		slices.SortFunc(ops, func(a, b markdownMQTTOperation) int {
+			return cmp.Or(cmp.Compare(a.topic, b.topic), cmp.Compare(a.action, b.action))
+		})
+
+		for _, op := range ops {
+			fmt.Fprintf(b, "#### %s `%s`%s\n\n", op.action, op.topic, markdownDeprecatedTag(op.deprecated))
+			fmt.Fprintf(b, "**%s** (`%s`)\n\n", op.summary, op.operationID)
+			renderMarkdownDeprecation(b, op.deprecated)
+
+			if op.description != "" {
+				fmt.Fprintf(b, "%s\n\n", op.description)
+			}
+
+			fmt.Fprintf(b, "- Payload: %s\n- QoS: %d\n", markdownTypeLink(types, op.typeName), op.qos)
+
+			if op.action == "Publish" {
+				fmt.Fprintf(b, "- Retained: %s\n", markdownBool(op.retained))
+			}
+
+			b.WriteString("\n")
+
+			if len(op.params) > 0 {
+				b.WriteString("Topic parameters:\n\n")
+				b.WriteString("| Name | Type | Description |\n")
+				b.WriteString("| --- | --- | --- |\n")
+
+				for _, param := range op.params {
+					fmt.Fprintf(b, "| `%s` | %s | %s |\n", param.Name, markdownTypeLink(types, param.TypeName), markdownCell(param.Description))
+				}
+
+				b.WriteString("\n")
+			}
+		}
+	}
+}
+
+// renderMarkdownTypes renders all types with their fields or enum values.
+func renderMarkdownTypes(b *strings.Builder, types map[string]*TypeInfo) {
+	if len(types) == 0 {
+		return
+	}
+
+	b.WriteString("## Types\n\n")
+
+	for _, name := range slices.Sorted(maps.Keys(types)) {
+		typeInfo := types[name]
+
+		// The deprecation is not in the heading, it would change the anchor used by type links
+		fmt.Fprintf(b, "### %s\n\n", name)
+		fmt.Fprintf(b, "Kind: `%s`\n\n", typeInfo.Kind)
+		renderMarkdownDeprecation(b, typeInfo.Deprecated)
+
+		if typeInfo.Description != "" {
+			fmt.Fprintf(b, "%s\n\n", typeInfo.Description)
+		}
+
+		if typeInfo.UnderlyingType != nil {
+			fmt.Fprintf(b, "Underlying type: `%s`\n\n", typeInfo.UnderlyingType.Type)
+		}
+
+		if len(typeInfo.Fields) > 0 {
+			b.WriteString("| Field | Type | Required | Description |\n")
+			b.WriteString("| --- | --- | --- | --- |\n")
+
+			for _, field := range typeInfo.Fields {
+				desc := field.Description
+				if field.Deprecated != "" {
+					desc = strings.TrimSpace("**Deprecated:** " + field.Deprecated + " " + desc)
+				}
+
+				fmt.Fprintf(b, "| `%s` | `%s` | %s | %s |\n",
+					field.Name, markdownCell(field.DisplayType), markdownBool(field.TypeInfo.Required), markdownCell(desc))
+			}
+
+			b.WriteString("\n")
+		}
+
+		if len(typeInfo.EnumValues) > 0 {
+			b.WriteString("| Value | Description |\n")
+			b.WriteString("| --- | --- |\n")
+
+			for _, value := range typeInfo.EnumValues {
+				desc := value.Description
+				if value.Deprecated != "" {
+					desc = strings.TrimSpace("**Deprecated:** " + value.Deprecated + " " + desc)
+				}
+
+				fmt.Fprintf(b, "| `%v` | %s |\n", value.Value, markdownCell(desc))
+			}
+
+			b.WriteString("\n")
+		}
+	}
+}
+
+// renderMarkdownDatabase renders a summary of the database schema with the full schema collapsed.
+func renderMarkdownDatabase(b *strings.Builder, db Database) {
+	if db.Schema == "" {
+		return
+	}
+
+	b.WriteString("## Database\n\n")
+
+	if db.Dialect != "" {
+		fmt.Fprintf(b, "Dialect: `%s`\n\n", db.Dialect)
+	}
+
+	matches := createTableRegexp.FindAllStringSubmatch(db.Schema, -1)
+	if len(matches) > 0 {
+		fmt.Fprintf(b, "Tables (%d):\n\n", len(matches))
+
+		for _, match := range matches {
+			fmt.Fprintf(b, "- `%s`\n", strings.ReplaceAll(match[1], `"`, ""))
+		}
+
+		b.WriteString("\n")
+	}
+
+	b.WriteString("<details>\n<summary>Schema</summary>\n\n```sql\n")
+	b.WriteString(strings.TrimSpace(db.Schema))
+	b.WriteString("\n```\n\n</details>\n\n")
+}
+
+// renderMarkdownDeprecation renders a deprecation notice, if any.
+func renderMarkdownDeprecation(b *strings.Builder, deprecated string) {
+	if deprecated != "" {
+		fmt.Fprintf(b, "> **Deprecated:** %s\n\n", deprecated)
+	}
+}
+
+// markdownDeprecatedTag returns a heading suffix for deprecated items.
+func markdownDeprecatedTag(deprecated string) string {
+	if deprecated == "" {
+		return ""
+	}
+
+	return " (deprecated)"
+}
+
+// markdownTypeLink links a documented type to its section, other types (e.g., primitives) are only quoted.
+// Returns "-" when there is no type.
+func markdownTypeLink(types map[string]*TypeInfo, typeName string) string {
+	if typeName == "" {
+		return "-"
+	}
+
+	if _, ok := types[typeName]; !ok {
+		return "`" + typeName + "`"
+	}
+
+	return fmt.Sprintf("[`%s`](#%s)", typeName, strings.ToLower(typeName))
+}
+
+// markdownBool renders a boolean as yes/no.
+func markdownBool(v bool) string {
+	if v {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// markdownCell escapes text for use in a table cell.
+func markdownCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
@@ -0,0 +1,116 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testMarkdownDocumentation() *APIDocumentation {
+	return &APIDocumentation{
+		Info: APIInfo{Title: "Local API", Version: "v1.0.0", Servers: []ServerInfo{{URL: "http://localhost:8080", Description: "Local server"}}},
+		Types: map[string]*TypeInfo{
+			"GetTeamResponse": {
+				Name:       "GetTeamResponse",
+				Kind:       TypeKindObject,
+				Deprecated: "Use GetTeamResponseV2 instead.",
+				Fields: []FieldInfo{
+					{Name: "teamID", DisplayType: "string", TypeInfo: FieldType{Required: true}, Description: "ID of the team"},
+					{Name: "users", DisplayType: "User[]", Description: "Users | members", Deprecated: "Use members."},
+				},
+			},
+			"PingStatus": {
+				Name:       "PingStatus",
+				Kind:       TypeKindStringEnum,
+				EnumValues: []EnumValue{{Value: "OK", Description: "All good"}},
+			},
+		},
+		HTTPOperations: map[string]*RouteInfo{
+			"getTeam": {
+				OperationID: "getTeam", Method: "GET", Path: "/api/team/{teamID}", Summary: "Get a team", Group: "Team",
+				Deprecated: "Use getTeamV2.",
+				Parameters: []ParameterInfo{{Name: "teamID", In: "path", TypeName: "string", Required: true, Description: "ID of the team"}},
+				Responses: map[int]ResponseInfo{
+					200: {StatusCode: 200, TypeName: "GetTeamResponse", Description: "The team", ContentType: ContentTypeJSON},
+					404: {StatusCode: 404, TypeName: "ErrorResponse", Description: "Not found", ContentType: ContentTypeJSON},
+				},
+			},
+			"ping": {OperationID: "ping", Method: "GET", Path: "/api/ping", Summary: "Ping", Group: "Core"},
+		},
+		MQTTPublications: map[string]*MQTTPublicationInfo{
+			"deviceCommand": {
+				OperationID: "deviceCommand", Topic: "devices/{deviceID}/commands", Summary: "Send a command", Group: "Devices",
+				QoS: 1, Retained: true, TypeName: "DeviceCommand",
+				TopicParameters: []MQTTTopicParameter{{Name: "deviceID", TypeName: "string", Description: "ID of the device"}},
+			},
+		},
+		MQTTSubscriptions: map[string]*MQTTSubscriptionInfo{
+			"deviceStatus": {OperationID: "deviceStatus", Topic: "devices/{deviceID}/status", Summary: "Device status", Group: "Devices", TypeName: "DeviceStatus"},
+		},
+		Database: Database{Dialect: "postgres", Schema: "CREATE TABLE public.users (id text);\nCREATE TABLE IF NOT EXISTS teams (id text);"},
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "api_docs.md")
+
+	if err := GenerateMarkdown(testMarkdownDocumentation(), outputPath); err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read markdown: %v", err)
+	}
+
+	md := string(data)
+
+	wantContains := []string{
+		"# Local API",
+		"## HTTP Operations",
+		"### Core",
+		"### Team",
+		"#### `GET /api/team/{teamID}` (deprecated)",
+		"> **Deprecated:** Use getTeamV2.",
+		"| `teamID` | path | `string` | yes | ID of the team |",
+		"| 200 | [`GetTeamResponse`](#getteamresponse) | application/json | The team |",
+		"## MQTT Operations",
+		"### Devices",
+		"#### Publish `devices/{deviceID}/commands`",
+		"- Retained: yes",
+		"#### Subscribe `devices/{deviceID}/status`",
+		"## Types",
+		"### GetTeamResponse",
+		"> **Deprecated:** Use GetTeamResponseV2 instead.",
+		`| ` + "`users`" + ` | ` + "`User[]`" + ` | no | **Deprecated:** Use members. Users \| members |`,
+		"| `OK` | All good |",
+		"## Database",
+		"Tables (2):",
+		"- `public.users`",
+		"- `teams`",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n%s", want, md)
+		}
+	}
+
+	// Groups are sorted
+	if strings.Index(md, "### Core") > strings.Index(md, "### Team") {
+		t.Error("HTTP groups are not sorted")
+	}
+}
+
+func TestGenerateMarkdownDeterministic(t *testing.T) {
+	t.Parallel()
+
+	first := renderMarkdown(testMarkdownDocumentation())
+	for range 5 {
+		if got := renderMarkdown(testMarkdownDocumentation()); got != first {
+			t.Fatal("renderMarkdown() output is not deterministic")
+		}
+	}
+}
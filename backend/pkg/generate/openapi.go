@@ -2,10 +2,13 @@ package generate
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -59,15 +62,24 @@ func buildObjectSchema(typeInfo *TypeInfo) (*openapi3.Schema, error) {
 		Required:    []string{},
 	}
 
-	// Structured objects should not allow additional properties
+	// Structured objects should not allow additional properties, unless opted in with a directive
 	schema.AdditionalProperties = openapi3.AdditionalProperties{
-		Has: new(false),
+		Has: new(typeInfo.AllowAdditionalProperties),
 	}
 
-	for _, field := range typeInfo.Fields {
+	if err := addFieldProperties(schema, typeInfo.Fields); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// addFieldProperties adds the fields of an object to its schema properties and required list.
+func addFieldProperties(schema *openapi3.Schema, fields []FieldInfo) error {
+	for _, field := range fields {
 		fieldSchema, err := buildFieldSchema(field)
 		if err != nil {
-			return nil, fmt.Errorf("failed to build schema for field %s: %w", field.Name, err)
+			return fmt.Errorf("failed to build schema for field %s: %w", field.Name, err)
 		}
 
 		schema.Properties[field.Name] = fieldSchema
@@ -77,7 +89,7 @@ func buildObjectSchema(typeInfo *TypeInfo) (*openapi3.Schema, error) {
 		}
 	}
 
-	return schema, nil
+	return nil
 }
 
 // buildFieldSchema creates an OpenAPI schema for a field.
@@ -87,10 +99,76 @@ func buildFieldSchema(field FieldInfo) (*openapi3.SchemaRef, error) {
 		return nil, err
 	}
 
+	// Apply field-level default value
+	schema, err = applyDefault(schema, field.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply field-level readOnly/writeOnly metadata
+	schema, err = applyReadWriteOnly(schema, field.ReadOnly, field.WriteOnly)
+	if err != nil {
+		return nil, err
+	}
+
 	// Apply field-level deprecated metadata
 	return applyDeprecated(schema, field.Deprecated != "")
 }
 
+// applyReadWriteOnly sets the ReadOnly/WriteOnly fields on a schema if needed.
+// For inline schemas (Value != nil), sets them directly.
+// For $ref schemas (Value == nil, Ref != ""), wraps with allOf in OpenAPI 3.0.
+func applyReadWriteOnly(schemaRef *openapi3.SchemaRef, readOnly, writeOnly bool) (*openapi3.SchemaRef, error) {
+	switch {
+	case !readOnly && !writeOnly:
+		return schemaRef, nil
+	case readOnly && writeOnly:
+		return nil, errors.New("schema cannot be both readOnly and writeOnly")
+	case schemaRef.Value != nil:
+		// Inline schema - set directly
+		schemaRef.Value.ReadOnly = readOnly
+		schemaRef.Value.WriteOnly = writeOnly
+
+		return schemaRef, nil
+	case schemaRef.Ref != "":
+		// OpenAPI 3.0: Reference schema - must wrap with allOf
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				AllOf:     []*openapi3.SchemaRef{schemaRef},
+				ReadOnly:  readOnly,
+				WriteOnly: writeOnly,
+			},
+		}, nil
+	default:
+		return nil, errors.New("invalid schemaRef: both Value and Ref are empty")
+	}
+}
+
+// applyDefault sets the Default field on a schema if needed.
+// For inline schemas (Value != nil), sets default directly.
+// For $ref schemas (Value == nil, Ref != ""), wraps with allOf in OpenAPI 3.0.
+func applyDefault(schemaRef *openapi3.SchemaRef, defaultValue any) (*openapi3.SchemaRef, error) {
+	switch {
+	case defaultValue == nil:
+		return schemaRef, nil
+	case schemaRef.Value != nil:
+		// Inline schema - set default directly
+		schemaRef.Value.Default = defaultValue
+
+		return schemaRef, nil
+	case schemaRef.Ref != "":
+		// OpenAPI 3.0: Reference schema - must wrap with allOf
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				AllOf:   []*openapi3.SchemaRef{schemaRef},
+				Default: defaultValue,
+			},
+		}, nil
+	default:
+		return nil, errors.New("invalid schemaRef: both Value and Ref are empty")
+	}
+}
+
 // applyNullable sets the Nullable field on a schema if needed.
 // For inline schemas (Value != nil), sets nullable directly.
 // For $ref schemas (Value == nil, Ref != ""), wraps with allOf in OpenAPI 3.0.
@@ -155,6 +233,11 @@ func applyDeprecated(schemaRef *openapi3.SchemaRef, deprecated bool) (*openapi3.
 
 // buildPrimitiveSchemaFromFieldType builds a schema for primitive types.
 func buildPrimitiveSchemaFromFieldType(ft FieldType, description string) (*openapi3.SchemaRef, error) {
+	// External types may carry a note about their representation (e.g., units)
+	if ft.Note != "" {
+		description = strings.TrimSpace(description + " " + ft.Note)
+	}
+
 	schema := &openapi3.Schema{
 		Type:        &openapi3.Types{ft.Type},
 		Description: description,
@@ -163,6 +246,17 @@ func buildPrimitiveSchemaFromFieldType(ft FieldType, description string) (*opena
 		schema.Format = ft.Format
 	}
 
+	// Apply validation constraints
+	if ft.MinLength != nil {
+		schema.MinLength = *ft.MinLength
+	}
+
+	schema.MaxLength = ft.MaxLength
+	schema.Min = ft.Minimum
+	schema.Max = ft.Maximum
+	schema.Pattern = ft.Pattern
+	schema.Enum = ft.Enum
+
 	schemaRef := &openapi3.SchemaRef{Value: schema}
 
 	return applyNullable(schemaRef, ft.Nullable)
@@ -188,6 +282,12 @@ func buildArraySchemaFromFieldType(ft FieldType, description string) (*openapi3.
 		Type:        &openapi3.Types{"array"},
 		Items:       itemSchema,
 		Description: description,
+		MaxItems:    ft.MaxItems,
+		UniqueItems: ft.UniqueItems,
+	}
+
+	if ft.MinItems != nil {
+		schema.MinItems = *ft.MinItems
 	}
 
 	schemaRef := &openapi3.SchemaRef{Value: schema}
@@ -232,6 +332,21 @@ func buildObjectSchemaFromFieldType(ft FieldType, description string) (*openapi3
 
 	schema.AdditionalProperties = openapi3.AdditionalProperties{Has: new(false)}
 
+	// Free-form objects (any/interface{}) accept any properties
+	if ft.Type == freeFormObjectType {
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Has: new(true)}
+	}
+
+	// Inline objects (anonymous structs) declare their properties in place
+	if ft.Fields != nil {
+		schema.Properties = make(openapi3.Schemas)
+		schema.Required = []string{}
+
+		if err := addFieldProperties(schema, ft.Fields); err != nil {
+			return nil, fmt.Errorf("inline object: %w", err)
+		}
+	}
+
 	// Handle additionalProperties for map types
 	if ft.AdditionalProperties != nil {
 		// Validate map key type (OpenAPI/JSON only supports string keys)
@@ -380,8 +495,9 @@ func buildAliasSchema(typeInfo *TypeInfo) (*openapi3.Schema, error) {
 func buildComponentSchemas(doc *APIDocumentation) (openapi3.Schemas, error) {
 	schemas := make(openapi3.Schemas)
 
-	// Build schemas only for types marked as used by HTTP
-	for name, typeInfo := range doc.Types {
+	// Build schemas only for types marked as used by HTTP, sorted by name for deterministic output
+	for _, name := range slices.Sorted(maps.Keys(doc.Types)) {
+		typeInfo := doc.Types[name]
 		if !typeInfo.UsedByHTTP {
 			continue
 		}
@@ -414,10 +530,25 @@ func generateOpenAPISpec(doc *APIDocumentation) (*openapi3.T, error) {
 
 	spec.Components.Schemas = schemas
 
-	// Build paths from http_operations
+	spec.Tags = buildTags(doc)
+
+	// Declare security schemes and the default security of all operations
+	if len(doc.Info.SecuritySchemes) > 0 {
+		spec.Components.SecuritySchemes = buildSecuritySchemes(doc.Info.SecuritySchemes)
+	}
+
+	if len(doc.Info.Security) > 0 {
+		spec.Security = *buildSecurityRequirements(doc.Info.Security)
+	}
+
+	// Build paths from http_operations, sorted by path then method for deterministic output
+	routes := slices.SortedFunc(maps.Values(doc.HTTPOperations), func(a, b *RouteInfo) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+
 	pathItems := make(map[string]*openapi3.PathItem)
 
-	for _, route := range doc.HTTPOperations {
+	for _, route := range routes {
 		// Get or create path item for this path
 		pathItem, exists := pathItems[route.Path]
 		if !exists {
@@ -448,8 +579,8 @@ func generateOpenAPISpec(doc *APIDocumentation) (*openapi3.T, error) {
 	}
 
 	// Add all path items to spec
-	for path, pathItem := range pathItems {
-		spec.Paths.Set(path, pathItem)
+	for _, path := range slices.Sorted(maps.Keys(pathItems)) {
+		spec.Paths.Set(path, pathItems[path])
 	}
 
 	return spec, nil
@@ -466,6 +597,14 @@ func buildOperation(route *RouteInfo, types map[string]*TypeInfo) (*openapi3.Ope
 		Responses:   &openapi3.Responses{},
 	}
 
+	// Override the default security, an empty requirement list documents a public route
+	switch {
+	case route.Public:
+		op.Security = openapi3.NewSecurityRequirements()
+	case len(route.Security) > 0:
+		op.Security = buildSecurityRequirements(route.Security)
+	}
+
 	// Add parameters
 	for _, param := range route.Parameters {
 		p := &openapi3.Parameter{
@@ -475,39 +614,27 @@ func buildOperation(route *RouteInfo, types map[string]*TypeInfo) (*openapi3.Ope
 			Description: param.Description,
 		}
 
-		// Build schema for parameter type
-		if typeInfo, ok := types[param.TypeName]; ok {
-			schema, err := toOpenAPISchema(typeInfo)
-			if err != nil {
-				return nil, fmt.Errorf("failed to build schema for parameter %s: %w", param.Name, err)
-			}
-
-			p.Schema = &openapi3.SchemaRef{Value: schema}
-		} else {
-			// Validate that it's a known primitive type before creating inline schema
-			if !isPrimitiveType(param.TypeName) {
-				return nil, fmt.Errorf("parameter %s has unregistered type %s (not found in types map and not a valid primitive type)", param.Name, param.TypeName)
-			}
-
-			// Primitive type - create inline schema
-			p.Schema = &openapi3.SchemaRef{
-				Value: &openapi3.Schema{Type: &openapi3.Types{param.TypeName}},
-			}
+		schema, err := buildScalarSchema(param.TypeName, param.Format, types)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Name, err)
 		}
 
+		p.Schema = schema
+		p.Schema.Value.Default = param.Default
+
 		op.Parameters = append(op.Parameters, &openapi3.ParameterRef{Value: p})
 	}
 
 	// Add request body
 	if route.Request != nil {
-		content, err := buildJSONContent(route.Request.TypeName, route.Request.Examples, types)
+		content, err := buildContent(ContentTypeJSON, route.Request.TypeName, route.Request.Examples, types)
 		if err != nil {
 			return nil, fmt.Errorf("request body: %w", err)
 		}
 
 		op.RequestBody = &openapi3.RequestBodyRef{
 			Value: &openapi3.RequestBody{
-				Required:    true,
+				Required:    route.Request.Required,
 				Description: route.Request.Description,
 				Content:     content,
 			},
@@ -519,8 +646,23 @@ func buildOperation(route *RouteInfo, types map[string]*TypeInfo) (*openapi3.Ope
 		statusStr := strconv.Itoa(statusCode)
 		response := &openapi3.Response{Description: &resp.Description}
 
-		if resp.TypeName != "" {
-			content, err := buildJSONContent(resp.TypeName, resp.Examples, types)
+		mediaType := resp.ContentType
+		if mediaType == "" {
+			mediaType = ContentTypeJSON
+		}
+
+		switch {
+		case len(resp.Variants) > 0:
+			content, err := buildVariantsContent(resp.Variants, types)
+			if err != nil {
+				return nil, fmt.Errorf("response for status %d: %w", statusCode, err)
+			}
+
+			response.Content = content
+		case resp.Binary:
+			response.Content = buildBinaryContent(mediaType)
+		case resp.TypeName != "":
+			content, err := buildContent(mediaType, resp.TypeName, resp.Examples, types)
 			if err != nil {
 				return nil, fmt.Errorf("response for status %d: %w", statusCode, err)
 			}
@@ -528,36 +670,180 @@ func buildOperation(route *RouteInfo, types map[string]*TypeInfo) (*openapi3.Ope
 			response.Content = content
 		}
 
+		for _, header := range resp.Headers {
+			schema, err := buildScalarSchema(header.TypeName, header.Format, types)
+			if err != nil {
+				return nil, fmt.Errorf("header %s for status %d: %w", header.Name, statusCode, err)
+			}
+
+			if response.Headers == nil {
+				response.Headers = openapi3.Headers{}
+			}
+
+			response.Headers[header.Name] = &openapi3.HeaderRef{
+				Value: &openapi3.Header{
+					Parameter: openapi3.Parameter{Description: header.Description, Schema: schema},
+				},
+			}
+		}
+
 		op.Responses.Set(statusStr, &openapi3.ResponseRef{Value: response})
 	}
 
 	return op, nil
 }
 
-// createJSONContent creates OpenAPI content for application/json with given type and examples.
-func createJSONContent(typeName string, examples map[string]any) openapi3.Content {
+// buildTags builds the tags of all route groups and described groups, sorted by name.
+func buildTags(doc *APIDocumentation) openapi3.Tags {
+	groups := maps.Clone(doc.Info.TagDescriptions)
+	if groups == nil {
+		groups = make(map[string]string)
+	}
+
+	for _, route := range doc.HTTPOperations {
+		if _, ok := groups[route.Group]; !ok && route.Group != "" {
+			groups[route.Group] = ""
+		}
+	}
+
+	var tags openapi3.Tags
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		tags = append(tags, &openapi3.Tag{Name: name, Description: groups[name]})
+	}
+
+	return tags
+}
+
+// buildServers converts the server information to OpenAPI servers.
+// Every {variable} in a server URL must have a matching entry in the server variables.
+func buildServers(servers []ServerInfo) (openapi3.Servers, error) {
+	var result openapi3.Servers
+
+	for _, server := range servers {
+		urlVariables, err := ExtractParamName(server.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL %s: %w", server.URL, err)
+		}
+
+		for _, name := range urlVariables {
+			if _, ok := server.Variables[name]; !ok {
+				return nil, fmt.Errorf("server URL %s uses variable %s which is not documented in Variables", server.URL, name)
+			}
+		}
+
+		openAPIServer := &openapi3.Server{
+			URL:         server.URL,
+			Description: server.Description,
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(server.Variables)) {
+			variable := server.Variables[name]
+
+			if !slices.Contains(urlVariables, name) {
+				return nil, fmt.Errorf("server variable %s is not used in server URL %s", name, server.URL)
+			}
+
+			if variable.Default == "" {
+				return nil, fmt.Errorf("server variable %s in server URL %s must have a default value", name, server.URL)
+			}
+
+			if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, variable.Default) {
+				return nil, fmt.Errorf("default value %q of server variable %s must be one of its enum values %v", variable.Default, name, variable.Enum)
+			}
+
+			if openAPIServer.Variables == nil {
+				openAPIServer.Variables = make(map[string]*openapi3.ServerVariable)
+			}
+
+			openAPIServer.Variables[name] = &openapi3.ServerVariable{
+				Default:     variable.Default,
+				Enum:        variable.Enum,
+				Description: variable.Description,
+			}
+		}
+
+		result = append(result, openAPIServer)
+	}
+
+	return result, nil
+}
+
+// buildSecuritySchemes converts the declared security schemes to OpenAPI security schemes.
+func buildSecuritySchemes(schemes map[string]SecuritySchemeInfo) openapi3.SecuritySchemes {
+	securitySchemes := make(openapi3.SecuritySchemes, len(schemes))
+
+	for name, scheme := range schemes {
+		securitySchemes[name] = &openapi3.SecuritySchemeRef{
+			Value: &openapi3.SecurityScheme{
+				Type:         scheme.Type,
+				Description:  scheme.Description,
+				Scheme:       scheme.Scheme,
+				BearerFormat: scheme.BearerFormat,
+				Name:         scheme.Name,
+				In:           scheme.In,
+			},
+		}
+	}
+
+	return securitySchemes
+}
+
+// buildSecurityRequirements builds security requirements where any one of the named schemes is sufficient.
+func buildSecurityRequirements(names []string) *openapi3.SecurityRequirements {
+	requirements := openapi3.NewSecurityRequirements()
+	for _, name := range names {
+		requirements.With(openapi3.NewSecurityRequirement().Authenticate(name))
+	}
+
+	return requirements
+}
+
+// buildScalarSchema builds the inline schema of a parameter or header.
+// Enums are inlined from the types map, primitives use the given OpenAPI type and format.
+func buildScalarSchema(typeName, format string, types map[string]*TypeInfo) (*openapi3.SchemaRef, error) {
+	if typeInfo, ok := types[typeName]; ok {
+		schema, err := toOpenAPISchema(typeInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build schema: %w", err)
+		}
+
+		return &openapi3.SchemaRef{Value: schema}, nil
+	}
+
+	// Validate that it's a known primitive type before creating inline schema
+	if !isPrimitiveType(typeName) {
+		return nil, fmt.Errorf("unregistered type %s (not found in types map and not a valid primitive type)", typeName)
+	}
+
+	return &openapi3.SchemaRef{
+		Value: &openapi3.Schema{Type: &openapi3.Types{typeName}, Format: format},
+	}, nil
+}
+
+// createContent creates OpenAPI content for the given media type with given type and examples.
+func createContent(mediaType, typeName string, examples map[string]any) openapi3.Content {
 	return openapi3.Content{
-		"application/json": &openapi3.MediaType{
+		mediaType: &openapi3.MediaType{
 			Schema:   createSchemaRef(typeName),
 			Examples: convertExamplesToOpenAPI(examples),
 		},
 	}
 }
 
-// buildJSONContent creates OpenAPI content for application/json with validation.
+// buildContent creates OpenAPI content for the given media type with validation.
 // Returns content for registered types (via reference), inline schemas for primitives, or error for unknown types.
-func buildJSONContent(typeName string, examples map[string]any, types map[string]*TypeInfo) (openapi3.Content, error) {
+func buildContent(mediaType, typeName string, examples map[string]any, types map[string]*TypeInfo) (openapi3.Content, error) {
 	// Check if type is registered in types map
 	if _, ok := types[typeName]; ok {
-		// Type exists - create reference via createJSONContent
-		return createJSONContent(typeName, examples), nil
+		// Type exists - create reference via createContent
+		return createContent(mediaType, typeName, examples), nil
 	}
 
 	// Check if it's a primitive type
 	if isPrimitiveType(typeName) {
 		// Primitive type - create inline schema
 		return openapi3.Content{
-			"application/json": &openapi3.MediaType{
+			mediaType: &openapi3.MediaType{
 				Schema: &openapi3.SchemaRef{
 					Value: &openapi3.Schema{Type: &openapi3.Types{typeName}},
 				},
@@ -570,6 +856,33 @@ func buildJSONContent(typeName string, examples map[string]any, types map[string
 	return nil, fmt.Errorf("type %s not found in types map and not a valid primitive type", typeName)
 }
 
+// buildVariantsContent creates OpenAPI content with one media type per content variant.
+func buildVariantsContent(variants []ContentVariantInfo, types map[string]*TypeInfo) (openapi3.Content, error) {
+	content := openapi3.Content{}
+
+	for _, variant := range variants {
+		variantContent, err := buildContent(variant.ContentType, variant.TypeName, variant.Examples, types)
+		if err != nil {
+			return nil, fmt.Errorf("content type %s: %w", variant.ContentType, err)
+		}
+
+		maps.Copy(content, variantContent)
+	}
+
+	return content, nil
+}
+
+// buildBinaryContent creates OpenAPI content for a raw (non-JSON) body of the given media type.
+func buildBinaryContent(mediaType string) openapi3.Content {
+	return openapi3.Content{
+		mediaType: &openapi3.MediaType{
+			Schema: &openapi3.SchemaRef{
+				Value: &openapi3.Schema{Type: &openapi3.Types{typeString}, Format: FormatBinary},
+			},
+		},
+	}
+}
+
 // createSchemaRef creates a schema reference for the given type name.
 func createSchemaRef(typeName string) *openapi3.SchemaRef {
 	return &openapi3.SchemaRef{
@@ -0,0 +1,864 @@
+package generate
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+)
+
+func TestBuildOperationResponseContentTypes(t *testing.T) {
+	t.Parallel()
+
+	types := map[string]*TypeInfo{
+		"Report": {Name: "Report", Kind: TypeKindObject},
+	}
+
+	route := &RouteInfo{
+		OperationID: "exportReport",
+		Method:      "GET",
+		Path:        "/reports",
+		Group:       "Reports",
+		Responses: map[int]ResponseInfo{
+			200: {StatusCode: 200, Description: "CSV export", ContentType: "text/csv", Binary: true},
+			201: {StatusCode: 201, Description: "Report", TypeName: "Report"},
+			202: {StatusCode: 202, Description: "Report", TypeName: "Report", ContentType: "application/vnd.report+json"},
+		},
+	}
+
+	op, err := buildOperation(route, types)
+	if err != nil {
+		t.Fatalf("buildOperation() error = %v", err)
+	}
+
+	binary := op.Responses.Value("200").Value.Content.Get("text/csv")
+	if binary == nil {
+		t.Fatal("binary response missing text/csv content")
+	}
+
+	if !binary.Schema.Value.Type.Is(typeString) || binary.Schema.Value.Format != FormatBinary {
+		t.Errorf("binary schema = %+v, want string/binary", binary.Schema.Value)
+	}
+
+	if op.Responses.Value("201").Value.Content.Get(ContentTypeJSON) == nil {
+		t.Error("response without ContentType should default to application/json")
+	}
+
+	if op.Responses.Value("202").Value.Content.Get("application/vnd.report+json") == nil {
+		t.Error("response with custom ContentType missing its media type")
+	}
+}
+
+func TestBuildOperationResponseContentVariants(t *testing.T) {
+	t.Parallel()
+
+	types := map[string]*TypeInfo{
+		"Report": {Name: "Report", Kind: TypeKindObject},
+	}
+
+	route := &RouteInfo{
+		OperationID: "exportReport",
+		Method:      "GET",
+		Path:        "/reports",
+		Group:       "Reports",
+		Responses: map[int]ResponseInfo{
+			200: {StatusCode: 200, Description: "Report export", Variants: []ContentVariantInfo{
+				{ContentType: ContentTypeJSON, TypeName: "Report", Examples: map[string]any{"json": map[string]any{"id": 1}}},
+				{ContentType: "text/csv", TypeName: typeString, Examples: map[string]any{"csv": "id\n1\n"}},
+			}},
+		},
+	}
+
+	op, err := buildOperation(route, types)
+	if err != nil {
+		t.Fatalf("buildOperation() error = %v", err)
+	}
+
+	content := op.Responses.Value("200").Value.Content
+	if len(content) != 2 {
+		t.Fatalf("response content has %d media types, want 2", len(content))
+	}
+
+	jsonContent := content.Get(ContentTypeJSON)
+	if jsonContent == nil || jsonContent.Schema.Ref != "#/components/schemas/Report" {
+		t.Fatalf("application/json content = %+v, want a Report reference", jsonContent)
+	}
+
+	csvContent := content.Get("text/csv")
+	if csvContent == nil || !csvContent.Schema.Value.Type.Is(typeString) {
+		t.Fatalf("text/csv content = %+v, want a string schema", csvContent)
+	}
+
+	if _, ok := jsonContent.Examples["json"]; !ok || len(jsonContent.Examples) != 1 {
+		t.Errorf("application/json examples = %v, want only json", jsonContent.Examples)
+	}
+
+	if _, ok := csvContent.Examples["csv"]; !ok || len(csvContent.Examples) != 1 {
+		t.Errorf("text/csv examples = %v, want only csv", csvContent.Examples)
+	}
+}
+
+func TestProcessContentVariantsValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response ResponseInfo
+		wantErr  string
+	}{
+		{
+			name:     "type with variants",
+			response: ResponseInfo{TypeValue: struct{}{}, Variants: []ContentVariantInfo{{ContentType: "text/csv", TypeValue: ""}}},
+			wantErr:  "must be unset",
+		},
+		{
+			name:     "missing content type",
+			response: ResponseInfo{Variants: []ContentVariantInfo{{TypeValue: ""}}},
+			wantErr:  "ContentType required",
+		},
+		{
+			name:     "duplicate content type",
+			response: ResponseInfo{Variants: []ContentVariantInfo{{ContentType: "text/csv", TypeValue: ""}, {ContentType: "text/csv", TypeValue: ""}}},
+			wantErr:  "duplicate ContentType text/csv",
+		},
+		{
+			name:     "nil type",
+			response: ResponseInfo{Variants: []ContentVariantInfo{{ContentType: "text/csv"}}},
+			wantErr:  "must not be nil",
+		},
+		{
+			name:     "non string primitive",
+			response: ResponseInfo{Variants: []ContentVariantInfo{{ContentType: "text/plain", TypeValue: 0}}},
+			wantErr:  "zero value struct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := &OpenAPICollector{}
+
+			err := g.processContentVariants(&tt.response)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("processContentVariants() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildOperationOptionalRequestBody(t *testing.T) {
+	t.Parallel()
+
+	types := map[string]*TypeInfo{
+		"PatchItemRequest": {Name: "PatchItemRequest", Kind: TypeKindObject},
+	}
+
+	for _, required := range []bool{true, false} {
+		route := &RouteInfo{
+			OperationID: "patchItem",
+			Method:      "PATCH",
+			Path:        "/items/{id}",
+			Request:     &RequestInfo{TypeName: "PatchItemRequest", Required: required},
+		}
+
+		op, err := buildOperation(route, types)
+		if err != nil {
+			t.Fatalf("buildOperation() error = %v", err)
+		}
+
+		if op.RequestBody.Value.Required != required {
+			t.Errorf("RequestBody.Required = %v, want %v", op.RequestBody.Value.Required, required)
+		}
+	}
+}
+
+func TestValidateBinaryResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response ResponseInfo
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			response: ResponseInfo{ContentType: "application/octet-stream", Binary: true},
+		},
+		{
+			name:     "json content type",
+			response: ResponseInfo{ContentType: ContentTypeJSON, Binary: true},
+			wantErr:  true,
+		},
+		{
+			name:     "with type",
+			response: ResponseInfo{ContentType: "text/csv", Binary: true, TypeValue: struct{}{}},
+			wantErr:  true,
+		},
+		{
+			name:     "with examples",
+			response: ResponseInfo{ContentType: "text/csv", Binary: true, Examples: map[string]any{"a": "b"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateBinaryResponse(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBinaryResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildOperationQueryParameters(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		types:                map[string]*TypeInfo{},
+		externalTypeFormats:  getExternalTypeMappings(),
+		primitiveTypeMapping: getPrimitiveTypeMappings(),
+	}
+
+	params := []ParameterInfo{
+		{Name: "limit", In: "query", TypeValue: new(int32), Default: int32(20), Description: "Page size"},
+		{Name: "cursor", In: "query", TypeValue: new(string), Description: "Page cursor"},
+		{Name: "teamID", In: "query", TypeValue: new(utils.UUID), Description: "Team filter"},
+	}
+
+	for i := range params {
+		if err := g.processHTTPParameter(&params[i]); err != nil {
+			t.Fatalf("processHTTPParameter(%s) error = %v", params[i].Name, err)
+		}
+	}
+
+	op, err := buildOperation(&RouteInfo{OperationID: "listItems", Parameters: params, Responses: map[int]ResponseInfo{}}, g.types)
+	if err != nil {
+		t.Fatalf("buildOperation() error = %v", err)
+	}
+
+	limit := op.Parameters.GetByInAndName("query", "limit")
+	if limit == nil {
+		t.Fatal("limit parameter missing")
+	}
+
+	schema := limit.Schema.Value
+	if !schema.Type.Is(typeInteger) || schema.Format != "int32" || schema.Default != int32(20) {
+		t.Errorf("limit schema = %+v, want integer/int32 with default 20", schema)
+	}
+
+	if op.Parameters.GetByInAndName("query", "cursor") == nil {
+		t.Error("cursor parameter missing")
+	}
+
+	// External types use their configured format
+	teamID := op.Parameters.GetByInAndName("query", "teamID")
+	if teamID == nil || !teamID.Schema.Value.Type.Is(typeString) || teamID.Schema.Value.Format != FormatUUID {
+		t.Errorf("teamID parameter = %+v, want string/uuid", teamID)
+	}
+}
+
+func TestBuildOperationCookieParameter(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		types:                map[string]*TypeInfo{},
+		externalTypeFormats:  getExternalTypeMappings(),
+		primitiveTypeMapping: getPrimitiveTypeMappings(),
+	}
+
+	params := []ParameterInfo{
+		{Name: "session", In: "cookie", TypeValue: new(string), Required: true, Description: "Session token"},
+	}
+
+	if err := g.processHTTPParameter(&params[0]); err != nil {
+		t.Fatalf("processHTTPParameter() error = %v", err)
+	}
+
+	op, err := buildOperation(&RouteInfo{OperationID: "getProfile", Parameters: params, Responses: map[int]ResponseInfo{}}, g.types)
+	if err != nil {
+		t.Fatalf("buildOperation() error = %v", err)
+	}
+
+	session := op.Parameters.GetByInAndName(openapi3.ParameterInCookie, "session")
+	if session == nil {
+		t.Fatal("session cookie parameter missing")
+	}
+
+	if err := session.Validate(context.Background()); err != nil {
+		t.Errorf("cookie parameter is not valid OpenAPI: %v", err)
+	}
+
+	if !session.Required || !session.Schema.Value.Type.Is(typeString) {
+		t.Errorf("session parameter = %+v, want a required string", session)
+	}
+}
+
+func TestBuildOperationResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		types: map[string]*TypeInfo{
+			"testFilter": {Name: "testFilter", Kind: TypeKindObject},
+		},
+		primitiveTypeMapping: getPrimitiveTypeMappings(),
+	}
+
+	headers := []HeaderInfo{
+		{Name: "Location", TypeValue: new(string), Description: "URL of the created resource"},
+		{Name: "X-RateLimit-Remaining", TypeValue: new(int), Description: "Requests left in the current window"},
+	}
+
+	for i := range headers {
+		if err := g.processHTTPHeader(&headers[i]); err != nil {
+			t.Fatalf("processHTTPHeader(%s) error = %v", headers[i].Name, err)
+		}
+	}
+
+	route := &RouteInfo{
+		OperationID: "createItem",
+		Responses: map[int]ResponseInfo{
+			201: {StatusCode: 201, Description: "Created", Headers: headers},
+		},
+	}
+
+	op, err := buildOperation(route, g.types)
+	if err != nil {
+		t.Fatalf("buildOperation() error = %v", err)
+	}
+
+	responseHeaders := op.Responses.Value("201").Value.Headers
+
+	location := responseHeaders["Location"]
+	if location == nil || !location.Value.Schema.Value.Type.Is(typeString) || location.Value.Description != "URL of the created resource" {
+		t.Errorf("Location header = %+v, want a described string header", location)
+	}
+
+	remaining := responseHeaders["X-RateLimit-Remaining"]
+	if remaining == nil || !remaining.Value.Schema.Value.Type.Is(typeInteger) {
+		t.Errorf("X-RateLimit-Remaining header = %+v, want an integer header", remaining)
+	}
+
+	for _, header := range []HeaderInfo{
+		{Name: "X-Missing-Description", TypeValue: new(string)},
+		{Name: "X-Nil-Type", Description: "Nil type"},
+		{Name: "X-Object", TypeValue: &testFilter{}, Description: "Object type"},
+	} {
+		if err := g.processHTTPHeader(&header); err == nil {
+			t.Errorf("processHTTPHeader(%s) expected error", header.Name)
+		}
+	}
+}
+
+// testFilter is an object type used to check that objects are rejected as parameter types.
+type testFilter struct{}
+
+func TestProcessHTTPParameterErrors(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		types: map[string]*TypeInfo{
+			"testFilter": {Name: "testFilter", Kind: TypeKindObject},
+		},
+		primitiveTypeMapping: getPrimitiveTypeMappings(),
+	}
+
+	tests := []struct {
+		name  string
+		param ParameterInfo
+	}{
+		{name: "nil type", param: ParameterInfo{Name: "q"}},
+		{name: "required with default", param: ParameterInfo{Name: "q", TypeValue: new(string), Required: true, Default: "x"}},
+		{name: "default type mismatch", param: ParameterInfo{Name: "q", TypeValue: new(int), Default: "x"}},
+		{name: "object type", param: ParameterInfo{Name: "q", TypeValue: &testFilter{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			param := tt.param
+			if err := g.processHTTPParameter(&param); err == nil {
+				t.Error("processHTTPParameter() expected error")
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpecDeterministic(t *testing.T) {
+	t.Parallel()
+
+	newDoc := func() *APIDocumentation {
+		doc := &APIDocumentation{
+			Types:          map[string]*TypeInfo{},
+			HTTPOperations: map[string]*RouteInfo{},
+		}
+
+		for _, name := range []string{"Zebra", "Apple", "Mango", "Kiwi", "Banana", "Cherry"} {
+			doc.Types[name] = &TypeInfo{
+				Name:       name,
+				Kind:       TypeKindObject,
+				UsedByHTTP: true,
+				Fields: []FieldInfo{
+					{Name: "id", TypeInfo: FieldType{Kind: FieldKindPrimitive, Type: typeString, Required: true}},
+				},
+			}
+
+			for _, method := range []string{"GET", "POST", "DELETE"} {
+				operationID := strings.ToLower(method) + name
+				doc.HTTPOperations[operationID] = &RouteInfo{
+					OperationID: operationID,
+					Method:      method,
+					Path:        "/" + strings.ToLower(name),
+					Group:       name,
+					Responses: map[int]ResponseInfo{
+						200: {StatusCode: 200, Description: "OK", TypeName: name},
+					},
+				}
+			}
+		}
+
+		return doc
+	}
+
+	render := func() string {
+		spec, err := generateOpenAPISpec(newDoc())
+		if err != nil {
+			t.Fatalf("generateOpenAPISpec() error = %v", err)
+		}
+
+		data, err := yaml.Marshal(spec)
+		if err != nil {
+			t.Fatalf("failed to marshal spec: %v", err)
+		}
+
+		return string(data)
+	}
+
+	first := render()
+	for range 10 {
+		if got := render(); got != first {
+			t.Fatal("generateOpenAPISpec() output is not deterministic")
+		}
+	}
+
+	// Paths and schemas are emitted in sorted order
+	if strings.Index(first, "/apple:") > strings.Index(first, "/zebra:") {
+		t.Error("paths are not sorted")
+	}
+
+	if strings.Index(first, "    Apple:") > strings.Index(first, "    Zebra:") {
+		t.Error("schemas are not sorted")
+	}
+}
+
+func TestGenerateOpenAPISpecExampleOrder(t *testing.T) {
+	t.Parallel()
+
+	examples := map[string]any{
+		"zeta":  map[string]any{"id": "3"},
+		"alpha": map[string]any{"id": "1"},
+		"mid":   map[string]any{"id": "2"},
+		"beta":  map[string]any{"id": "4"},
+	}
+
+	render := func() string {
+		doc := &APIDocumentation{
+			Types: map[string]*TypeInfo{
+				"Item": {Name: "Item", Kind: TypeKindObject, UsedByHTTP: true},
+			},
+			HTTPOperations: map[string]*RouteInfo{
+				"getItem": {
+					OperationID: "getItem",
+					Method:      "GET",
+					Path:        "/item",
+					Group:       "Items",
+					Responses: map[int]ResponseInfo{
+						200: {StatusCode: 200, Description: "OK", TypeName: "Item", Examples: examples},
+					},
+				},
+			},
+		}
+
+		spec, err := generateOpenAPISpec(doc)
+		if err != nil {
+			t.Fatalf("generateOpenAPISpec() error = %v", err)
+		}
+
+		data, err := yaml.Marshal(spec)
+		if err != nil {
+			t.Fatalf("failed to marshal spec: %v", err)
+		}
+
+		return string(data)
+	}
+
+	first := render()
+	for range 10 {
+		if got := render(); got != first {
+			t.Fatal("example order is not deterministic")
+		}
+	}
+
+	positions := make([]int, 0, len(examples))
+	for _, name := range []string{"alpha", "beta", "mid", "zeta"} {
+		positions = append(positions, strings.Index(first, "    "+name+":"))
+	}
+
+	if !slices.IsSorted(positions) || positions[0] == -1 {
+		t.Errorf("examples are not listed by name, positions = %v\n%s", positions, first)
+	}
+}
+
+func TestGenerateOpenAPISpecSecurity(t *testing.T) {
+	t.Parallel()
+
+	doc := &APIDocumentation{
+		Types: map[string]*TypeInfo{},
+		HTTPOperations: map[string]*RouteInfo{
+			"getItem":    {OperationID: "getItem", Method: "GET", Path: "/item", Responses: map[int]ResponseInfo{}},
+			"login":      {OperationID: "login", Method: "POST", Path: "/login", Public: true, Responses: map[int]ResponseInfo{}},
+			"deleteItem": {OperationID: "deleteItem", Method: "DELETE", Path: "/item", Security: []string{"apiKeyAuth", "bearerAuth"}, Responses: map[int]ResponseInfo{}},
+		},
+		Info: APIInfo{
+			SecuritySchemes: map[string]SecuritySchemeInfo{
+				"bearerAuth": {Type: SecuritySchemeTypeHTTP, Scheme: "bearer", BearerFormat: "JWT"},
+				"apiKeyAuth": {Type: SecuritySchemeTypeAPIKey, Name: "X-API-Key", In: "header"},
+			},
+			Security: []string{"bearerAuth"},
+		},
+	}
+
+	if err := validateSecuritySchemes(doc.Info); err != nil {
+		t.Fatalf("validateSecuritySchemes() error = %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(doc)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	bearer := spec.Components.SecuritySchemes["bearerAuth"]
+	if bearer == nil || bearer.Value.Type != "http" || bearer.Value.Scheme != "bearer" {
+		t.Errorf("bearerAuth scheme = %+v, want http bearer", bearer)
+	}
+
+	if len(spec.Security) != 1 || spec.Security[0]["bearerAuth"] == nil {
+		t.Errorf("default security = %v, want bearerAuth", spec.Security)
+	}
+
+	item := spec.Paths.Find("/item")
+
+	if item.Get.Security != nil {
+		t.Errorf("getItem security = %v, want the default", *item.Get.Security)
+	}
+
+	if got := item.Delete.Security; got == nil || len(*got) != 2 {
+		t.Errorf("deleteItem security = %v, want apiKeyAuth or bearerAuth", got)
+	}
+
+	// Public routes override the default with an empty requirement list
+	data, err := yaml.Marshal(spec.Paths.Find("/login").Post)
+	if err != nil {
+		t.Fatalf("failed to marshal operation: %v", err)
+	}
+
+	if !strings.Contains(string(data), "security: []") {
+		t.Errorf("login operation = %s, want an empty security list", data)
+	}
+}
+
+func TestValidateSecuritySchemes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		info APIInfo
+	}{
+		{name: "unknown type", info: APIInfo{SecuritySchemes: map[string]SecuritySchemeInfo{"auth": {Type: "oauth2"}}}},
+		{name: "http without scheme", info: APIInfo{SecuritySchemes: map[string]SecuritySchemeInfo{"auth": {Type: SecuritySchemeTypeHTTP}}}},
+		{name: "apiKey without name", info: APIInfo{SecuritySchemes: map[string]SecuritySchemeInfo{"auth": {Type: SecuritySchemeTypeAPIKey, In: "header"}}}},
+		{name: "apiKey with invalid in", info: APIInfo{SecuritySchemes: map[string]SecuritySchemeInfo{"auth": {Type: SecuritySchemeTypeAPIKey, Name: "key", In: "body"}}}},
+		{name: "undeclared default", info: APIInfo{Security: []string{"auth"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := validateSecuritySchemes(tt.info); err == nil {
+				t.Error("validateSecuritySchemes() expected error")
+			}
+		})
+	}
+}
+
+func TestBuildServers(t *testing.T) {
+	t.Parallel()
+
+	region := ServerVariable{Default: "eu", Enum: []string{"eu", "us"}, Description: "Region of the tenant"}
+
+	tests := []struct {
+		name    string
+		server  ServerInfo
+		wantErr bool
+	}{
+		{name: "plain URL", server: ServerInfo{URL: "http://localhost:8080"}},
+		{name: "templated URL", server: ServerInfo{URL: "https://{region}.api.example.com", Variables: map[string]ServerVariable{"region": region}}},
+		{name: "missing variable", server: ServerInfo{URL: "https://{region}.api.example.com"}, wantErr: true},
+		{name: "unused variable", server: ServerInfo{URL: "https://api.example.com", Variables: map[string]ServerVariable{"region": region}}, wantErr: true},
+		{name: "missing default", server: ServerInfo{URL: "https://{region}.api.example.com", Variables: map[string]ServerVariable{"region": {}}}, wantErr: true},
+		{name: "default not in enum", server: ServerInfo{URL: "https://{region}.api.example.com", Variables: map[string]ServerVariable{"region": {Default: "ap", Enum: []string{"eu", "us"}}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			servers, err := buildServers([]ServerInfo{tt.server})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildServers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := servers[0].Variables; len(got) != len(tt.server.Variables) {
+				t.Errorf("variables = %v, want %v", got, tt.server.Variables)
+			}
+		})
+	}
+}
+
+func TestBuildTags(t *testing.T) {
+	t.Parallel()
+
+	doc := &APIDocumentation{
+		HTTPOperations: map[string]*RouteInfo{
+			"getTeam": {OperationID: "getTeam", Group: "Team"},
+			"ping":    {OperationID: "ping", Group: "Core"},
+		},
+		Info: APIInfo{TagDescriptions: map[string]string{"Team": "Team management", "Admin": "Administration"}},
+	}
+
+	tags := buildTags(doc)
+
+	want := []struct{ name, description string }{
+		{name: "Admin", description: "Administration"},
+		{name: "Core", description: ""},
+		{name: "Team", description: "Team management"},
+	}
+
+	if len(tags) != len(want) {
+		t.Fatalf("buildTags() returned %d tags, want %d", len(tags), len(want))
+	}
+
+	for i, w := range want {
+		if tags[i].Name != w.name || tags[i].Description != w.description {
+			t.Errorf("tags[%d] = %s/%q, want %s/%q", i, tags[i].Name, tags[i].Description, w.name, w.description)
+		}
+	}
+}
+
+func TestRegisterRouteStrictTags(t *testing.T) {
+	t.Parallel()
+
+	g := &OpenAPICollector{
+		httpOps:           make(map[string]*RouteInfo),
+		mqttPublications:  make(map[string]*MQTTPublicationInfo),
+		mqttSubscriptions: make(map[string]*MQTTSubscriptionInfo),
+		apiInfo:           APIInfo{TagDescriptions: map[string]string{"Core": "Core operations"}},
+		strictTags:        true,
+	}
+
+	if err := g.RegisterRoute(&RouteInfo{OperationID: "ping", Group: "Core"}); err != nil {
+		t.Errorf("RegisterRoute() with described group error = %v", err)
+	}
+
+	if err := g.RegisterRoute(&RouteInfo{OperationID: "getTeam", Group: "Team"}); err == nil {
+		t.Error("RegisterRoute() with undescribed group expected error")
+	}
+}
+
+type testExampleUser struct {
+	Name string `json:"name"`
+}
+
+type testExampleTeam struct {
+	Name    string `json:"name"`
+	Members int    `json:"members"`
+}
+
+func TestValidateExamplesMatchType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		examples map[string]any
+		wantErr  bool
+	}{
+		{name: "same type", examples: map[string]any{"ok": testExampleUser{Name: "John"}}},
+		{name: "pointer to same type", examples: map[string]any{"ok": &testExampleUser{Name: "John"}}},
+		{name: "map with known fields", examples: map[string]any{"ok": map[string]any{"name": "John"}}},
+		{name: "other type with unknown field", examples: map[string]any{"stale": testExampleTeam{Name: "Team"}}, wantErr: true},
+		{name: "wrong field type", examples: map[string]any{"stale": map[string]any{"name": 1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateExamplesMatchType(testExampleUser{}, tt.examples)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateExamplesMatchType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), "[stale]") {
+				t.Errorf("error = %q, want it to name the example", err)
+			}
+		})
+	}
+}
+
+type testDeviceReading struct {
+	DeviceID int64 `json:"deviceID"`
+}
+
+func TestGenerateOpenAPISpecLargeIntegerExample(t *testing.T) {
+	t.Parallel()
+
+	doc := &APIDocumentation{
+		Types: map[string]*TypeInfo{
+			"testDeviceReading": {
+				Name:       "testDeviceReading",
+				Kind:       TypeKindObject,
+				UsedByHTTP: true,
+				Fields: []FieldInfo{
+					{Name: "deviceID", TypeInfo: FieldType{Kind: FieldKindPrimitive, Type: typeInteger, Format: "int64", Required: true}},
+				},
+			},
+		},
+		HTTPOperations: map[string]*RouteInfo{
+			"getReading": {
+				OperationID: "getReading",
+				Method:      "GET",
+				Path:        "/reading",
+				Responses: map[int]ResponseInfo{
+					200: {
+						StatusCode:  200,
+						Description: "OK",
+						TypeName:    "testDeviceReading",
+						Examples:    map[string]any{"big": testDeviceReading{DeviceID: 1234567890123456789}},
+					},
+				},
+			},
+		},
+	}
+
+	spec, err := generateOpenAPISpec(doc)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed to marshal spec: %v", err)
+	}
+
+	// A float64 round trip would render 1234567890123456768
+	if !strings.Contains(string(data), "deviceID: 1234567890123456789") {
+		t.Errorf("spec does not contain the exact 19-digit example:\n%s", data)
+	}
+}
+
+func TestBuildComponentSchemasArrayReferences(t *testing.T) {
+	t.Parallel()
+
+	src := `package team
+
+// User is only referenced through GetTeamResponse.
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type GetTeamResponse struct {
+	Users  []User  ` + "`json:\"users\"`" + `
+	Admins []*User ` + "`json:\"admins\"`" + `
+	Guests *[]User ` + "`json:\"guests\"`" + `
+}
+`
+
+	g, _ := newSourceTestCollector(t, src)
+
+	if err := g.extractAllTypesFromGo(g.goParser); err != nil {
+		t.Fatalf("extractAllTypesFromGo() error = %v", err)
+	}
+
+	g.markTypeAsHTTP("GetTeamResponse")
+
+	if !g.types["User"].UsedByHTTP {
+		t.Error("User.UsedByHTTP = false, want true")
+	}
+
+	schemas, err := buildComponentSchemas(g.getDocumentation())
+	if err != nil {
+		t.Fatalf("buildComponentSchemas() error = %v", err)
+	}
+
+	if _, ok := schemas["User"]; !ok {
+		t.Fatal("User missing from component schemas")
+	}
+
+	const userRef = "#/components/schemas/User"
+
+	properties := schemas["GetTeamResponse"].Value.Properties
+
+	tests := []struct {
+		property      string
+		arrayNullable bool
+		itemNullable  bool
+	}{
+		{property: "users", arrayNullable: false, itemNullable: false},
+		{property: "admins", arrayNullable: false, itemNullable: true},
+		{property: "guests", arrayNullable: true, itemNullable: false},
+	}
+
+	for _, tt := range tests {
+		array := properties[tt.property].Value
+		if !array.Type.Is("array") {
+			t.Errorf("%s type = %v, want array", tt.property, array.Type)
+
+			continue
+		}
+
+		if array.Nullable != tt.arrayNullable {
+			t.Errorf("%s nullable = %v, want %v", tt.property, array.Nullable, tt.arrayNullable)
+		}
+
+		items := array.Items
+
+		// Nullable references are wrapped with allOf, as $ref siblings are ignored in OpenAPI 3.0
+		if tt.itemNullable {
+			if items.Value == nil || !items.Value.Nullable || len(items.Value.AllOf) != 1 {
+				t.Errorf("%s items = %+v, want nullable allOf wrapper", tt.property, items)
+
+				continue
+			}
+
+			items = items.Value.AllOf[0]
+		}
+
+		if items.Ref != userRef {
+			t.Errorf("%s items $ref = %q, want %q", tt.property, items.Ref, userRef)
+		}
+	}
+}
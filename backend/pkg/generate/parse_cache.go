@@ -0,0 +1,157 @@
+package generate
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/coder/guts"
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseCache shares parse results between collectors created in the same process.
+// Generating the docs of several deployments then loads each Go types directory once: the Go packages are
+// cached per directory, so a directory used by several deployments (e.g., shared types) is type-checked once.
+// The TypeScript AST needs every directory of a set to resolve references between them, so it is only reused
+// by collectors with the same directory set. Database schemas are cached per deployment, so the migrations
+// of a deployment run once.
+// A ParseCache is safe for concurrent use.
+type ParseCache struct {
+	mu          sync.Mutex
+	fset        *token.FileSet              // Shared by all cached packages, so their positions stay comparable
+	goParsers   map[string]*GoParser        // Parsed Go packages, keyed by directory
+	typescripts map[string]*guts.Typescript // TypeScript ASTs, keyed by directory set and external type formats
+	schemas     map[string]string           // Database schemas, keyed by deployment and output path
+}
+
+// NewParseCache creates an empty parse cache.
+func NewParseCache() *ParseCache {
+	return &ParseCache{
+		fset:        token.NewFileSet(),
+		goParsers:   make(map[string]*GoParser),
+		typescripts: make(map[string]*guts.Typescript),
+		schemas:     make(map[string]string),
+	}
+}
+
+// goParser returns the Go packages of the directories, calling parse for each directory missing from the cache.
+// A nil cache parses all directories with a single call, as loading them together is faster than one by one.
+func (c *ParseCache) goParser(goTypesDirPaths []string, parse func(fset *token.FileSet, goTypesDirPaths []string) (*GoParser, error)) (*GoParser, error) {
+	if c == nil {
+		return parse(token.NewFileSet(), goTypesDirPaths)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		files []*ast.File
+		pkgs  []*packages.Package
+		seen  = make(map[string]struct{}, len(goTypesDirPaths))
+	)
+
+	for _, dir := range goTypesDirPaths {
+		key := normalizeLocalPackagePath(dir)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		dirParser, ok := c.goParsers[key]
+		if !ok {
+			var err error
+
+			dirParser, err = parse(c.fset, []string{dir})
+			if err != nil {
+				return nil, err
+			}
+
+			c.goParsers[key] = dirParser
+		}
+
+		files = append(files, dirParser.files...)
+		pkgs = append(pkgs, dirParser.packages...)
+	}
+
+	return &GoParser{fset: c.fset, files: files, packages: pkgs}, nil
+}
+
+// typescript returns the cached TypeScript AST of the directory set and external type formats, calling parse on a miss.
+// A nil cache always calls parse.
+func (c *ParseCache) typescript(goTypesDirPaths []string, formats map[string]ExternalTypeFormat, parse func() (*guts.Typescript, error)) (*guts.Typescript, error) {
+	if c == nil {
+		return parse()
+	}
+
+	// The overrides built from the formats change the generated AST, so they are part of the key
+	key := dirSetKey(goTypesDirPaths) + "\n" + externalTypeFormatsKey(formats)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ts, ok := c.typescripts[key]; ok {
+		return ts, nil
+	}
+
+	ts, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	c.typescripts[key] = ts
+
+	return ts, nil
+}
+
+// databaseSchema returns the cached database schema of the deployment, calling generate on a miss.
+// The output path is part of the key, as generate also writes the schema files there.
+// A nil cache always calls generate.
+func (c *ParseCache) databaseSchema(deployment, outputPath string, generate func() (string, error)) (string, error) {
+	if c == nil {
+		return generate()
+	}
+
+	key := deployment + "\x00" + outputPath
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema, ok := c.schemas[key]; ok {
+		return schema, nil
+	}
+
+	schema, err := generate()
+	if err != nil {
+		return "", err
+	}
+
+	c.schemas[key] = schema
+
+	return schema, nil
+}
+
+// dirSetKey returns a key identifying a set of directories, independent of their order and duplicates.
+func dirSetKey(dirs []string) string {
+	normalized := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		normalized = append(normalized, normalizeLocalPackagePath(dir))
+	}
+
+	slices.Sort(normalized)
+
+	return strings.Join(slices.Compact(normalized), "\x00")
+}
+
+// externalTypeFormatsKey returns a key identifying the TypeScript primitives the external types render as.
+func externalTypeFormatsKey(formats map[string]ExternalTypeFormat) string {
+	entries := make([]string, 0, len(formats))
+	for _, fullPath := range slices.Sorted(maps.Keys(formats)) {
+		entries = append(entries, fullPath+"="+formats[fullPath].Type)
+	}
+
+	return strings.Join(entries, "\x00")
+}
@@ -0,0 +1,160 @@
+package generate
+
+import (
+	"go/ast"
+	"go/token"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDirSetKey(t *testing.T) {
+	t.Parallel()
+
+	base := dirSetKey([]string{"backend/internal/shared/types", "backend/internal/cloud/api/types"})
+
+	tests := []struct {
+		name string
+		dirs []string
+		same bool
+	}{
+		{name: "reordered", dirs: []string{"backend/internal/cloud/api/types", "backend/internal/shared/types"}, same: true},
+		{name: "normalized", dirs: []string{"./backend/internal/shared/types", "/backend/internal/cloud/api/types"}, same: true},
+		{name: "duplicated", dirs: []string{"backend/internal/shared/types", "backend/internal/cloud/api/types", "backend/internal/shared/types"}, same: true},
+		{name: "subset", dirs: []string{"backend/internal/shared/types"}, same: false},
+		{name: "different", dirs: []string{"backend/internal/shared/types", "backend/internal/local/api/types"}, same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := dirSetKey(tt.dirs) == base; got != tt.same {
+				t.Errorf("dirSetKey(%v) == base is %v, want %v", tt.dirs, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestParseCacheGoParser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cache     *ParseCache
+		wantCalls []string
+	}{
+		{name: "nil cache", cache: nil, wantCalls: []string{"shared,local", "shared,cloud"}},
+		{name: "shared cache", cache: NewParseCache(), wantCalls: []string{"shared", "local", "cloud"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []string
+
+			parse := func(fset *token.FileSet, dirs []string) (*GoParser, error) {
+				calls = append(calls, strings.Join(dirs, ","))
+
+				files := make([]*ast.File, 0, len(dirs))
+				for _, dir := range dirs {
+					files = append(files, &ast.File{Name: ast.NewIdent(dir)})
+				}
+
+				return &GoParser{fset: fset, files: files}, nil
+			}
+
+			// Both deployments share the first directory, which a cache parses once
+			local, err := tt.cache.goParser([]string{"shared", "local"}, parse)
+			if err != nil {
+				t.Fatalf("goParser() error = %v", err)
+			}
+
+			cloud, err := tt.cache.goParser([]string{"shared", "cloud"}, parse)
+			if err != nil {
+				t.Fatalf("goParser() error = %v", err)
+			}
+
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("parse calls = %v, want %v", calls, tt.wantCalls)
+			}
+
+			for parser, want := range map[*GoParser][]string{local: {"shared", "local"}, cloud: {"shared", "cloud"}} {
+				var got []string
+				for _, file := range parser.files {
+					got = append(got, file.Name.Name)
+				}
+
+				if !slices.Equal(got, want) {
+					t.Errorf("parsed files = %v, want %v", got, want)
+				}
+			}
+
+			if tt.cache != nil && (local.fset != tt.cache.fset || cloud.fset != tt.cache.fset) {
+				t.Error("cached parsers do not share the cache file set")
+			}
+		})
+	}
+}
+
+func TestParseCacheDatabaseSchema(t *testing.T) {
+	t.Parallel()
+
+	cache := NewParseCache()
+	calls := 0
+
+	generate := func() (string, error) {
+		calls++
+
+		return "CREATE TABLE users ();", nil
+	}
+
+	for _, deployment := range []string{"local", "local", "cloud"} {
+		if _, err := cache.databaseSchema(deployment, "docs/"+deployment+"/schema.sql", generate); err != nil {
+			t.Fatalf("databaseSchema() error = %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("migrations ran %d times, want once per deployment (2)", calls)
+	}
+}
+
+// Types directories of the deployments, as configured by the local and cloud mains.
+//
+//nolint:gochecknoglobals // Benchmark fixture
+var benchmarkDeploymentDirs = [][]string{
+	{"../../internal/shared/types", "../../internal/local/api/types", "../../internal/local/mqtt/types"},
+	{"../../internal/shared/types", "../../internal/cloud/api/types"},
+}
+
+// BenchmarkParseCache parses the Go types of the local and cloud deployments, which share the shared types
+// directory, with and without a shared cache.
+func BenchmarkParseCache(b *testing.B) {
+	g := &OpenAPICollector{l: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	parseDeployments := func(b *testing.B, cache *ParseCache) {
+		b.Helper()
+
+		for _, dirs := range benchmarkDeploymentDirs {
+			if _, err := cache.goParser(dirs, g.parseGoTypesDirs); err != nil {
+				b.Fatalf("goParser() error = %v", err)
+			}
+		}
+	}
+
+	b.Run("without cache", func(b *testing.B) {
+		for b.Loop() {
+			parseDeployments(b, nil)
+		}
+	})
+
+	b.Run("with cache", func(b *testing.B) {
+		for b.Loop() {
+			parseDeployments(b, NewParseCache())
+		}
+	})
+}
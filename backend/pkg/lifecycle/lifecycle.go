@@ -0,0 +1,106 @@
+package lifecycle
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// CloseFunc releases a component, it should return once ctx is done.
+type CloseFunc func(ctx context.Context) error
+
+// closer is a registered component teardown.
+type closer struct {
+	name     string
+	priority int
+	timeout  time.Duration
+	fn       CloseFunc
+}
+
+// Manager tears down the registered components in order on shutdown.
+// It is safe for concurrent use.
+type Manager struct {
+	l *slog.Logger
+
+	mu      sync.Mutex
+	closers []closer
+}
+
+// New creates a lifecycle manager.
+func New(l *slog.Logger) *Manager {
+	return &Manager{
+		l: l.With(slog.String("component", "lifecycle")),
+	}
+}
+
+// Register adds a named component teardown. Components are closed by ascending priority,
+// components with the same priority in registration order. A positive timeout bounds the teardown,
+// when it expires the manager stops waiting and moves on to the next component.
+func (m *Manager) Register(name string, priority int, timeout time.Duration, fn CloseFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closers = append(m.closers, closer{name: name, priority: priority, timeout: timeout, fn: fn})
+}
+
+// Shutdown closes every registered component in order, logging each one.
+// A failing or timed out component does not stop the remaining ones, all errors are returned joined.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	closers := slices.Clone(m.closers)
+	m.mu.Unlock()
+
+	slices.SortStableFunc(closers, func(a, b closer) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+
+	var errs []error
+
+	for _, c := range closers {
+		m.l.Info("shutting down...", slog.String("name", c.name))
+
+		start := time.Now()
+
+		if err := c.close(ctx); err != nil {
+			m.l.Error("shutdown failed", slog.String("name", c.name), utils.ErrAttr(err))
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+
+			continue
+		}
+
+		m.l.Info("shut down", slog.String("name", c.name), slog.Duration("took", time.Since(start)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// close runs the teardown, giving up once its timeout expires or ctx is done.
+func (c closer) close(ctx context.Context) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// Run in its own goroutine so a teardown ignoring ctx cannot block the remaining ones
+	done := make(chan error, 1)
+
+	go func() {
+		done <- c.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("gave up waiting: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,91 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownOrder(t *testing.T) {
+	t.Parallel()
+
+	m := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	record := func(name string, err error) CloseFunc {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, name)
+
+			return err
+		}
+	}
+
+	errBroker := errors.New("broker still has clients")
+
+	m.Register("broker", 2, 0, record("broker", errBroker))
+	m.Register("http server", 0, time.Second, record("http server", nil))
+	m.Register("mqtt client", 1, 0, record("mqtt client", nil))
+	m.Register("database", 2, 0, record("database", nil))
+
+	err := m.Shutdown(t.Context())
+	if !errors.Is(err, errBroker) {
+		t.Errorf("Shutdown() error = %v, want %v", err, errBroker)
+	}
+
+	// Equal priorities keep their registration order, and the failing broker does not stop the database
+	want := []string{"http server", "mqtt client", "broker", "database"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("teardown order = %v, want %v", order, want)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	m := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	closed := false
+
+	// A teardown ignoring its context must not block the remaining ones
+	m.Register("stuck", 0, 50*time.Millisecond, func(context.Context) error {
+		<-release
+
+		return nil
+	})
+	m.Register("next", 1, 0, func(context.Context) error {
+		closed = true
+
+		return nil
+	})
+
+	start := time.Now()
+
+	err := m.Shutdown(t.Context())
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "stuck") {
+		t.Errorf("Shutdown() error = %v, want the stuck component deadline", err)
+	}
+
+	if !closed {
+		t.Error("component after the timed out one was not closed")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown() took %s, want about the 50ms timeout", elapsed)
+	}
+}
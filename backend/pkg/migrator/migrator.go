@@ -1,19 +1,60 @@
 package migrator
 
 import (
+	"cmp"
 	"embed"
 	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/amacneil/dbmate/v2/pkg/dbmate"
 )
 
+// migrationFileRegexp matches migration file names and captures their version, mirroring dbmate.
+//
+//nolint:gochecknoglobals // Compiled once
+var migrationFileRegexp = regexp.MustCompile(`^(\d+).*\.sql$`)
+
+// conventionalMigrationFileRegexp matches migration file names of the conventional <version>_<description>.sql form.
+//
+//nolint:gochecknoglobals // Compiled once
+var conventionalMigrationFileRegexp = regexp.MustCompile(`^\d+_.+\.sql$`)
+
 // Migrator defines the interface for database migrations and schema operations.
 type Migrator interface {
 	Migrate() error
+	// Rollback reverts the given number of most recently applied migrations and returns the resulting version.
+	Rollback(steps int) (string, error)
+	// MigrateTo applies or reverts migrations until the given version is the latest applied and returns it.
+	MigrateTo(version string) (string, error)
+	// Status lists all known migrations in order and whether each has been applied.
+	Status() ([]MigrationStatus, error)
 	DumpSchema(outputPath string) error
+	// DumpSchemaJSON writes the schema as structured JSON (tables, columns, foreign keys and indexes), see [ParseSchema].
+	DumpSchemaJSON(outputPath string) error
+}
+
+// MigrationStatus describes a single migration and whether it has been applied.
+type MigrationStatus struct {
+	Version  string // Version is the numeric prefix of the migration file (e.g., "20260206100000")
+	FileName string // FileName is the migration file name
+	Applied  bool   // Applied indicates whether the migration has been applied to the database
+}
+
+// migrationFile is an embedded migration file.
+type migrationFile struct {
+	version string
+	path    string
 }
 
 // New creates a PostgreSQL migrator.
 // Accepts one embed.FS and multiple migration directory paths.
+// The migration files are validated before anything runs, see [planMigrations].
 //
 //nolint:ireturn // Returns Migrator interface
 func New(l *slog.Logger, connString string, fs embed.FS, migrationDirs ...string) (Migrator, error) {
@@ -21,5 +62,153 @@ func New(l *slog.Logger, connString string, fs embed.FS, migrationDirs ...string
 		return nil, errors.New("at least one migration directory is required")
 	}
 
-	return newPostgresMigrator(l, connString, fs, migrationDirs...)
+	m, err := newPostgresMigrator(l, connString, fs, migrationDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planMigrations(m.l, fs, migrationDirs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid migrations: %w", err)
+	}
+
+	paths := make([]string, 0, len(plan))
+	for _, file := range plan {
+		paths = append(paths, file.path)
+	}
+
+	m.l.Info("migration plan", slog.Int("count", len(plan)), slog.Any("files", paths))
+
+	return m, nil
+}
+
+// planMigrations lists the migration files of all directories in the order dbmate applies them.
+// File names are checked with dbmate's own rule, it fails on .sql files without a version (which dbmate would
+// silently skip), on versions of different lengths (whose string order, used by dbmate, differs from their
+// numeric order) and on duplicate versions, as they make the apply order ambiguous.
+// Names without the conventional <version>_<description>.sql form are only logged.
+func planMigrations(l *slog.Logger, fsys fs.FS, migrationDirs []string) ([]migrationFile, error) {
+	var (
+		files []migrationFile
+		errs  []error
+	)
+
+	for _, dir := range migrationDirs {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration directory %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			// Other files (e.g., READMEs) are ignored by dbmate as well
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+
+			filePath := path.Join(dir, entry.Name())
+
+			matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+			if matches == nil {
+				errs = append(errs, fmt.Errorf("migration %s has no version, expected <version>_<description>.sql", filePath))
+
+				continue
+			}
+
+			if !conventionalMigrationFileRegexp.MatchString(entry.Name()) {
+				l.Warn("migration name does not follow <version>_<description>.sql", slog.String("file", filePath))
+			}
+
+			files = append(files, migrationFile{version: matches[1], path: filePath})
+		}
+	}
+
+	slices.SortStableFunc(files, func(a, b migrationFile) int {
+		return cmp.Compare(a.version, b.version)
+	})
+
+	for i, file := range files {
+		if len(file.version) != len(files[0].version) {
+			errs = append(errs, fmt.Errorf("migration %s has a %d digit version, expected %d digits like %s",
+				file.path, len(file.version), len(files[0].version), files[0].path))
+		}
+
+		if i > 0 && file.version == files[i-1].version {
+			errs = append(errs, fmt.Errorf("migration %s has the same version as %s", file.path, files[i-1].path))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return files, nil
+}
+
+// toMigrationStatuses converts dbmate migrations to [MigrationStatus] values.
+func toMigrationStatuses(migrations []dbmate.Migration) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+
+	for _, migration := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			Version:  migration.Version,
+			FileName: migration.FileName,
+			Applied:  migration.Applied,
+		})
+	}
+
+	return statuses
+}
+
+// latestAppliedVersion returns the version of the most recently applied migration,
+// or an empty string if none have been applied.
+func latestAppliedVersion(migrations []dbmate.Migration) string {
+	version := ""
+
+	for _, migration := range migrations {
+		if migration.Applied && migration.Version > version {
+			version = migration.Version
+		}
+	}
+
+	return version
+}
+
+// hasMigrationVersion reports whether a migration with the given version exists.
+func hasMigrationVersion(migrations []dbmate.Migration, version string) bool {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// versionFS wraps a migrations filesystem and hides migration files newer than maxVersion,
+// so that dbmate's Migrate only applies migrations up to and including that version.
+type versionFS struct {
+	fs.FS
+
+	maxVersion string
+}
+
+// ReadDir implements [fs.ReadDirFS], filtering out migration files newer than maxVersion.
+func (v versionFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(v.FS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]fs.DirEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if !entry.IsDir() && len(matches) == 2 && matches[1] > v.maxVersion {
+			continue
+		}
+
+		filtered = append(filtered, entry)
+	}
+
+	return filtered, nil
 }
@@ -0,0 +1,219 @@
+package migrator
+
+import (
+	"embed"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/amacneil/dbmate/v2/pkg/dbmate"
+)
+
+//go:embed testdata/duplicate
+var duplicateMigrationsFS embed.FS //nolint:gochecknoglobals // Embedded test migrations
+
+func TestNewRejectsDuplicateVersions(t *testing.T) {
+	t.Parallel()
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, err := New(l, "postgres://localhost:5432/app", duplicateMigrationsFS, "testdata/duplicate")
+	if err == nil {
+		t.Fatal("New() expected error for duplicate migration versions, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "20260101000000_users.sql has the same version as testdata/duplicate/20260101000000_devices.sql") {
+		t.Errorf("New() error = %v, want the duplicate migrations named", err)
+	}
+}
+
+func TestPlanMigrations(t *testing.T) {
+	t.Parallel()
+
+	base := fstest.MapFS{
+		"shared/20260101000000_users.sql":    {},
+		"shared/20260103000000_api_keys.sql": {},
+		"shared/README.md":                   {},
+		"local/20260102000000_devices.sql":   {},
+	}
+
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		dirs     []string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "ordered across directories",
+			dirs:     []string{"shared", "local"},
+			expected: []string{"shared/20260101000000_users.sql", "local/20260102000000_devices.sql", "shared/20260103000000_api_keys.sql"},
+		},
+		{
+			name:   "duplicate version across directories",
+			files:  fstest.MapFS{"local/20260101000000_devices.sql": {}},
+			dirs:   []string{"shared", "local"},
+			errMsg: "local/20260101000000_devices.sql has the same version as shared/20260101000000_users.sql",
+		},
+		{
+			name:     "names accepted by dbmate",
+			files:    fstest.MapFS{"local/20260104000000.sql": {}, "local/20260105000000-sensors.sql": {}},
+			dirs:     []string{"local"},
+			expected: []string{"local/20260102000000_devices.sql", "local/20260104000000.sql", "local/20260105000000-sensors.sql"},
+		},
+		{
+			name:   "missing version",
+			files:  fstest.MapFS{"local/devices.sql": {}},
+			dirs:   []string{"local"},
+			errMsg: "migration local/devices.sql has no version",
+		},
+		{
+			name:   "version of different length",
+			files:  fstest.MapFS{"local/2026010500_sensors.sql": {}},
+			dirs:   []string{"shared", "local"},
+			errMsg: "migration local/2026010500_sensors.sql has a 10 digit version, expected 14 digits",
+		},
+		{
+			name:   "missing directory",
+			dirs:   []string{"cloud"},
+			errMsg: "failed to read migration directory cloud",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := maps.Clone(base)
+			maps.Copy(fsys, tt.files)
+
+			plan, err := planMigrations(slog.New(slog.NewTextHandler(io.Discard, nil)), fsys, tt.dirs)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("planMigrations() error = %v, want containing %q", err, tt.errMsg)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("planMigrations() error = %v", err)
+			}
+
+			paths := make([]string, 0, len(plan))
+			for _, file := range plan {
+				paths = append(paths, file.path)
+			}
+
+			if !slices.Equal(paths, tt.expected) {
+				t.Errorf("planMigrations() = %v, want %v", paths, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVersionFS(t *testing.T) {
+	t.Parallel()
+
+	base := fstest.MapFS{
+		"migrations/20260101000000_users.sql":    {},
+		"migrations/20260102000000_devices.sql":  {},
+		"migrations/20260103000000_api_keys.sql": {},
+		"migrations/README.md":                   {},
+	}
+
+	tests := []struct {
+		name       string
+		maxVersion string
+		expected   []string
+	}{
+		{
+			name:       "first version",
+			maxVersion: "20260101000000",
+			expected:   []string{"20260101000000_users.sql", "README.md"},
+		},
+		{
+			name:       "middle version",
+			maxVersion: "20260102000000",
+			expected:   []string{"20260101000000_users.sql", "20260102000000_devices.sql", "README.md"},
+		},
+		{
+			name:       "latest version",
+			maxVersion: "20260103000000",
+			expected:   []string{"20260101000000_users.sql", "20260102000000_devices.sql", "20260103000000_api_keys.sql", "README.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := fs.ReadDir(versionFS{FS: base, maxVersion: tt.maxVersion}, "migrations")
+			if err != nil {
+				t.Fatalf("ReadDir() error = %v", err)
+			}
+
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("ReadDir() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLatestAppliedVersion(t *testing.T) {
+	t.Parallel()
+
+	migrations := []dbmate.Migration{
+		{Version: "20260101000000", Applied: true},
+		{Version: "20260102000000", Applied: true},
+		{Version: "20260103000000", Applied: false},
+	}
+
+	if got := latestAppliedVersion(migrations); got != "20260102000000" {
+		t.Errorf("latestAppliedVersion() = %q, want %q", got, "20260102000000")
+	}
+
+	if got := latestAppliedVersion(nil); got != "" {
+		t.Errorf("latestAppliedVersion(nil) = %q, want empty", got)
+	}
+
+	if !hasMigrationVersion(migrations, "20260103000000") {
+		t.Error("hasMigrationVersion() = false for existing version")
+	}
+
+	if hasMigrationVersion(migrations, "20260104000000") {
+		t.Error("hasMigrationVersion() = true for missing version")
+	}
+}
+
+func TestToMigrationStatuses(t *testing.T) {
+	t.Parallel()
+
+	// Simulates a partially applied set of migrations
+	migrations := []dbmate.Migration{
+		{Version: "20260101000000", FileName: "20260101000000_users.sql", Applied: true},
+		{Version: "20260102000000", FileName: "20260102000000_devices.sql", Applied: false},
+		{Version: "20260103000000", FileName: "20260103000000_api_keys.sql", Applied: false},
+	}
+
+	expected := []MigrationStatus{
+		{Version: "20260101000000", FileName: "20260101000000_users.sql", Applied: true},
+		{Version: "20260102000000", FileName: "20260102000000_devices.sql", Applied: false},
+		{Version: "20260103000000", FileName: "20260103000000_api_keys.sql", Applied: false},
+	}
+
+	if got := toMigrationStatuses(migrations); !slices.Equal(got, expected) {
+		t.Errorf("toMigrationStatuses() = %v, want %v", got, expected)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"os"
 
 	"http-mqtt-boilerplate/backend/pkg/utils"
 
@@ -28,10 +29,6 @@ func newPostgresMigrator(l *slog.Logger, connStr string, fs embed.FS, migrationD
 		return nil, errors.New("connection string is required")
 	}
 
-	if len(migrationDirs) == 0 {
-		return nil, errors.New("at least one migration directory is required")
-	}
-
 	// Verify all migration directories exist
 	for _, dir := range migrationDirs {
 		_, err := fs.ReadDir(dir)
@@ -74,6 +71,87 @@ func (m *postgresMigrator) Migrate() error {
 	return nil
 }
 
+// Rollback reverts the given number of most recently applied migrations on the PostgreSQL database.
+func (m *postgresMigrator) Rollback(steps int) (string, error) {
+	if steps < 1 {
+		return "", fmt.Errorf("rollback steps must be at least 1, got %d", steps)
+	}
+
+	m.l.Info("rolling back database", slog.Int("steps", steps))
+
+	for step := range steps {
+		if err := m.db.Rollback(); err != nil {
+			return "", fmt.Errorf("failed to roll back migration (step %d of %d): %w", step+1, steps, err)
+		}
+	}
+
+	return m.currentVersion()
+}
+
+// MigrateTo applies or reverts migrations on the PostgreSQL database until the given version is the latest applied.
+func (m *postgresMigrator) MigrateTo(version string) (string, error) {
+	migrations, err := m.db.FindMigrations()
+	if err != nil {
+		return "", fmt.Errorf("failed to find migrations: %w", err)
+	}
+
+	if !hasMigrationVersion(migrations, version) {
+		return "", fmt.Errorf("migration version %s not found", version)
+	}
+
+	current := latestAppliedVersion(migrations)
+
+	m.l.Info("migrating database to version", slog.String("from", current), slog.String("to", version))
+
+	switch {
+	case version > current:
+		// Apply pending migrations up to the target version only
+		db := *m.db
+		db.FS = versionFS{FS: m.fs, maxVersion: version}
+
+		if err := db.Migrate(); err != nil {
+			return "", fmt.Errorf("failed to migrate database to version %s: %w", version, err)
+		}
+	case version < current:
+		// Revert migrations until the target version is the latest applied
+		for current > version {
+			if err := m.db.Rollback(); err != nil {
+				return "", fmt.Errorf("failed to roll back migration %s: %w", current, err)
+			}
+
+			if current, err = m.currentVersion(); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return m.currentVersion()
+}
+
+// Status lists all migrations of the PostgreSQL database and whether each has been applied.
+func (m *postgresMigrator) Status() ([]MigrationStatus, error) {
+	migrations, err := m.db.FindMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find migrations: %w", err)
+	}
+
+	return toMigrationStatuses(migrations), nil
+}
+
+// currentVersion returns the version of the most recently applied migration.
+func (m *postgresMigrator) currentVersion() (string, error) {
+	migrations, err := m.db.FindMigrations()
+	if err != nil {
+		return "", fmt.Errorf("failed to find migrations: %w", err)
+	}
+
+	version := latestAppliedVersion(migrations)
+
+	m.l.Info("database at version", slog.String("version", version))
+
+	return version, nil
+}
+
 // DumpSchema dumps the PostgreSQL database schema to the specified file path.
 func (m *postgresMigrator) DumpSchema(filePath string) error {
 	m.db.SchemaFile = filePath
@@ -86,3 +164,35 @@ func (m *postgresMigrator) DumpSchema(filePath string) error {
 
 	return nil
 }
+
+// DumpSchemaJSON dumps the PostgreSQL database schema as structured JSON to the specified file path.
+func (m *postgresMigrator) DumpSchemaJSON(filePath string) error {
+	// dbmate only dumps to a file, so dump the SQL to a temporary one first
+	sqlFile, err := os.CreateTemp("", "schema-*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary schema file: %w", err)
+	}
+
+	defer func() {
+		if err := os.Remove(sqlFile.Name()); err != nil {
+			m.l.Warn("failed to remove temporary schema file", utils.ErrAttr(err))
+		}
+	}()
+
+	if err := sqlFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary schema file: %w", err)
+	}
+
+	if err := m.DumpSchema(sqlFile.Name()); err != nil {
+		return err
+	}
+
+	sql, err := os.ReadFile(sqlFile.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read schema dump: %w", err)
+	}
+
+	m.l.Info("writing structured schema", slog.String("file", filePath))
+
+	return writeSchemaJSON(string(sql), filePath)
+}
@@ -0,0 +1,138 @@
+package migrator
+
+import (
+	"context"
+	"embed"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/testcontainers/testcontainers-go"
+	postgrescontainer "github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+//go:embed testdata/migrations
+var testMigrationsFS embed.FS //nolint:gochecknoglobals // Embedded test migrations
+
+// newTestPostgres starts a PostgreSQL container for the test and returns its connection string.
+// The test is skipped when Docker is not available.
+func newTestPostgres(t *testing.T) string {
+	t.Helper()
+
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	container, err := postgrescontainer.Run(t.Context(),
+		"postgres:18-alpine",
+		postgrescontainer.WithDatabase("testdb"),
+		postgrescontainer.WithUsername("testuser"),
+		postgrescontainer.WithPassword("testpassword"),
+		postgrescontainer.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+
+	if err != nil {
+		t.Fatalf("failed to start PostgreSQL container: %v", err)
+	}
+
+	connStr, err := container.ConnectionString(t.Context(), "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get connection string: %v", err)
+	}
+
+	return connStr
+}
+
+// tableExists reports whether a table exists in the public schema.
+func tableExists(t *testing.T, connStr, table string) bool {
+	t.Helper()
+
+	conn, err := pgx.Connect(t.Context(), connStr)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	defer func() {
+		if err := conn.Close(context.WithoutCancel(t.Context())); err != nil {
+			t.Errorf("failed to close connection: %v", err)
+		}
+	}()
+
+	var exists bool
+	if err := conn.QueryRow(t.Context(), "SELECT to_regclass('public.' || $1) IS NOT NULL", table).Scan(&exists); err != nil {
+		t.Fatalf("failed to look up table %s: %v", table, err)
+	}
+
+	return exists
+}
+
+func TestPostgresRollbackAndStatus(t *testing.T) {
+	t.Parallel()
+
+	connStr := newTestPostgres(t)
+
+	mig, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), connStr, testMigrationsFS, "testdata/migrations")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := mig.Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	for _, table := range []string{"users", "devices", "api_keys"} {
+		if !tableExists(t, connStr, table) {
+			t.Fatalf("table %s missing after Migrate()", table)
+		}
+	}
+
+	version, err := mig.Rollback(1)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if version != "20260102000000" {
+		t.Errorf("Rollback() version = %s, want 20260102000000", version)
+	}
+
+	if tableExists(t, connStr, "api_keys") {
+		t.Error("table api_keys still exists after Rollback(1)")
+	}
+
+	if !tableExists(t, connStr, "devices") {
+		t.Error("table devices missing after Rollback(1), want only the latest migration reverted")
+	}
+
+	if version, err = mig.MigrateTo("20260101000000"); err != nil || version != "20260101000000" {
+		t.Fatalf("MigrateTo(20260101000000) = %s, %v, want 20260101000000", version, err)
+	}
+
+	if tableExists(t, connStr, "devices") {
+		t.Error("table devices still exists after MigrateTo(20260101000000)")
+	}
+
+	// Only the first migration is applied, the reverted ones are reported as pending
+	statuses, err := mig.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	expected := []MigrationStatus{
+		{Version: "20260101000000", FileName: "20260101000000_users.sql", Applied: true},
+		{Version: "20260102000000", FileName: "20260102000000_devices.sql", Applied: false},
+		{Version: "20260103000000", FileName: "20260103000000_api_keys.sql", Applied: false},
+	}
+
+	if !slices.Equal(statuses, expected) {
+		t.Errorf("Status() = %+v, want %+v", statuses, expected)
+	}
+
+	if version, err = mig.MigrateTo("20260103000000"); err != nil || version != "20260103000000" {
+		t.Fatalf("MigrateTo(20260103000000) = %s, %v, want 20260103000000", version, err)
+	}
+
+	if !tableExists(t, connStr, "api_keys") {
+		t.Error("table api_keys missing after MigrateTo(20260103000000)")
+	}
+}
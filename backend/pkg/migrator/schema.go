@@ -0,0 +1,326 @@
+package migrator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// Schema is the structured form of a database schema dump.
+type Schema struct {
+	Tables []Table `json:"tables"` // Tables sorted by name
+}
+
+// Table describes a table, its columns and its constraints.
+type Table struct {
+	Name        string             `json:"name"`                  // Schema-qualified name (e.g., public.device)
+	Columns     []Column           `json:"columns"`               // Columns in declaration order
+	PrimaryKey  []string           `json:"primaryKey,omitempty"`  // Primary key columns
+	Unique      []UniqueConstraint `json:"unique,omitempty"`      // Unique constraints sorted by name
+	ForeignKeys []ForeignKey       `json:"foreignKeys,omitempty"` // Foreign keys sorted by name
+	Indexes     []Index            `json:"indexes,omitempty"`     // Indexes sorted by name
+}
+
+// Column describes a table column.
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`              // SQL type as dumped (e.g., timestamp without time zone)
+	Nullable bool   `json:"nullable"`          // Whether the column accepts NULL
+	Default  string `json:"default,omitempty"` // Default expression as dumped
+}
+
+// UniqueConstraint describes a unique constraint.
+type UniqueConstraint struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// ForeignKey describes a foreign key constraint.
+type ForeignKey struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referencedTable"`
+	ReferencedColumns []string `json:"referencedColumns"`
+	OnDelete          string   `json:"onDelete,omitempty"` // Referential action (e.g., CASCADE), empty for the default NO ACTION
+	OnUpdate          string   `json:"onUpdate,omitempty"` // Referential action (e.g., CASCADE), empty for the default NO ACTION
+}
+
+// Index describes an index.
+type Index struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique"`
+	Method  string   `json:"method"`          // Access method (e.g., btree)
+	Columns []string `json:"columns"`         // Indexed columns or expressions
+	Where   string   `json:"where,omitempty"` // Predicate of a partial index
+}
+
+var (
+	//nolint:gochecknoglobals // Compiled once
+	createTableRegexp = regexp.MustCompile(`(?s)^CREATE TABLE (\S+) \((.*)\)$`)
+	//nolint:gochecknoglobals // Compiled once
+	alterTableRegexp = regexp.MustCompile(`(?s)^ALTER TABLE (?:ONLY )?(\S+)\s+(.*)$`)
+	//nolint:gochecknoglobals // Compiled once
+	setDefaultRegexp = regexp.MustCompile(`(?s)^ALTER COLUMN (\S+) SET DEFAULT (.*)$`)
+	//nolint:gochecknoglobals // Compiled once
+	primaryKeyRegexp = regexp.MustCompile(`(?s)^ADD CONSTRAINT (\S+) PRIMARY KEY \((.*)\)$`)
+	//nolint:gochecknoglobals // Compiled once
+	uniqueRegexp = regexp.MustCompile(`(?s)^ADD CONSTRAINT (\S+) UNIQUE \((.*)\)$`)
+	//nolint:gochecknoglobals // Compiled once
+	foreignKeyRegexp = regexp.MustCompile(`(?s)^ADD CONSTRAINT (\S+) FOREIGN KEY \((.*?)\) REFERENCES ([^\s(]+)\((.*?)\)(.*)$`)
+	//nolint:gochecknoglobals // Compiled once
+	referentialActionRegexp = regexp.MustCompile(`ON (DELETE|UPDATE) (CASCADE|RESTRICT|NO ACTION|SET NULL|SET DEFAULT)`)
+	//nolint:gochecknoglobals // Compiled once
+	createIndexRegexp = regexp.MustCompile(`(?s)^CREATE (UNIQUE )?INDEX (\S+) ON (?:ONLY )?(\S+) USING (\S+) \((.*?)\)(?: WHERE (.*))?$`)
+)
+
+// ParseSchema parses a PostgreSQL schema dump, as written by [Migrator.DumpSchema], into its structured form.
+// Tables, columns, primary keys, unique constraints, foreign keys, indexes and column defaults are extracted,
+// other statements (sequences, check constraints, functions, etc.) are ignored.
+func ParseSchema(sql string) (Schema, error) {
+	tables := make(map[string]*Table)
+
+	table := func(name string) (*Table, error) {
+		t, ok := tables[name]
+		if !ok {
+			return nil, fmt.Errorf("table %s is altered before it is created", name)
+		}
+
+		return t, nil
+	}
+
+	for _, stmt := range splitStatements(sql) {
+		switch {
+		case strings.HasPrefix(stmt, "CREATE TABLE "):
+			t, err := parseCreateTable(stmt)
+			if err != nil {
+				return Schema{}, err
+			}
+
+			tables[t.Name] = t
+
+		case strings.HasPrefix(stmt, "ALTER TABLE "):
+			matches := alterTableRegexp.FindStringSubmatch(stmt)
+			if matches == nil {
+				continue
+			}
+
+			t, err := table(unquoteIdentifier(matches[1]))
+			if err != nil {
+				return Schema{}, err
+			}
+
+			if err := applyAlterTable(t, matches[2]); err != nil {
+				return Schema{}, fmt.Errorf("table %s: %w", t.Name, err)
+			}
+
+		case strings.HasPrefix(stmt, "CREATE INDEX ") || strings.HasPrefix(stmt, "CREATE UNIQUE INDEX "):
+			matches := createIndexRegexp.FindStringSubmatch(stmt)
+			if matches == nil {
+				return Schema{}, fmt.Errorf("unsupported index statement: %s", stmt)
+			}
+
+			t, err := table(unquoteIdentifier(matches[3]))
+			if err != nil {
+				return Schema{}, err
+			}
+
+			t.Indexes = append(t.Indexes, Index{
+				Name:    unquoteIdentifier(matches[2]),
+				Unique:  matches[1] != "",
+				Method:  matches[4],
+				Columns: splitList(matches[5]),
+				Where:   matches[6],
+			})
+		}
+	}
+
+	schema := Schema{Tables: make([]Table, 0, len(tables))}
+
+	for _, name := range slices.Sorted(maps.Keys(tables)) {
+		t := tables[name]
+
+		slices.SortFunc(t.Unique, func(a, b UniqueConstraint) int { return cmp.Compare(a.Name, b.Name) })
+		slices.SortFunc(t.ForeignKeys, func(a, b ForeignKey) int { return cmp.Compare(a.Name, b.Name) })
+		slices.SortFunc(t.Indexes, func(a, b Index) int { return cmp.Compare(a.Name, b.Name) })
+
+		schema.Tables = append(schema.Tables, *t)
+	}
+
+	return schema, nil
+}
+
+// splitStatements splits a schema dump into statements, dropping comments and psql meta-commands.
+func splitStatements(sql string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+
+	for line := range strings.SplitSeq(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") || strings.HasPrefix(trimmed, `\`) {
+			continue
+		}
+
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+
+		current.WriteString(trimmed)
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(current.String(), ";"))
+			current.Reset()
+		}
+	}
+
+	return statements
+}
+
+// parseCreateTable parses a CREATE TABLE statement with one column or constraint per line.
+func parseCreateTable(stmt string) (*Table, error) {
+	matches := createTableRegexp.FindStringSubmatch(stmt)
+	if matches == nil {
+		return nil, fmt.Errorf("unsupported table statement: %s", stmt)
+	}
+
+	t := &Table{Name: unquoteIdentifier(matches[1]), Columns: []Column{}}
+
+	for line := range strings.SplitSeq(matches[2], "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if line == "" || strings.HasPrefix(line, "CONSTRAINT ") {
+			continue
+		}
+
+		name, definition, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("table %s: column %q has no type", t.Name, line)
+		}
+
+		column := Column{Name: unquoteIdentifier(name), Nullable: true}
+
+		if rest, found := strings.CutSuffix(definition, " NOT NULL"); found {
+			column.Nullable = false
+			definition = rest
+		}
+
+		column.Type, column.Default, _ = strings.Cut(definition, " DEFAULT ")
+		t.Columns = append(t.Columns, column)
+	}
+
+	return t, nil
+}
+
+// applyAlterTable applies the column default or constraint added by an ALTER TABLE action.
+func applyAlterTable(t *Table, action string) error {
+	if matches := setDefaultRegexp.FindStringSubmatch(action); matches != nil {
+		name := unquoteIdentifier(matches[1])
+
+		i := slices.IndexFunc(t.Columns, func(c Column) bool { return c.Name == name })
+		if i < 0 {
+			return fmt.Errorf("default set on unknown column %s", name)
+		}
+
+		t.Columns[i].Default = matches[2]
+
+		return nil
+	}
+
+	if matches := primaryKeyRegexp.FindStringSubmatch(action); matches != nil {
+		t.PrimaryKey = splitList(matches[2])
+
+		return nil
+	}
+
+	if matches := uniqueRegexp.FindStringSubmatch(action); matches != nil {
+		t.Unique = append(t.Unique, UniqueConstraint{Name: unquoteIdentifier(matches[1]), Columns: splitList(matches[2])})
+
+		return nil
+	}
+
+	if matches := foreignKeyRegexp.FindStringSubmatch(action); matches != nil {
+		fk := ForeignKey{
+			Name:              unquoteIdentifier(matches[1]),
+			Columns:           splitList(matches[2]),
+			ReferencedTable:   unquoteIdentifier(matches[3]),
+			ReferencedColumns: splitList(matches[4]),
+		}
+
+		for _, action := range referentialActionRegexp.FindAllStringSubmatch(matches[5], -1) {
+			if action[1] == "DELETE" {
+				fk.OnDelete = action[2]
+			} else {
+				fk.OnUpdate = action[2]
+			}
+		}
+
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+	}
+
+	// Other actions (check constraints, ownership, etc.) are not part of the structured schema
+	return nil
+}
+
+// splitList splits a comma-separated list of identifiers or expressions, ignoring commas inside parentheses.
+func splitList(list string) []string {
+	var (
+		items []string
+		depth int
+		start int
+	)
+
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, unquoteIdentifier(strings.TrimSpace(list[start:i])))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(items, unquoteIdentifier(strings.TrimSpace(list[start:])))
+}
+
+// unquoteIdentifier removes the double quotes around the parts of a possibly qualified identifier (e.g., public."user").
+func unquoteIdentifier(identifier string) string {
+	if !strings.Contains(identifier, `"`) {
+		return identifier
+	}
+
+	// Expressions are kept as is, only plain identifiers are unquoted
+	if strings.ContainsAny(identifier, " ()") {
+		return identifier
+	}
+
+	return strings.ReplaceAll(identifier, `"`, "")
+}
+
+// writeSchemaJSON parses a schema dump and writes its structured form as indented JSON.
+func writeSchemaJSON(sql string, outputPath string) error {
+	schema, err := ParseSchema(sql)
+	if err != nil {
+		return fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	data, err := utils.ToJSONIndent(schema)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write schema JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package migrator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// testSchemaDump is a trimmed pg_dump output, as written by DumpSchema.
+const testSchemaDump = `\restrict dbmate
+
+SET statement_timeout = 0;
+
+--
+-- Name: device; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.device (
+    id integer NOT NULL,
+    device_id text NOT NULL,
+    status text DEFAULT 'offline'::text NOT NULL,
+    last_seen timestamp without time zone,
+    CONSTRAINT device_status_check CHECK ((status <> ''::text))
+);
+
+CREATE SEQUENCE public.device_id_seq
+    AS integer
+    START WITH 1
+    CACHE 1;
+
+CREATE TABLE public."user" (
+    id integer NOT NULL,
+    email text NOT NULL,
+    device_id integer
+);
+
+ALTER TABLE ONLY public.device ALTER COLUMN id SET DEFAULT nextval('public.device_id_seq'::regclass);
+
+ALTER TABLE ONLY public.device
+    ADD CONSTRAINT device_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.device
+    ADD CONSTRAINT device_device_id_key UNIQUE (device_id);
+
+ALTER TABLE ONLY public."user"
+    ADD CONSTRAINT user_pkey PRIMARY KEY (id);
+
+CREATE INDEX device_status_idx ON public.device USING btree (status);
+
+CREATE UNIQUE INDEX user_email_idx ON public."user" USING btree (lower(email), id) WHERE (email IS NOT NULL);
+
+ALTER TABLE ONLY public."user"
+    ADD CONSTRAINT fk_user_device FOREIGN KEY (device_id) REFERENCES public.device(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+\unrestrict dbmate
+
+INSERT INTO public.schema_migrations (version) VALUES
+    ('20260206100000');
+`
+
+func TestParseSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := ParseSchema(testSchemaDump)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+
+	expected := Schema{Tables: []Table{
+		{
+			Name: "public.device",
+			Columns: []Column{
+				{Name: "id", Type: "integer", Default: "nextval('public.device_id_seq'::regclass)"},
+				{Name: "device_id", Type: "text"},
+				{Name: "status", Type: "text", Default: "'offline'::text"},
+				{Name: "last_seen", Type: "timestamp without time zone", Nullable: true},
+			},
+			PrimaryKey: []string{"id"},
+			Unique:     []UniqueConstraint{{Name: "device_device_id_key", Columns: []string{"device_id"}}},
+			Indexes:    []Index{{Name: "device_status_idx", Method: "btree", Columns: []string{"status"}}},
+		},
+		{
+			Name: "public.user",
+			Columns: []Column{
+				{Name: "id", Type: "integer"},
+				{Name: "email", Type: "text"},
+				{Name: "device_id", Type: "integer", Nullable: true},
+			},
+			PrimaryKey: []string{"id"},
+			ForeignKeys: []ForeignKey{{
+				Name:              "fk_user_device",
+				Columns:           []string{"device_id"},
+				ReferencedTable:   "public.device",
+				ReferencedColumns: []string{"id"},
+				OnDelete:          "SET NULL",
+				OnUpdate:          "CASCADE",
+			}},
+			Indexes: []Index{{Name: "user_email_idx", Unique: true, Method: "btree", Columns: []string{"lower(email)", "id"}, Where: "(email IS NOT NULL)"}},
+		},
+	}}
+
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("ParseSchema() =\n%+v\nwant\n%+v", schema, expected)
+	}
+}
+
+func TestWriteSchemaJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	write := func(name string) []byte {
+		path := filepath.Join(dir, name)
+		if err := writeSchemaJSON(testSchemaDump, path); err != nil {
+			t.Fatalf("writeSchemaJSON() error = %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read schema JSON: %v", err)
+		}
+
+		return data
+	}
+
+	first := write("first.json")
+	if !bytes.Equal(first, write("second.json")) {
+		t.Error("writeSchemaJSON() output is not deterministic")
+	}
+
+	// The JSON is readable without the Go types
+	doc, err := utils.FromJSON[map[string]any](first)
+	if err != nil {
+		t.Fatalf("schema JSON is invalid: %v", err)
+	}
+
+	tables, _ := doc["tables"].([]any)
+	if len(tables) != 2 {
+		t.Fatalf("tables = %v, want 2 tables", doc["tables"])
+	}
+
+	user, _ := tables[1].(map[string]any)
+	if user["name"] != "public.user" {
+		t.Errorf("tables[1].name = %v, want public.user", user["name"])
+	}
+
+	for _, key := range []string{"columns", "primaryKey", "foreignKeys", "indexes"} {
+		if _, ok := user[key]; !ok {
+			t.Errorf("tables[1] has no %q key: %v", key, user)
+		}
+	}
+
+	if _, ok := user["unique"]; ok {
+		t.Error("tables[1] has an empty unique key, want it omitted")
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{name: "altered before created", sql: "ALTER TABLE ONLY public.device\n    ADD CONSTRAINT device_pkey PRIMARY KEY (id);"},
+		{name: "index on unknown table", sql: "CREATE INDEX device_idx ON public.device USING btree (id);"},
+		{name: "default on unknown column", sql: "CREATE TABLE public.device (\n    id integer\n);\nALTER TABLE ONLY public.device ALTER COLUMN uuid SET DEFAULT gen_random_uuid();"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseSchema(tt.sql); err == nil {
+				t.Error("ParseSchema() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+-- migrate:up
+CREATE TABLE devices (id integer);
+
+-- migrate:down
+DROP TABLE devices;
@@ -0,0 +1,5 @@
+-- migrate:up
+CREATE TABLE devices (id integer PRIMARY KEY, owner_id integer REFERENCES users (id));
+
+-- migrate:down
+DROP TABLE devices;
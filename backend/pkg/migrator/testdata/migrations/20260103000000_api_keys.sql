@@ -0,0 +1,5 @@
+-- migrate:up
+CREATE TABLE api_keys (id integer PRIMARY KEY, user_id integer REFERENCES users (id));
+
+-- migrate:down
+DROP TABLE api_keys;
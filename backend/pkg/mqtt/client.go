@@ -1,12 +1,18 @@
 package mqtt
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"http-mqtt-boilerplate/backend/pkg/utils"
 	"log/slog"
+	"maps"
 	"net/url"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/eclipse/paho.golang/autopaho"
@@ -18,12 +24,18 @@ const (
 	sendTimeout       = 10 * time.Second
 	connectRetryDelay = 5 * time.Second
 	connectTimeout    = 5 * time.Second
+
+	reconnectBackoffFactor = 2
+	subscribeMaxAttempts   = 3
 )
 
 type MQTTClient struct {
 	connMgr *autopaho.ConnectionManager
 	builder *MQTTBuilder
 	l       *slog.Logger
+
+	subscriptionMu  sync.Mutex
+	subscriptionErr error // Joined per-topic errors of the last SubscribeAll call
 }
 
 func newWrappedMQTTClient(l *slog.Logger, connMgr *autopaho.ConnectionManager, builder *MQTTBuilder) *MQTTClient {
@@ -40,18 +52,42 @@ func (c *MQTTClient) IsConnected() bool {
 }
 
 // Publish sends a message to the specified topic using the publication spec identified by operationID.
-// It does not validate the topic or payload.
-// TODO: Make this easier to work with, passing operationID is a bit awkward and error prone.
-func (c *MQTTClient) Publish(ctx context.Context, operationID string, actualTopic string, payload any) error {
-	if c.connMgr == nil {
-		return errors.New("MQTT client not connected - call Connect first")
-	}
-
+// The QoS and retained flag can be overridden with [WithQoS] and [WithRetained], the topic is already resolved
+// so [WithTopicParams] is rejected. It does not validate the topic or payload, prefer [PublishJSON] which does.
+func (c *MQTTClient) Publish(ctx context.Context, operationID string, actualTopic string, payload any, opts ...PublishOption) error {
 	pub, ok := c.builder.publications[operationID]
 	if !ok {
 		return fmt.Errorf("publication not found for operationID %s", operationID)
 	}
 
+	options, err := newPublishOptions(pub, opts)
+	if err != nil {
+		return err
+	}
+
+	if options.topicParams != nil {
+		return fmt.Errorf("topic parameters are not supported for the resolved topic %s, use PublishJSON", actualTopic)
+	}
+
+	return c.publish(ctx, pub, actualTopic, payload, options)
+}
+
+// publisher is the subset of [autopaho.ConnectionManager] used to publish messages.
+type publisher interface {
+	Publish(ctx context.Context, p *paho.Publish) (*paho.PublishResponse, error)
+}
+
+// publish serializes the payload and sends it to actualTopic using the resolved QoS and retained flag.
+func (c *MQTTClient) publish(ctx context.Context, pub *PublicationSpec, actualTopic string, payload any, options publishOptions) error {
+	if c.connMgr == nil {
+		return errors.New("MQTT client not connected - call Connect first")
+	}
+
+	return c.publishWith(ctx, c.connMgr, pub, actualTopic, payload, options)
+}
+
+// publishWith is [MQTTClient.publish] with the publisher passed in, so tests can replace the connection manager.
+func (c *MQTTClient) publishWith(ctx context.Context, p publisher, pub *PublicationSpec, actualTopic string, payload any, options publishOptions) error {
 	bytes, err := utils.ToJSON(payload)
 	if err != nil {
 		return fmt.Errorf("failed to serialize payload: %w", err)
@@ -60,16 +96,16 @@ func (c *MQTTClient) Publish(ctx context.Context, operationID string, actualTopi
 	log := c.l.With(
 		slog.String("operationID", pub.OperationID),
 		slog.String("topic", actualTopic),
-		slog.Int("qos", int(pub.QoS)),
+		slog.Int("qos", int(options.qos)),
 	)
 
 	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
 	defer cancel()
 
-	_, err = c.connMgr.Publish(ctx, &paho.Publish{
+	_, err = p.Publish(ctx, &paho.Publish{
 		Topic:   actualTopic,
-		QoS:     byte(pub.QoS),
-		Retain:  pub.Retained,
+		QoS:     byte(options.qos),
+		Retain:  options.retained,
 		Payload: bytes,
 	})
 	if err != nil {
@@ -84,49 +120,128 @@ func (c *MQTTClient) Publish(ctx context.Context, operationID string, actualTopi
 		return err
 	}
 
+	c.builder.counters.published.Add(1)
+
 	return nil
 }
 
-// SubscribeAll subscribes to all registered subscriptions in a single call.
+// subscriber is the subset of [autopaho.ConnectionManager] used to subscribe to topics.
+type subscriber interface {
+	Subscribe(ctx context.Context, s *paho.Subscribe) (*paho.Suback, error)
+}
+
+// SubscribeAll subscribes to every registered subscription, one topic at a time.
+// Transient failures are retried with the configured reconnect backoff, up to [subscribeMaxAttempts] per topic.
+// Failures are joined into a single error that names each failing operationID,
+// the same error is also available from [MQTTClient.SubscriptionErrors] until the next call.
 func (c *MQTTClient) SubscribeAll(ctx context.Context) error {
 	if c.connMgr == nil {
 		return errors.New("MQTT client not connected - call Connect first")
 	}
 
+	return c.subscribeAll(ctx, c.connMgr)
+}
+
+// SubscriptionErrors returns the joined per-topic errors of the last [MQTTClient.SubscribeAll] call,
+// or nil if every subscription succeeded.
+func (c *MQTTClient) SubscriptionErrors() error {
+	c.subscriptionMu.Lock()
+	defer c.subscriptionMu.Unlock()
+
+	return c.subscriptionErr
+}
+
+func (c *MQTTClient) subscribeAll(ctx context.Context, s subscriber) error {
 	if len(c.builder.subscriptions) == 0 {
 		c.l.Info("no subscriptions to subscribe to")
+		c.setSubscriptionErrors(nil)
 
 		return nil
 	}
 
-	// Build subscription options for all registered subscriptions
-	subscriptions := make([]paho.SubscribeOptions, 0, len(c.builder.subscriptions))
-	for _, sub := range c.builder.subscriptions {
-		subscriptions = append(subscriptions, paho.SubscribeOptions{
-			Topic: sub.TopicMQTT,
-			QoS:   byte(sub.QoS),
-		})
+	// Subscribe in operationID order for deterministic behavior and error messages
+	operationIDs := slices.Sorted(maps.Keys(c.builder.subscriptions))
+
+	var errs []error
+
+	for _, operationID := range operationIDs {
+		sub := c.builder.subscriptions[operationID]
+
+		if err := c.subscribeWithRetry(ctx, s, sub); err != nil {
+			c.l.Error("failed to subscribe to topic", slog.String("operationID", operationID), slog.String("topic", sub.TopicMQTT), utils.ErrAttr(err))
+			errs = append(errs, fmt.Errorf("operationID %s (topic %s): %w", operationID, sub.TopicMQTT, err))
+
+			continue
+		}
+
+		c.l.Info("subscribed to topic", slog.String("operationID", operationID), slog.String("topic", sub.TopicMQTT), slog.Int("qos", int(sub.QoS)))
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
-	defer cancel()
+	err := errors.Join(errs...)
+	c.setSubscriptionErrors(err)
 
-	_, err := c.connMgr.Subscribe(ctx, &paho.Subscribe{Subscriptions: subscriptions})
 	if err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
-			return fmt.Errorf("subscribe all timeout after %s, might complete later if reconnecting", sendTimeout)
+		return fmt.Errorf("failed to subscribe to %d of %d topics: %w", len(errs), len(operationIDs), err)
+	}
+
+	c.l.Info("subscribed to all topics successfully", slog.Int("count", len(operationIDs)))
+
+	return nil
+}
+
+// subscribeWithRetry subscribes to a single topic, retrying transient failures.
+func (c *MQTTClient) subscribeWithRetry(ctx context.Context, s subscriber, sub *SubscriptionSpec) error {
+	backoff := c.builder.opts.reconnectBackoff()
+
+	var err error
+
+	for attempt := range subscribeMaxAttempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+			case <-time.After(backoff(attempt)):
+			}
 		}
 
-		return err
+		var suback *paho.Suback
+
+		suback, err = subscribeOnce(ctx, s, sub)
+		if err == nil || !isTransientSubscribeError(suback, err) {
+			return err
+		}
+
+		c.l.Warn("transient subscribe failure, retrying", slog.String("operationID", sub.OperationID), slog.Int("attempt", attempt+1), utils.ErrAttr(err))
 	}
 
-	for _, sub := range subscriptions {
-		c.l.Info("subscribed to topic", slog.String("topic", sub.Topic), slog.Int("qos", int(sub.QoS)))
+	return fmt.Errorf("giving up after %d attempts: %w", subscribeMaxAttempts, err)
+}
+
+// subscribeOnce sends a single subscribe request for the topic, bounded by [sendTimeout].
+func subscribeOnce(ctx context.Context, s subscriber, sub *SubscriptionSpec) (*paho.Suback, error) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	return s.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: sub.TopicMQTT, QoS: byte(sub.QoS)}},
+	})
+}
+
+// isTransientSubscribeError reports whether a subscribe failure may succeed on retry.
+// Rejections by the broker (a failure reason code in the SUBACK) and invalid arguments are permanent.
+func isTransientSubscribeError(suback *paho.Suback, err error) bool {
+	if suback != nil || errors.Is(err, paho.ErrInvalidArguments) {
+		return false
 	}
 
-	c.l.Info("subscribed to all topics successfully", slog.Int("count", len(subscriptions)))
+	return !errors.Is(err, context.Canceled)
+}
 
-	return nil
+func (c *MQTTClient) setSubscriptionErrors(err error) {
+	c.subscriptionMu.Lock()
+	defer c.subscriptionMu.Unlock()
+
+	c.subscriptionErr = err
 }
 
 // MQTTClientOptions contains configuration for creating an MQTT client.
@@ -135,6 +250,93 @@ type MQTTClientOptions struct {
 	ClientID  string
 	Username  string
 	Password  string
+
+	// TLS configuration, required when the broker URL uses a TLS scheme (e.g., ssl://, mqtts://).
+	// Either set TLSConfig directly or use the file based convenience options.
+	TLSConfig          *tls.Config // TLSConfig is a fully custom TLS configuration.
+	CAFile             string      // CAFile is the path to a PEM encoded CA bundle used to verify the broker.
+	ClientCertFile     string      // ClientCertFile is the path to a PEM encoded client certificate for mutual TLS.
+	ClientKeyFile      string      // ClientKeyFile is the path to the PEM encoded private key of the client certificate.
+	InsecureSkipVerify bool        // InsecureSkipVerify disables broker certificate verification (testing only).
+
+	// Last Will and Testament, published by the broker if the client disconnects ungracefully.
+	// Disabled when WillTopic is empty.
+	WillTopic   string // WillTopic is the concrete topic the will message is published to (no {param} placeholders).
+	WillPayload []byte // WillPayload is the will message payload.
+	WillQoS     QoS    // WillQoS is the quality of service level of the will message.
+	WillRetain  bool   // WillRetain indicates whether the broker should retain the will message.
+
+	// Reconnection tuning, built-in defaults are used for unset (zero) values.
+	// When both backoff bounds are set and differ, the delay grows exponentially from min to max.
+	ReconnectBackoffMin time.Duration // ReconnectBackoffMin is the delay before the first reconnection attempt.
+	ReconnectBackoffMax time.Duration // ReconnectBackoffMax is the upper bound of the reconnection delay.
+	ConnectTimeout      time.Duration // ConnectTimeout bounds each connection attempt and the initial wait in [MQTTBuilder.Connect].
+
+	// DrainTimeout bounds how long [MQTTBuilder.DisconnectWithDefaultTimeout] waits for running subscription handlers
+	// to complete, defaults to 10 seconds when unset.
+	DrainTimeout time.Duration
+
+	// ValidatePayloads makes [PublishJSON] validate payloads against the generated schema of the publication's
+	// message type before sending. Requires a collector implementing [generate.MQTTPayloadValidator].
+	ValidatePayloads bool
+}
+
+// validateReconnect validates the reconnection options.
+func (o *MQTTClientOptions) validateReconnect() error {
+	if o.ReconnectBackoffMin < 0 || o.ReconnectBackoffMax < 0 {
+		return errors.New("reconnect backoff must not be negative")
+	}
+
+	if o.ReconnectBackoffMax > 0 && o.ReconnectBackoffMin == 0 {
+		return errors.New("reconnect backoff max requires reconnect backoff min")
+	}
+
+	if o.ReconnectBackoffMax > 0 && o.ReconnectBackoffMax < o.ReconnectBackoffMin {
+		return fmt.Errorf("reconnect backoff max (%s) must not be less than min (%s)", o.ReconnectBackoffMax, o.ReconnectBackoffMin)
+	}
+
+	if o.ConnectTimeout < 0 {
+		return errors.New("connect timeout must not be negative")
+	}
+
+	return nil
+}
+
+// reconnectBackoff returns the autopaho backoff for the configured bounds.
+func (o *MQTTClientOptions) reconnectBackoff() autopaho.Backoff {
+	switch {
+	case o.ReconnectBackoffMin == 0:
+		return autopaho.NewConstantBackoff(connectRetryDelay)
+	case o.ReconnectBackoffMax <= o.ReconnectBackoffMin:
+		return autopaho.NewConstantBackoff(o.ReconnectBackoffMin)
+	default:
+		return autopaho.NewExponentialBackoff(o.ReconnectBackoffMin, o.ReconnectBackoffMax, o.ReconnectBackoffMin, reconnectBackoffFactor)
+	}
+}
+
+// validateWill validates the Last Will and Testament options.
+func (o *MQTTClientOptions) validateWill() error {
+	if o.WillTopic == "" {
+		if len(o.WillPayload) > 0 {
+			return errors.New("will topic is required when will payload is set")
+		}
+
+		return nil
+	}
+
+	if err := validateTopicPattern(o.WillTopic); err != nil {
+		return fmt.Errorf("invalid will topic: %w", err)
+	}
+
+	if strings.Contains(o.WillTopic, "{") {
+		return errors.New("invalid will topic: parameters are not allowed, use a concrete topic")
+	}
+
+	if err := validateQoS(o.WillQoS); err != nil {
+		return fmt.Errorf("invalid will qos: %w", err)
+	}
+
+	return nil
 }
 
 // newAutopahoConnection creates a new autopaho connection manager using the provided options.
@@ -154,13 +356,14 @@ func newAutopahoConnection(ctx context.Context, l *slog.Logger, opts *MQTTClient
 
 	// Create client config
 	clientConfig := autopaho.ClientConfig{
-		ServerUrls:        []*url.URL{brokerURL},
-		KeepAlive:         keepAlive,
-		ConnectRetryDelay: connectRetryDelay,
-		ConnectTimeout:    connectTimeout,
-		OnConnectionUp:    mb.onConnect(ctx),
-		OnConnectionDown:  mb.onConnectionDown,
-		OnConnectError:    mb.onConnectionError,
+		ServerUrls:       []*url.URL{brokerURL},
+		KeepAlive:        keepAlive,
+		ReconnectBackoff: opts.reconnectBackoff(),
+		ConnectTimeout:   cmp.Or(opts.ConnectTimeout, connectTimeout),
+		TlsCfg:           opts.TLSConfig,
+		OnConnectionUp:   mb.onConnect(ctx),
+		OnConnectionDown: mb.onConnectionDown,
+		OnConnectError:   mb.onConnectionError,
 		ClientConfig: paho.ClientConfig{
 			ClientID:      opts.ClientID,
 			Router:        mb.router,
@@ -174,13 +377,15 @@ func newAutopahoConnection(ctx context.Context, l *slog.Logger, opts *MQTTClient
 		clientConfig.ConnectPassword = []byte(opts.Password)
 	}
 
-	// FIXME: Set will message
-	// clientConfig.WillMessage = &paho.WillMessage{
-	// 	Topic:   "",
-	// 	Payload: []byte(""),
-	// 	QoS:     2,
-	// 	Retain:  true,
-	// }
+	// Set will message if provided
+	if opts.WillTopic != "" {
+		clientConfig.WillMessage = &paho.WillMessage{
+			Topic:   opts.WillTopic,
+			Payload: opts.WillPayload,
+			QoS:     byte(opts.WillQoS),
+			Retain:  opts.WillRetain,
+		}
+	}
 
 	// Create connection manager
 	cm, err := autopaho.NewConnection(ctx, clientConfig)
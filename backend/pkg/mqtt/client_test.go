@@ -0,0 +1,113 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"http-mqtt-boilerplate/backend/pkg/generate"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// fakeSubscriber fails subscriptions based on the topic and counts the attempts per topic.
+type fakeSubscriber struct {
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (f *fakeSubscriber) Subscribe(_ context.Context, s *paho.Subscribe) (*paho.Suback, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	topic := s.Subscriptions[0].Topic
+	f.attempts[topic]++
+
+	switch {
+	case strings.HasPrefix(topic, "rejected/"):
+		return &paho.Suback{Reasons: []byte{0x87}}, errors.New("failed to subscribe to topic: not authorized")
+	case strings.HasPrefix(topic, "down/"):
+		return nil, autopaho.ConnectionDownError
+	case strings.HasPrefix(topic, "flaky/") && f.attempts[topic] == 1:
+		return nil, paho.ErrConnectionLost
+	default:
+		return &paho.Suback{Reasons: []byte{0x00}}, nil
+	}
+}
+
+func TestSubscribeAllAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	mb, err := NewMQTTBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{}, MQTTClientOptions{
+		BrokerURL:           "mqtt://localhost:1883",
+		ClientID:            "test",
+		ReconnectBackoffMin: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewMQTTBuilder() error = %v", err)
+	}
+
+	noop := func(context.Context, map[string]string, testTemperature) error { return nil }
+
+	for operationID, topic := range map[string]string{
+		"subscribeValid":    "valid/temperature",
+		"subscribeFlaky":    "flaky/temperature",
+		"subscribeRejected": "rejected/temperature",
+		"subscribeDown":     "down/temperature",
+	} {
+		mb.MustRegisterSubscribe(topic, SubscriptionSpec{
+			OperationID:  operationID,
+			Summary:      "Subscribe to temperature",
+			Description:  "Receives temperature readings",
+			Group:        "Telemetry",
+			MessageType:  testTemperature{},
+			TypedHandler: TypedHandler(noop),
+		})
+	}
+
+	sub := &fakeSubscriber{attempts: make(map[string]int)}
+
+	err = mb.Client().subscribeAll(context.Background(), sub)
+	if err == nil {
+		t.Fatal("subscribeAll() expected error, got nil")
+	}
+
+	for _, operationID := range []string{"subscribeRejected", "subscribeDown"} {
+		if !strings.Contains(err.Error(), operationID) {
+			t.Errorf("subscribeAll() error = %v, want it to name %s", err, operationID)
+		}
+	}
+
+	for _, operationID := range []string{"subscribeValid", "subscribeFlaky"} {
+		if strings.Contains(err.Error(), operationID) {
+			t.Errorf("subscribeAll() error = %v, want it not to name %s", err, operationID)
+		}
+	}
+
+	if !errors.Is(err, autopaho.ConnectionDownError) {
+		t.Errorf("subscribeAll() error = %v, want it to wrap the per-topic errors", err)
+	}
+
+	if got := mb.Client().SubscriptionErrors(); got == nil || !strings.Contains(err.Error(), got.Error()) {
+		t.Errorf("SubscriptionErrors() = %v, want the joined per-topic errors", got)
+	}
+
+	expectedAttempts := map[string]int{
+		"valid/temperature":    1,
+		"flaky/temperature":    2,
+		"rejected/temperature": 1,
+		"down/temperature":     subscribeMaxAttempts,
+	}
+
+	for topic, want := range expectedAttempts {
+		if got := sub.attempts[topic]; got != want {
+			t.Errorf("attempts for %s = %d, want %d", topic, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package mqtt
+
+import "sync"
+
+// connectionEvents delivers connection state changes to the user callbacks.
+// Deliveries run one at a time, in the order of the state changes, in a goroutine separate from the connection manager:
+// a slow callback delays the following ones but never the connection manager.
+type connectionEvents struct {
+	mu         sync.Mutex
+	onUp       []func()      // User callbacks, see [MQTTBuilder.OnConnect]
+	onDown     []func(error) // User callbacks, see [MQTTBuilder.OnConnectionLost]
+	pending    []func()      // Queued callback invocations
+	delivering bool          // Whether the delivery goroutine is running
+}
+
+// addOnUp registers a callback invoked on every connection.
+func (e *connectionEvents) addOnUp(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.onUp = append(e.onUp, fn)
+}
+
+// addOnDown registers a callback invoked every time the connection is lost.
+func (e *connectionEvents) addOnDown(fn func(error)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.onDown = append(e.onDown, fn)
+}
+
+// up queues the connection callbacks.
+func (e *connectionEvents) up() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, fn := range e.onUp {
+		e.enqueue(fn)
+	}
+}
+
+// down queues the connection lost callbacks with err.
+func (e *connectionEvents) down(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, fn := range e.onDown {
+		e.enqueue(func() { fn(err) })
+	}
+}
+
+// enqueue queues a callback invocation and starts the delivery goroutine if it is not running, e.mu must be held.
+func (e *connectionEvents) enqueue(fn func()) {
+	e.pending = append(e.pending, fn)
+
+	if !e.delivering {
+		e.delivering = true
+
+		go e.deliver()
+	}
+}
+
+// deliver runs the queued callback invocations in order until the queue is empty.
+func (e *connectionEvents) deliver() {
+	for {
+		e.mu.Lock()
+
+		if len(e.pending) == 0 {
+			e.delivering = false
+			e.mu.Unlock()
+
+			return
+		}
+
+		fn := e.pending[0]
+		e.pending = e.pending[1:]
+
+		e.mu.Unlock()
+
+		fn()
+	}
+}
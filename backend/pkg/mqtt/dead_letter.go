@@ -0,0 +1,91 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// User properties added to dead-lettered messages, the payload is republished unchanged.
+const (
+	deadLetterPropertyError       = "error"       // The error returned by the handler
+	deadLetterPropertyOperationID = "operationID" // The operationID of the failing subscription
+	deadLetterPropertyTopic       = "topic"       // The topic the message was originally received on
+)
+
+// validateDeadLetterTopic validates the dead-letter topic of a subscription.
+func validateDeadLetterTopic(spec SubscriptionSpec) error {
+	if err := validateTopicPattern(spec.DeadLetterTopic); err != nil {
+		return err
+	}
+
+	if strings.Contains(spec.DeadLetterTopic, "{") {
+		return errors.New("parameters are not allowed, use a concrete topic")
+	}
+
+	if spec.TypedHandler == nil {
+		return errors.New("dead-letter topic requires a typedHandler, plain handlers cannot report errors")
+	}
+
+	return nil
+}
+
+// deadLetterFunc returns the handler error func of a subscription with a dead-letter topic.
+// The message is republished in a goroutine tracked with the running handlers, as paho handlers must not call
+// back into the client: a QoS 1 or 2 publish would block the receive loop, and every later message, until it times out.
+func (mb *MQTTBuilder) deadLetterFunc(sub *SubscriptionSpec, deadLetter func(context.Context, *SubscriptionSpec, *paho.Publish, error)) handlerErrorFunc {
+	return func(msg *paho.Publish, err error) {
+		mb.handlers.goTracked(func() {
+			deadLetter(context.Background(), sub, msg, err)
+		})
+	}
+}
+
+// deadLetter republishes a message whose handler failed to the dead-letter topic of the subscription.
+// Failures are logged, the message is lost if it cannot be dead-lettered.
+func (c *MQTTClient) deadLetter(ctx context.Context, sub *SubscriptionSpec, msg *paho.Publish, handlerErr error) {
+	if c.connMgr == nil {
+		c.l.Error("cannot dead-letter message, MQTT client not connected", slog.String("operationID", sub.OperationID), slog.String("topic", msg.Topic))
+
+		return
+	}
+
+	c.deadLetterWith(ctx, c.connMgr, sub, msg, handlerErr)
+}
+
+// deadLetterWith is [MQTTClient.deadLetter] with the publisher passed in, so tests can replace the connection manager.
+func (c *MQTTClient) deadLetterWith(ctx context.Context, p publisher, sub *SubscriptionSpec, msg *paho.Publish, handlerErr error) {
+	log := c.l.With(
+		slog.String("operationID", sub.OperationID),
+		slog.String("topic", msg.Topic),
+		slog.String("deadLetterTopic", sub.DeadLetterTopic),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	_, err := p.Publish(ctx, &paho.Publish{
+		Topic:   sub.DeadLetterTopic,
+		QoS:     byte(sub.QoS),
+		Payload: msg.Payload,
+		Properties: &paho.PublishProperties{
+			User: paho.UserProperties{
+				{Key: deadLetterPropertyError, Value: handlerErr.Error()},
+				{Key: deadLetterPropertyOperationID, Value: sub.OperationID},
+				{Key: deadLetterPropertyTopic, Value: msg.Topic},
+			},
+		},
+	})
+	if err != nil {
+		log.Error("failed to dead-letter message", utils.ErrAttr(err))
+
+		return
+	}
+
+	log.Warn("dead-lettered message after handler failure")
+}
@@ -0,0 +1,186 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+func TestDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	mb := newTestBuilder(t)
+	mb.MustRegisterSubscribe("devices/{deviceID}/temperature", SubscriptionSpec{
+		OperationID:     "subscribeTemperature",
+		Summary:         "Subscribe to temperature",
+		Description:     "Receives temperature readings",
+		Group:           "Telemetry",
+		TopicParameters: []TopicParameter{{Name: "deviceID", Description: "Device ID", Type: new(string)}},
+		MessageType:     testTemperature{},
+		QoS:             QoSAtLeastOnce,
+		DeadLetterTopic: "deadletter/temperature",
+		TypedHandler: TypedHandler(func(context.Context, map[string]string, testTemperature) error {
+			return errors.New("sensor offline")
+		}),
+	})
+
+	sub := mb.subscriptions["subscribeTemperature"]
+	fake := &fakePublisher{}
+
+	// Fake the connection manager so the dead-lettered message can be inspected
+	h := sub.TypedHandler.pahoHandler(mb.l, &mb.counters, sub.OperationID, "devices/{deviceID}/temperature", func(msg *paho.Publish, err error) {
+		mb.Client().deadLetterWith(t.Context(), fake, sub, msg, err)
+	})
+
+	h(&paho.Publish{Topic: "devices/device-001/temperature", Payload: []byte(`{"value":21}`)})
+
+	if len(fake.published) != 1 {
+		t.Fatalf("dead-lettered %d messages, want 1", len(fake.published))
+	}
+
+	got := fake.published[0]
+
+	if got.Topic != "deadletter/temperature" {
+		t.Errorf("topic = %q, want %q", got.Topic, "deadletter/temperature")
+	}
+
+	if string(got.Payload) != `{"value":21}` {
+		t.Errorf("payload = %s, want the original payload", got.Payload)
+	}
+
+	if got.QoS != byte(QoSAtLeastOnce) {
+		t.Errorf("qos = %d, want %d", got.QoS, QoSAtLeastOnce)
+	}
+
+	for key, want := range map[string]string{
+		deadLetterPropertyError:       "sensor offline",
+		deadLetterPropertyOperationID: "subscribeTemperature",
+		deadLetterPropertyTopic:       "devices/device-001/temperature",
+	} {
+		if value := got.Properties.User.Get(key); value != want {
+			t.Errorf("user property %s = %q, want %q", key, value, want)
+		}
+	}
+}
+
+// blockingPublisher blocks every publish until released, like a QoS 1 publish waiting for its acknowledgement.
+type blockingPublisher struct {
+	fakePublisher
+
+	release chan struct{}
+}
+
+func (b *blockingPublisher) Publish(ctx context.Context, p *paho.Publish) (*paho.PublishResponse, error) {
+	<-b.release
+
+	return b.fakePublisher.Publish(ctx, p)
+}
+
+func TestDeadLetterDoesNotBlockHandler(t *testing.T) {
+	t.Parallel()
+
+	mb := newTestBuilder(t)
+	mb.MustRegisterSubscribe("devices/temperature", SubscriptionSpec{
+		OperationID:     "subscribeTemperature",
+		Summary:         "Subscribe to temperature",
+		Description:     "Receives temperature readings",
+		Group:           "Telemetry",
+		MessageType:     testTemperature{},
+		QoS:             QoSAtLeastOnce,
+		DeadLetterTopic: "deadletter/temperature",
+		TypedHandler: TypedHandler(func(context.Context, map[string]string, testTemperature) error {
+			return errors.New("sensor offline")
+		}),
+	})
+
+	sub := mb.subscriptions["subscribeTemperature"]
+	blocking := &blockingPublisher{release: make(chan struct{})}
+
+	onError := mb.deadLetterFunc(sub, func(ctx context.Context, sub *SubscriptionSpec, msg *paho.Publish, err error) {
+		mb.Client().deadLetterWith(ctx, blocking, sub, msg, err)
+	})
+	h := sub.TypedHandler.pahoHandler(mb.l, &mb.counters, sub.OperationID, "devices/temperature", onError)
+
+	returned := make(chan struct{})
+
+	go func() {
+		if !mb.handlers.start() {
+			t.Error("handler refused before draining")
+		}
+		defer mb.handlers.done()
+
+		h(&paho.Publish{Topic: "devices/temperature", Payload: []byte(`{"value":21}`)})
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return while the dead-letter publish was blocked")
+	}
+
+	// Draining waits for the pending dead-letter publish
+	if mb.handlers.drain(50 * time.Millisecond) {
+		t.Error("drain() completed before the dead-letter publish")
+	}
+
+	close(blocking.release)
+
+	if !mb.handlers.drain(time.Second) {
+		t.Fatal("drain() timed out after the dead-letter publish was released")
+	}
+
+	if len(blocking.published) != 1 || blocking.published[0].Topic != "deadletter/temperature" {
+		t.Errorf("published = %v, want one message on deadletter/temperature", blocking.published)
+	}
+}
+
+func TestDeadLetterTopicValidation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(context.Context, map[string]string, testTemperature) error { return nil }
+
+	tests := []struct {
+		name     string
+		spec     SubscriptionSpec
+		errorMsg string
+	}{
+		{
+			name:     "invalid topic",
+			spec:     SubscriptionSpec{DeadLetterTopic: "deadletter/#", TypedHandler: TypedHandler(noop)},
+			errorMsg: "multi-level wildcard",
+		},
+		{
+			name:     "parameterized topic",
+			spec:     SubscriptionSpec{DeadLetterTopic: "deadletter/{deviceID}", TypedHandler: TypedHandler(noop)},
+			errorMsg: "parameters are not allowed",
+		},
+		{
+			name:     "plain handler",
+			spec:     SubscriptionSpec{DeadLetterTopic: "deadletter/temperature", Handler: func(*paho.Publish) {}},
+			errorMsg: "requires a typedHandler",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := tt.spec
+			spec.OperationID = "subscribeTemperature"
+			spec.Summary = "Subscribe to temperature"
+			spec.Description = "Receives temperature readings"
+			spec.Group = "Telemetry"
+			spec.MessageType = testTemperature{}
+
+			err := newTestBuilder(t).RegisterSubscribe("devices/temperature", spec)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("RegisterSubscribe() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package mqtt
+
+import (
+	"sync"
+	"time"
+)
+
+// handlerTracker tracks the running subscription handlers so disconnecting can wait for them.
+// Once draining starts no new handler may start.
+type handlerTracker struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+}
+
+// start registers a running handler, it returns false when the tracker is draining and the message must be refused.
+// Every successful start must be followed by a call to done.
+func (t *handlerTracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false
+	}
+
+	t.wg.Add(1)
+
+	return true
+}
+
+// done marks a running handler as completed.
+func (t *handlerTracker) done() {
+	t.wg.Done()
+}
+
+// goTracked runs fn in a goroutine that drain waits for like a running handler.
+// It must be called from a running handler, so the work is tracked even when draining has already started.
+func (t *handlerTracker) goTracked(fn func()) {
+	t.wg.Add(1)
+
+	go func() {
+		defer t.wg.Done()
+
+		fn()
+	}()
+}
+
+// drain refuses new handlers and waits for the running ones to complete.
+// It returns false if they did not complete within the timeout.
+func (t *handlerTracker) drain(timeout time.Duration) bool {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	completed := make(chan struct{})
+
+	go func() {
+		t.wg.Wait()
+		close(completed)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-completed:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package mqtt
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"http-mqtt-boilerplate/backend/pkg/generate"
+
+	"github.com/eclipse/paho.golang/packets"
+)
+
+func TestDisconnectDrainsHandlers(t *testing.T) {
+	t.Parallel()
+
+	const handlerDuration = 200 * time.Millisecond
+
+	mb, err := NewMQTTBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{}, MQTTClientOptions{
+		BrokerURL:    "mqtt://localhost:1883",
+		ClientID:     "test",
+		DrainTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewMQTTBuilder() error = %v", err)
+	}
+
+	started := make(chan struct{}, 1)
+
+	var completed atomic.Int32
+
+	mb.MustRegisterSubscribe("devices/temperature", SubscriptionSpec{
+		OperationID: "subscribeTemperature",
+		Summary:     "Subscribe to temperature",
+		Description: "Receives temperature readings",
+		Group:       "Telemetry",
+		MessageType: testTemperature{},
+		TypedHandler: TypedHandler(func(context.Context, map[string]string, testTemperature) error {
+			started <- struct{}{}
+
+			time.Sleep(handlerDuration)
+			completed.Add(1)
+
+			return nil
+		}),
+	})
+
+	route := func() {
+		mb.router.Route(&packets.Publish{Topic: "devices/temperature", Payload: []byte(`{"value":21}`), Properties: &packets.Properties{}})
+	}
+
+	go route()
+	<-started
+
+	mb.DisconnectWithDefaultTimeout()
+
+	if got := completed.Load(); got != 1 {
+		t.Fatalf("disconnect returned with %d completed handlers, want 1", got)
+	}
+
+	// Messages received after disconnecting are refused
+	route()
+
+	if got := completed.Load(); got != 1 {
+		t.Errorf("handler completed %d times after disconnect, want 1", got)
+	}
+}
+
+func TestHandlerTrackerDrainTimeout(t *testing.T) {
+	t.Parallel()
+
+	var tracker handlerTracker
+
+	if !tracker.start() {
+		t.Fatal("start() = false before draining, want true")
+	}
+
+	if tracker.drain(50 * time.Millisecond) {
+		t.Error("drain() = true with a running handler, want false after the timeout")
+	}
+
+	if tracker.start() {
+		t.Error("start() = true while draining, want false")
+	}
+
+	tracker.done()
+
+	if !tracker.drain(time.Second) {
+		t.Error("drain() = false after the handler completed, want true")
+	}
+}
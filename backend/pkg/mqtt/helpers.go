@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"http-mqtt-boilerplate/backend/pkg/generate"
+	"reflect"
 	"strings"
 )
 
@@ -54,6 +55,46 @@ func validateTopicPattern(topic string) error {
 	return nil
 }
 
+// validateWildcardTopic validates a raw MQTT topic filter used by [MQTTBuilder.RegisterWildcardSubscribe].
+// Valid filters:
+// - Single-level wildcards '+' must occupy a whole segment (e.g., devices/+/temperature)
+// - A multi-level wildcard '#' must occupy the whole final segment (e.g., devices/#)
+// - Parameters {param} are not supported, wildcard values are not captured.
+func validateWildcardTopic(topic string) error {
+	if topic == "" {
+		return errors.New("topic cannot be empty")
+	}
+
+	if strings.HasPrefix(topic, "/") {
+		return errors.New("leading slash is not allowed")
+	}
+
+	if strings.HasSuffix(topic, "/") {
+		return errors.New("trailing slash is not allowed")
+	}
+
+	segments := strings.Split(topic, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			return errors.New("empty segments are not allowed")
+		}
+
+		if strings.Contains(segment, "#") && (segment != "#" || i != len(segments)-1) {
+			return errors.New("multi-level wildcard '#' is only allowed as the final segment")
+		}
+
+		if strings.Contains(segment, "+") && segment != "+" {
+			return errors.New("wildcard '+' must occupy an entire segment")
+		}
+
+		if strings.ContainsAny(segment, "{}") {
+			return errors.New("parameters {param} are not supported in wildcard topics - use RegisterSubscribe instead")
+		}
+	}
+
+	return nil
+}
+
 // convertTopicToMQTT converts a parameterized topic (devices/{deviceID}/temperature)
 // to an MQTT wildcard pattern (devices/+/temperature).
 func convertTopicToMQTT(topic string) string {
@@ -76,10 +117,13 @@ func validateQoS(qos QoS) error {
 	return nil
 }
 
+// generateParameters validates the declared topic parameters against the {param} placeholders of the topic and collects their metadata.
+// Every placeholder must appear once and be declared exactly once, so the number of placeholders equals the number of declared parameters.
 func generateParameters(topic string, topicParams []TopicParameter) ([]generate.MQTTTopicParameter, error) {
 	var parameters []generate.MQTTTopicParameter
 	// Validate path parameters and collect metadata
 	params := map[string]struct{}{}
+	paramNames := []string{} // Topic order, for deterministic errors
 	documentedPathParams := map[string]struct{}{}
 
 	// Extract param names from topic
@@ -90,7 +134,12 @@ func generateParameters(topic string, topicParams []TopicParameter) ([]generate.
 		}
 
 		for _, paramName := range paramsName {
+			if _, exists := params[paramName]; exists {
+				return nil, fmt.Errorf("topic parameter %s appears more than once in topic %s", paramName, topic)
+			}
+
 			params[paramName] = struct{}{}
+			paramNames = append(paramNames, paramName)
 		}
 	}
 
@@ -115,16 +164,20 @@ func generateParameters(topic string, topicParams []TopicParameter) ([]generate.
 		})
 
 		if _, exists := params[paramSpec.Name]; !exists {
-			return nil, fmt.Errorf("documented parameter %s not found in topic", paramSpec.Name)
+			return nil, fmt.Errorf("topic %s has %d parameters but %d are declared: documented parameter %s not found in topic", topic, len(params), len(topicParams), paramSpec.Name)
+		}
+
+		if _, exists := documentedPathParams[paramSpec.Name]; exists {
+			return nil, fmt.Errorf("topic parameter %s declared more than once for topic %s", paramSpec.Name, topic)
 		}
 
 		documentedPathParams[paramSpec.Name] = struct{}{}
 	}
 
-	// Now go over all discovered path parameters and validate that they are documented
-	for name := range params {
+	// Now go over all discovered path parameters in topic order and validate that they are documented
+	for _, name := range paramNames {
 		if _, exists := documentedPathParams[name]; !exists {
-			return nil, fmt.Errorf("topic parameter %s not documented", name)
+			return nil, fmt.Errorf("topic %s has %d parameters but %d are declared: topic parameter %s not documented", topic, len(params), len(topicParams), name)
 		}
 	}
 
@@ -182,13 +235,30 @@ func (mb *MQTTBuilder) validateSubscriptionSpec(spec SubscriptionSpec) error {
 		return errors.New("messageType is required")
 	}
 
-	if spec.Handler == nil {
-		return errors.New("handler is required")
+	if spec.Handler == nil && spec.TypedHandler == nil {
+		return errors.New("handler or typedHandler is required")
+	}
+
+	if spec.Handler != nil && spec.TypedHandler != nil {
+		return errors.New("only one of handler or typedHandler may be set")
+	}
+
+	if spec.TypedHandler != nil {
+		want := derefType(reflect.TypeOf(spec.MessageType))
+		if got := spec.TypedHandler.messageType(); got != want {
+			return fmt.Errorf("typedHandler message type %v does not match messageType %v", got, want)
+		}
 	}
 
 	if err := validateQoS(spec.QoS); err != nil {
 		return err
 	}
 
+	if spec.DeadLetterTopic != "" {
+		if err := validateDeadLetterTopic(spec); err != nil {
+			return fmt.Errorf("invalid dead-letter topic: %w", err)
+		}
+	}
+
 	return nil
 }
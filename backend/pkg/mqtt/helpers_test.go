@@ -3,6 +3,9 @@ package mqtt
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
 )
 
 func TestValidateTopicPattern(t *testing.T) {
@@ -142,6 +145,78 @@ func TestValidateTopicPattern(t *testing.T) {
 	}
 }
 
+func TestRegisterTopicParameterMismatch(t *testing.T) {
+	t.Parallel()
+
+	deviceID := TopicParameter{Name: "deviceID", Description: "Device ID", Type: new(string)}
+	sensorID := TopicParameter{Name: "sensorID", Description: "Sensor ID", Type: new(string)}
+
+	tests := []struct {
+		name     string
+		topic    string
+		params   []TopicParameter
+		errorMsg string
+	}{
+		{
+			name:     "too few declared parameters",
+			topic:    "devices/{deviceID}/sensors/{sensorID}/temperature",
+			params:   []TopicParameter{deviceID},
+			errorMsg: "has 2 parameters but 1 are declared: topic parameter sensorID not documented",
+		},
+		{
+			name:     "too many declared parameters",
+			topic:    "devices/{deviceID}/temperature",
+			params:   []TopicParameter{deviceID, sensorID},
+			errorMsg: "has 1 parameters but 2 are declared: documented parameter sensorID not found in topic",
+		},
+		{
+			name:     "parameter declared twice",
+			topic:    "devices/{deviceID}/temperature",
+			params:   []TopicParameter{deviceID, deviceID},
+			errorMsg: "topic parameter deviceID declared more than once",
+		},
+		{
+			name:     "placeholder repeated in topic",
+			topic:    "devices/{deviceID}/mirror/{deviceID}",
+			params:   []TopicParameter{deviceID},
+			errorMsg: "topic parameter deviceID appears more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mb := newTestBuilder(t)
+
+			err := mb.RegisterPublish(tt.topic, PublicationSpec{
+				OperationID:     "publishTemperature",
+				Summary:         "Publish temperature",
+				Description:     "Publishes a temperature reading",
+				Group:           "Telemetry",
+				TopicParameters: tt.params,
+				MessageType:     testTemperature{},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("RegisterPublish() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+
+			err = mb.RegisterSubscribe(tt.topic, SubscriptionSpec{
+				OperationID:     "subscribeTemperature",
+				Summary:         "Subscribe to temperature",
+				Description:     "Receives temperature readings",
+				Group:           "Telemetry",
+				TopicParameters: tt.params,
+				MessageType:     testTemperature{},
+				Handler:         func(*paho.Publish) {},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("RegisterSubscribe() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
+
 func TestConvertTopicToMQTT(t *testing.T) {
 	t.Parallel()
 
@@ -249,3 +324,190 @@ func TestValidateQoS(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateWill(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        MQTTClientOptions
+		expectError bool
+	}{
+		{
+			name: "no will",
+			opts: MQTTClientOptions{},
+		},
+		{
+			name: "valid will",
+			opts: MQTTClientOptions{WillTopic: "gateways/gateway-01/status", WillPayload: []byte("offline"), WillQoS: QoSAtLeastOnce, WillRetain: true},
+		},
+		{
+			name:        "payload without topic",
+			opts:        MQTTClientOptions{WillPayload: []byte("offline")},
+			expectError: true,
+		},
+		{
+			name:        "wildcard topic",
+			opts:        MQTTClientOptions{WillTopic: "gateways/#"},
+			expectError: true,
+		},
+		{
+			name:        "parameterized topic",
+			opts:        MQTTClientOptions{WillTopic: "gateways/{gatewayID}/status"},
+			expectError: true,
+		},
+		{
+			name:        "invalid qos",
+			opts:        MQTTClientOptions{WillTopic: "gateways/status", WillQoS: 3},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.opts.validateWill()
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateWill() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateReconnect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        MQTTClientOptions
+		expectError bool
+	}{
+		{
+			name: "defaults",
+			opts: MQTTClientOptions{},
+		},
+		{
+			name: "constant backoff",
+			opts: MQTTClientOptions{ReconnectBackoffMin: time.Second},
+		},
+		{
+			name: "exponential backoff",
+			opts: MQTTClientOptions{ReconnectBackoffMin: time.Second, ReconnectBackoffMax: time.Minute, ConnectTimeout: 5 * time.Second},
+		},
+		{
+			name:        "max without min",
+			opts:        MQTTClientOptions{ReconnectBackoffMax: time.Minute},
+			expectError: true,
+		},
+		{
+			name:        "max less than min",
+			opts:        MQTTClientOptions{ReconnectBackoffMin: time.Minute, ReconnectBackoffMax: time.Second},
+			expectError: true,
+		},
+		{
+			name:        "negative backoff",
+			opts:        MQTTClientOptions{ReconnectBackoffMin: -time.Second},
+			expectError: true,
+		},
+		{
+			name:        "negative connect timeout",
+			opts:        MQTTClientOptions{ConnectTimeout: -time.Second},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.opts.validateReconnect()
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateReconnect() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateWildcardTopic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		topic       string
+		expectError bool
+		errorMsg    string
+	}{
+		// Valid filters
+		{
+			name:  "all topics",
+			topic: "#",
+		},
+		{
+			name:  "trailing multi-level wildcard",
+			topic: "devices/#",
+		},
+		{
+			name:  "single-level wildcards",
+			topic: "devices/+/sensors/+",
+		},
+		{
+			name:  "mixed wildcards",
+			topic: "devices/+/#",
+		},
+		// Invalid filters
+		{
+			name:        "multi-level wildcard in the middle",
+			topic:       "devices/#/temperature",
+			expectError: true,
+			errorMsg:    "only allowed as the final segment",
+		},
+		{
+			name:        "multi-level wildcard within segment",
+			topic:       "devices/sensor#",
+			expectError: true,
+			errorMsg:    "only allowed as the final segment",
+		},
+		{
+			name:        "single-level wildcard within segment",
+			topic:       "devices/sensor+/temperature",
+			expectError: true,
+			errorMsg:    "must occupy an entire segment",
+		},
+		{
+			name:        "parameter",
+			topic:       "devices/{deviceID}/#",
+			expectError: true,
+			errorMsg:    "parameters {param} are not supported",
+		},
+		{
+			name:        "empty segment",
+			topic:       "devices//#",
+			expectError: true,
+			errorMsg:    "empty segments are not allowed",
+		},
+		{
+			name:        "empty topic",
+			topic:       "",
+			expectError: true,
+			errorMsg:    "topic cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateWildcardTopic(tt.topic)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("validateWildcardTopic(%q) expected error containing %q, got nil", tt.topic, tt.errorMsg)
+				} else if !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("validateWildcardTopic(%q) error = %q, want error containing %q", tt.topic, err.Error(), tt.errorMsg)
+				}
+			} else if err != nil {
+				t.Errorf("validateWildcardTopic(%q) unexpected error: %v", tt.topic, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package mqtt
+
+import "sync/atomic"
+
+// Metrics is a snapshot of the message counters of an [MQTTBuilder].
+type Metrics struct {
+	PublishedCount uint64 // Messages published successfully
+	ReceivedCount  uint64 // Messages routed to a subscription handler
+	DroppedCount   uint64 // Received messages dropped because the topic parameters or payload could not be decoded, or the payload was empty
+}
+
+// messageCounters holds the message counters, updated concurrently from the publish and handler paths.
+type messageCounters struct {
+	published atomic.Uint64
+	received  atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// Metrics returns a snapshot of the message counters since the builder was created.
+// Each counter is read atomically, but the snapshot as a whole is not.
+func (mb *MQTTBuilder) Metrics() Metrics {
+	return Metrics{
+		PublishedCount: mb.counters.published.Load(),
+		ReceivedCount:  mb.counters.received.Load(),
+		DroppedCount:   mb.counters.dropped.Load(),
+	}
+}
@@ -0,0 +1,87 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// fakePublisher records published messages and fails publishes to topics in failTopics.
+type fakePublisher struct {
+	mu         sync.Mutex
+	published  []*paho.Publish
+	failTopics map[string]struct{}
+}
+
+func (f *fakePublisher) Publish(_ context.Context, p *paho.Publish) (*paho.PublishResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, fail := f.failTopics[p.Topic]; fail {
+		return nil, errors.New("publish rejected")
+	}
+
+	f.published = append(f.published, p)
+
+	return &paho.PublishResponse{}, nil
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	mb := newTestBuilder(t)
+	mb.MustRegisterPublish("devices/{deviceID}/temperature", PublicationSpec{
+		OperationID:     "publishTemperature",
+		Summary:         "Publish temperature",
+		Description:     "Publishes a temperature reading",
+		Group:           "Telemetry",
+		TopicParameters: []TopicParameter{{Name: "deviceID", Description: "Device ID", Type: new(string)}},
+		MessageType:     testTemperature{},
+	})
+
+	var handled int
+
+	mb.MustRegisterSubscribe("commands/{deviceID}/temperature", SubscriptionSpec{
+		OperationID:     "subscribeTemperature",
+		Summary:         "Subscribe to temperature",
+		Description:     "Receives temperature readings",
+		Group:           "Telemetry",
+		TopicParameters: []TopicParameter{{Name: "deviceID", Description: "Device ID", Type: new(string)}},
+		MessageType:     testTemperature{},
+		TypedHandler: TypedHandler(func(context.Context, map[string]string, testTemperature) error {
+			handled++
+
+			return nil
+		}),
+	})
+
+	c := mb.Client()
+	pub := mb.publications["publishTemperature"]
+	fake := &fakePublisher{failTopics: map[string]struct{}{"devices/broken/temperature": {}}}
+
+	for _, topic := range []string{"devices/device-001/temperature", "devices/device-002/temperature", "devices/broken/temperature"} {
+		_ = c.publishWith(t.Context(), fake, pub, topic, testTemperature{Value: 21}, publishOptions{qos: pub.QoS})
+	}
+
+	// Route messages as the connection manager would, one of them cannot be decoded
+	for _, payload := range []string{`{"value":21}`, `{"value":22}`, `{`} {
+		mb.router.Route(&packets.Publish{Topic: "commands/device-001/temperature", Payload: []byte(payload), Properties: &packets.Properties{}})
+	}
+
+	want := Metrics{PublishedCount: 2, ReceivedCount: 3, DroppedCount: 1}
+	if got := mb.Metrics(); got != want {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+
+	if len(fake.published) != 2 {
+		t.Errorf("published %d messages, want 2", len(fake.published))
+	}
+
+	if handled != 2 {
+		t.Errorf("handler called %d times, want 2", handled)
+	}
+}
@@ -1,6 +1,7 @@
 package mqtt
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -19,6 +20,11 @@ const (
 	disconnectTimeout = 10 * time.Second
 )
 
+// Sentinel errors for specific cases.
+var (
+	ErrConnectionLost = errors.New("connection to mqtt broker lost")
+)
+
 // MQTTBuilder provides a fluent API for registering MQTT publications and subscriptions.
 type MQTTBuilder struct {
 	connMgr       *autopaho.ConnectionManager
@@ -29,10 +35,16 @@ type MQTTBuilder struct {
 	operationIDs  map[string]struct{}
 	publications  map[string]*PublicationSpec
 	subscriptions map[string]*SubscriptionSpec
-	connected     atomic.Bool
-	opts          MQTTClientOptions
+
+	publicationTopics map[string]*PublicationSpec // Publications keyed by parameterized topic, for [PublishJSON]
+	connected         atomic.Bool
+	opts              MQTTClientOptions
 
 	registrationsCompleted atomic.Bool
+	counters               messageCounters
+	handlers               handlerTracker
+
+	events connectionEvents // User connection callbacks, see [MQTTBuilder.OnConnect]
 }
 
 // NewMQTTBuilder creates a new MQTT builder with the given broker configuration.
@@ -48,6 +60,32 @@ func NewMQTTBuilder(l *slog.Logger, collector generate.MQTTMetadataCollector, op
 		return nil, errors.New("client ID is required")
 	}
 
+	if err := opts.validateWill(); err != nil {
+		return nil, err
+	}
+
+	if err := opts.validateReconnect(); err != nil {
+		return nil, fmt.Errorf("invalid reconnect configuration: %w", err)
+	}
+
+	if opts.DrainTimeout < 0 {
+		return nil, errors.New("drain timeout must not be negative")
+	}
+
+	// Resolve TLS configuration up front so misconfiguration fails fast
+	tlsConfig, err := opts.resolveTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	opts.TLSConfig = tlsConfig
+
+	if opts.ValidatePayloads {
+		if _, ok := collector.(generate.MQTTPayloadValidator); !ok {
+			return nil, errors.New("payload validation requires a collector that can validate payloads (e.g., generation enabled)")
+		}
+	}
+
 	// Create a router for handling incoming messages
 	router := paho.NewStandardRouter()
 
@@ -59,13 +97,15 @@ func NewMQTTBuilder(l *slog.Logger, collector generate.MQTTMetadataCollector, op
 		operationIDs:  make(map[string]struct{}),
 		publications:  make(map[string]*PublicationSpec),
 		subscriptions: make(map[string]*SubscriptionSpec),
+
+		publicationTopics: make(map[string]*PublicationSpec),
 	}
 
 	// Create wrapped client with nil connMgr - will be populated in [MQTTBuilder.Connect]
 	// This allows [MQTTBuilder.Client] to be called before [MQTTBuilder.Connect]
 	mb.wrappedClient = newWrappedMQTTClient(l, nil, mb)
 
-	mqttBuilderLogger.Info("mqtt builder created", slog.String("broker", opts.BrokerURL), slog.String("clientID", opts.ClientID))
+	mqttBuilderLogger.Info("mqtt builder created", slog.String("broker", opts.BrokerURL), slog.String("clientID", opts.ClientID), slog.Bool("tls", tlsConfig != nil), slog.Bool("validatePayloads", opts.ValidatePayloads))
 
 	return mb, nil
 }
@@ -96,6 +136,11 @@ func (mb *MQTTBuilder) RegisterPublish(topic string, spec PublicationSpec) error
 		return fmt.Errorf("duplicate operationID: %s", spec.OperationID)
 	}
 
+	// Check for duplicate topic, each topic maps to a single message type
+	if existing, exists := mb.publicationTopics[topic]; exists {
+		return fmt.Errorf("duplicate publication topic %s (already registered by operationID %s)", topic, existing.OperationID)
+	}
+
 	// Convert topic parameters to documentation format
 	topicParams, err := generateParameters(topic, spec.TopicParameters)
 	if err != nil {
@@ -127,6 +172,7 @@ func (mb *MQTTBuilder) RegisterPublish(topic string, spec PublicationSpec) error
 	// Store publication
 	mb.operationIDs[spec.OperationID] = struct{}{}
 	mb.publications[spec.OperationID] = &spec
+	mb.publicationTopics[topic] = &spec
 
 	mb.l.Info("registered mqtt publication", slog.String("operationID", spec.OperationID), slog.String("topic", topic), slog.String("group", spec.Group))
 
@@ -199,7 +245,7 @@ func (mb *MQTTBuilder) RegisterSubscribe(topic string, spec SubscriptionSpec) er
 	mb.subscriptions[spec.OperationID] = &spec
 
 	// Register handler with the router
-	mb.router.RegisterHandler(mqttTopic, spec.Handler)
+	mb.router.RegisterHandler(mqttTopic, mb.subscriptionHandler(topic, &spec))
 
 	mb.l.Info("registered mqtt subscription", slog.String("operationID", spec.OperationID), slog.String("topic", topic), slog.String("group", spec.Group))
 
@@ -214,9 +260,86 @@ func (mb *MQTTBuilder) MustRegisterSubscribe(topic string, spec SubscriptionSpec
 	}
 }
 
+// RegisterWildcardSubscribe registers a subscription to a raw MQTT topic filter with wildcards (e.g., devices/# or devices/+/status).
+// Unlike [MQTTBuilder.RegisterSubscribe], the topic is used as-is, '+' and a trailing '#' are allowed and no
+// parameters are extracted. Wildcard subscriptions are intended for monitoring and are not included in the generated documentation.
+func (mb *MQTTBuilder) RegisterWildcardSubscribe(topic string, spec SubscriptionSpec) error {
+	if mb.registrationsCompleted.Load() {
+		return errors.New("cannot register subscription after connecting to MQTT broker")
+	}
+
+	// Validate topic
+	if err := validateWildcardTopic(topic); err != nil {
+		return fmt.Errorf("invalid wildcard topic: %w", err)
+	}
+
+	// Validate spec
+	if err := mb.validateSubscriptionSpec(spec); err != nil {
+		return fmt.Errorf("invalid subscription spec: %w", err)
+	}
+
+	if len(spec.TopicParameters) > 0 {
+		return errors.New("invalid subscription spec: topic parameters are not supported for wildcard subscriptions")
+	}
+
+	// Check for duplicate operationID
+	if _, exists := mb.operationIDs[spec.OperationID]; exists {
+		return fmt.Errorf("duplicate operationID: %s", spec.OperationID)
+	}
+
+	spec.TopicMQTT = topic
+
+	// Store subscription, the topic is already in MQTT format
+	mb.operationIDs[spec.OperationID] = struct{}{}
+	mb.subscriptions[spec.OperationID] = &spec
+
+	// Register handler with the router
+	mb.router.RegisterHandler(topic, mb.subscriptionHandler(topic, &spec))
+
+	mb.l.Info("registered mqtt wildcard subscription", slog.String("operationID", spec.OperationID), slog.String("topic", topic), slog.String("group", spec.Group))
+
+	return nil
+}
+
+// MustRegisterWildcardSubscribe registers a wildcard subscription and terminates the program if an error occurs.
+func (mb *MQTTBuilder) MustRegisterWildcardSubscribe(topic string, spec SubscriptionSpec) {
+	if err := mb.RegisterWildcardSubscribe(topic, spec); err != nil {
+		mb.l.Error("failed to register wildcard subscription", slog.String("operationID", spec.OperationID), slog.String("topic", topic), slog.String("group", spec.Group), utils.ErrAttr(err))
+		os.Exit(1)
+	}
+}
+
+// subscriptionHandler returns the router handler of a subscription, counting every received message
+// and tracking the running handler so disconnecting can wait for it.
+func (mb *MQTTBuilder) subscriptionHandler(topic string, spec *SubscriptionSpec) paho.MessageHandler {
+	handler := spec.Handler
+	if spec.TypedHandler != nil {
+		var onError handlerErrorFunc
+		if spec.DeadLetterTopic != "" {
+			onError = mb.deadLetterFunc(spec, mb.wrappedClient.deadLetter)
+		}
+
+		handler = spec.TypedHandler.pahoHandler(mb.l, &mb.counters, spec.OperationID, topic, onError)
+	}
+
+	return func(msg *paho.Publish) {
+		mb.counters.received.Add(1)
+
+		if !mb.handlers.start() {
+			mb.l.Warn("mqtt client is disconnecting, refusing message", slog.String("operationID", spec.OperationID), slog.String("topic", msg.Topic))
+
+			return
+		}
+		defer mb.handlers.done()
+
+		handler(msg)
+	}
+}
+
 // Connect connects to the MQTT broker and waits for the connection to complete.
 // This will disallow any further registration calls.
-// [MQTTBuilder.RegisterPublish], [MQTTBuilder.MustRegisterPublish],[MQTTBuilder.RegisterSubscribe], [MQTTBuilder.MustRegisterSubscribe].
+// [MQTTBuilder.RegisterPublish], [MQTTBuilder.MustRegisterPublish],[MQTTBuilder.RegisterSubscribe], [MQTTBuilder.MustRegisterSubscribe],
+// [MQTTBuilder.RegisterWildcardSubscribe], [MQTTBuilder.MustRegisterWildcardSubscribe].
 func (mb *MQTTBuilder) Connect(ctx context.Context) error {
 	mb.registrationsCompleted.Store(true)
 
@@ -229,7 +352,25 @@ func (mb *MQTTBuilder) Connect(ctx context.Context) error {
 	mb.connMgr = connMgr
 	mb.wrappedClient.connMgr = connMgr
 
-	mb.l.Info("connecting to mqtt broker... will wait indefinitely for connection to complete")
+	willAttrs := []any{slog.Bool("will", mb.opts.WillTopic != "")}
+	if mb.opts.WillTopic != "" {
+		willAttrs = append(willAttrs, slog.String("willTopic", mb.opts.WillTopic), slog.Int("willQoS", int(mb.opts.WillQoS)), slog.Bool("willRetain", mb.opts.WillRetain))
+	}
+
+	// Bound the initial wait when a connect timeout is configured,
+	// the connection manager itself keeps using the parent context
+	awaitCtx := ctx
+
+	if mb.opts.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+
+		awaitCtx, cancel = context.WithTimeout(ctx, mb.opts.ConnectTimeout)
+		defer cancel()
+
+		mb.l.Info("connecting to mqtt broker...", append(willAttrs, slog.Duration("timeout", mb.opts.ConnectTimeout))...)
+	} else {
+		mb.l.Info("connecting to mqtt broker... will wait indefinitely for connection to complete", willAttrs...)
+	}
 
 	done := make(chan struct{})
 	defer close(done)
@@ -256,7 +397,7 @@ func (mb *MQTTBuilder) Connect(ctx context.Context) error {
 	// will automatically connect when [autopaho.NewConnection] is called
 	// from within the [newAutopahoConnection] function
 	// We just need to wait for the first successful connection
-	err = mb.connMgr.AwaitConnection(ctx)
+	err = mb.connMgr.AwaitConnection(awaitCtx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
 	}
@@ -267,7 +408,11 @@ func (mb *MQTTBuilder) Connect(ctx context.Context) error {
 }
 
 // DisconnectWithDefaultTimeout disconnects from the MQTT broker with a default timeout.
+// Messages received from now on are refused, and running subscription handlers are given up to
+// [MQTTClientOptions.DrainTimeout] to complete before the disconnect is sent.
 func (mb *MQTTBuilder) DisconnectWithDefaultTimeout() {
+	mb.drainHandlers()
+
 	if !mb.wrappedClient.IsConnected() {
 		return
 	}
@@ -288,12 +433,44 @@ func (mb *MQTTBuilder) DisconnectWithDefaultTimeout() {
 	mb.l.Info("disconnected from mqtt broker")
 }
 
+// OnConnect registers a callback invoked every time the client connects or reconnects to the broker.
+// Connection callbacks run one at a time, in the order of the connection state changes, so a flapping connection
+// never runs a lost callback after the following connect callback. They run outside the connection manager,
+// a slow callback only delays the following ones. Callbacks may be registered at any time.
+func (mb *MQTTBuilder) OnConnect(fn func()) {
+	mb.events.addOnUp(fn)
+}
+
+// OnConnectionLost registers a callback invoked every time an active connection to the broker is lost.
+// The callback receives [ErrConnectionLost]. Callbacks are delivered in order with the [MQTTBuilder.OnConnect] ones.
+func (mb *MQTTBuilder) OnConnectionLost(fn func(error)) {
+	mb.events.addOnDown(fn)
+}
+
+// drainHandlers refuses new messages and waits for the running subscription handlers to complete, up to the drain timeout.
+func (mb *MQTTBuilder) drainHandlers() {
+	timeout := cmp.Or(mb.opts.DrainTimeout, disconnectTimeout)
+
+	mb.l.Info("waiting for running mqtt subscription handlers...", slog.Duration("timeout", timeout))
+
+	if !mb.handlers.drain(timeout) {
+		mb.l.Warn("timed out waiting for mqtt subscription handlers, some may still be running", slog.Duration("timeout", timeout))
+
+		return
+	}
+
+	mb.l.Info("mqtt subscription handlers completed")
+}
+
 // onConnect is called when the client successfully connects or reconnects to the broker.
 func (mb *MQTTBuilder) onConnect(ctx context.Context) func(*autopaho.ConnectionManager, *paho.Connack) {
 	return func(_ *autopaho.ConnectionManager, _ *paho.Connack) {
 		mb.l.Info("connected to mqtt broker, subscribing to topics", slog.Int("subscriptionCount", len(mb.subscriptions)))
 		mb.connected.Store(true)
-		// Subscribe to all registered subscriptions at once
+
+		mb.events.up()
+
+		// Subscribe to all registered subscriptions, failures are also exposed via [MQTTClient.SubscriptionErrors]
 		go func() {
 			if err := mb.wrappedClient.SubscribeAll(ctx); err != nil {
 				mb.l.Error("failed to subscribe to topics", utils.ErrAttr(err))
@@ -312,5 +489,7 @@ func (mb *MQTTBuilder) onConnectionDown() bool {
 	mb.l.Warn("connection to mqtt broker lost")
 	mb.connected.Store(false)
 
+	mb.events.down(ErrConnectionLost)
+
 	return true // Return true to allow autopaho to attempt reconnection
 }
@@ -0,0 +1,199 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"http-mqtt-boilerplate/backend/pkg/generate"
+)
+
+func TestConnectTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Reserve a local port and close the listener so nothing accepts connections on it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+
+	deadBroker := "mqtt://" + listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		t.Fatalf("failed to close listener: %v", err)
+	}
+
+	const timeout = 500 * time.Millisecond
+
+	mb, err := NewMQTTBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{}, MQTTClientOptions{
+		BrokerURL:           deadBroker,
+		ClientID:            "test",
+		ReconnectBackoffMin: 50 * time.Millisecond,
+		ReconnectBackoffMax: 200 * time.Millisecond,
+		ConnectTimeout:      timeout,
+	})
+	if err != nil {
+		t.Fatalf("NewMQTTBuilder() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	err = mb.Connect(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Connect() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > timeout+time.Second {
+		t.Errorf("Connect() returned after %s, want within %s", elapsed, timeout)
+	}
+}
+
+func TestRegisterWildcardSubscribe(t *testing.T) {
+	t.Parallel()
+
+	noop := func(context.Context, map[string]string, testTemperature) error { return nil }
+
+	newSpec := func(operationID string) SubscriptionSpec {
+		return SubscriptionSpec{
+			OperationID:  operationID,
+			Summary:      "Monitor devices",
+			Description:  "Receives every device message",
+			Group:        "Monitoring",
+			MessageType:  testTemperature{},
+			TypedHandler: TypedHandler(noop),
+		}
+	}
+
+	mb := newTestBuilder(t)
+
+	if err := mb.RegisterWildcardSubscribe("devices/#", newSpec("monitorDevices")); err != nil {
+		t.Fatalf("RegisterWildcardSubscribe() error = %v", err)
+	}
+
+	if got := mb.subscriptions["monitorDevices"].TopicMQTT; got != "devices/#" {
+		t.Errorf("TopicMQTT = %q, want %q", got, "devices/#")
+	}
+
+	if err := mb.RegisterWildcardSubscribe("devices/+/status", newSpec("monitorDevices")); err == nil {
+		t.Error("RegisterWildcardSubscribe() expected duplicate operationID error, got nil")
+	}
+
+	if err := mb.RegisterWildcardSubscribe("devices/#/status", newSpec("monitorStatus")); err == nil {
+		t.Error("RegisterWildcardSubscribe() expected invalid topic error, got nil")
+	}
+
+	spec := newSpec("monitorSensors")
+	spec.TopicParameters = []TopicParameter{{Name: "deviceID", Description: "Device ID", Type: new(string)}}
+
+	if err := mb.RegisterWildcardSubscribe("devices/+/sensors", spec); err == nil {
+		t.Error("RegisterWildcardSubscribe() expected topic parameters error, got nil")
+	}
+
+	// The documented path still rejects raw wildcards
+	if err := mb.RegisterSubscribe("devices/#", newSpec("subscribeDevices")); err == nil {
+		t.Error("RegisterSubscribe() expected wildcard error, got nil")
+	}
+}
+
+func TestConnectionCallbacks(t *testing.T) {
+	t.Parallel()
+
+	mb := newTestBuilder(t)
+
+	connected := make(chan struct{}, 1)
+	lost := make(chan error, 1)
+	release := make(chan struct{})
+
+	// Blocking callbacks must not block the connection manager hooks
+	mb.OnConnect(func() {
+		connected <- struct{}{}
+
+		<-release
+	})
+	mb.OnConnectionLost(func(err error) {
+		lost <- err
+	})
+
+	// Fake the connection manager by invoking its hooks directly
+	mb.onConnect(t.Context())(nil, nil)
+
+	select {
+	case <-connected:
+	case <-time.After(time.Second):
+		t.Fatal("OnConnect callback was not invoked")
+	}
+
+	if !mb.wrappedClient.IsConnected() {
+		t.Error("IsConnected() = false after connect, want true")
+	}
+
+	if reconnect := mb.onConnectionDown(); !reconnect {
+		t.Error("onConnectionDown() = false, want true to keep reconnecting")
+	}
+
+	if mb.wrappedClient.IsConnected() {
+		t.Error("IsConnected() = true after connection lost, want false")
+	}
+
+	// The lost callback waits for the running connect callback
+	select {
+	case err := <-lost:
+		t.Fatalf("OnConnectionLost callback invoked with %v before the OnConnect callback returned", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case err := <-lost:
+		if !errors.Is(err, ErrConnectionLost) {
+			t.Errorf("OnConnectionLost error = %v, want %v", err, ErrConnectionLost)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnConnectionLost callback was not invoked")
+	}
+}
+
+func TestConnectionCallbacksOrder(t *testing.T) {
+	t.Parallel()
+
+	const flaps = 100
+
+	mb := newTestBuilder(t)
+
+	events := make(chan string, 2*flaps+1)
+
+	mb.OnConnect(func() { events <- "connect" })
+	mb.OnConnectionLost(func(error) { events <- "lost" })
+
+	// A flapping connection: connect, then lose and reconnect repeatedly
+	onConnect := mb.onConnect(t.Context())
+	onConnect(nil, nil)
+
+	for range flaps {
+		mb.onConnectionDown()
+		onConnect(nil, nil)
+	}
+
+	for i := range 2*flaps + 1 {
+		want := "connect"
+		if i%2 == 1 {
+			want = "lost"
+		}
+
+		select {
+		case got := <-events:
+			if got != want {
+				t.Fatalf("callback %d = %s, want %s", i, got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("callback %d was not invoked", i)
+		}
+	}
+}
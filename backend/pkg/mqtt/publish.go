@@ -0,0 +1,179 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"http-mqtt-boilerplate/backend/pkg/generate"
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// PublishOption customizes a single publish call.
+type PublishOption func(*publishOptions)
+
+// publishOptions holds the options applied to a single publish call.
+type publishOptions struct {
+	topicParams map[string]string
+	qos         QoS
+	retained    bool
+}
+
+// WithTopicParams sets the values substituted for the {param} placeholders of the topic.
+func WithTopicParams(params map[string]string) PublishOption {
+	return func(o *publishOptions) {
+		o.topicParams = params
+	}
+}
+
+// WithQoS overrides the QoS of the [PublicationSpec] for a single publish call (e.g., a one-off diagnostic at QoS 0).
+func WithQoS(qos QoS) PublishOption {
+	return func(o *publishOptions) {
+		o.qos = qos
+	}
+}
+
+// WithRetained overrides the retained flag of the [PublicationSpec] for a single publish call.
+func WithRetained(retained bool) PublishOption {
+	return func(o *publishOptions) {
+		o.retained = retained
+	}
+}
+
+// newPublishOptions applies the options over the QoS and retained flag registered for the publication.
+func newPublishOptions(pub *PublicationSpec, opts []PublishOption) (publishOptions, error) {
+	options := publishOptions{qos: pub.QoS, retained: pub.Retained}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := validateQoS(options.qos); err != nil {
+		return options, fmt.Errorf("invalid publish options for operationID %s: %w", pub.OperationID, err)
+	}
+
+	return options, nil
+}
+
+// PublishJSON publishes a payload to the publication registered for the given parameterized topic
+// (e.g., devices/{deviceID}/temperature), using the QoS and retained flag from its [PublicationSpec]
+// unless overridden with [WithQoS] and [WithRetained].
+// The payload type must match the registered MessageType and every topic parameter must be supplied with [WithTopicParams].
+// Go does not allow type parameters on methods, so the client is passed explicitly.
+func PublishJSON[T any](ctx context.Context, c *MQTTClient, topic string, payload T, opts ...PublishOption) error {
+	pub, ok := c.builder.publicationTopics[topic]
+	if !ok {
+		return fmt.Errorf("no publication registered for topic %s", topic)
+	}
+
+	if err := validatePayloadType(pub, payload); err != nil {
+		return err
+	}
+
+	options, err := newPublishOptions(pub, opts)
+	if err != nil {
+		return err
+	}
+
+	actualTopic, err := buildTopic(topic, options.topicParams)
+	if err != nil {
+		return fmt.Errorf("failed to build topic for operationID %s: %w", pub.OperationID, err)
+	}
+
+	if c.builder.opts.ValidatePayloads {
+		if err := c.validatePayloadSchema(pub, payload); err != nil {
+			return err
+		}
+	}
+
+	return c.publish(ctx, pub, actualTopic, payload, options)
+}
+
+// validatePayloadSchema validates the marshaled payload against the generated schema of the publication's message type.
+func (c *MQTTClient) validatePayloadSchema(pub *PublicationSpec, payload any) error {
+	validator, ok := c.builder.collector.(generate.MQTTPayloadValidator)
+	if !ok {
+		return errors.New("payload validation enabled but collector cannot validate payloads")
+	}
+
+	bytes, err := utils.ToJSON(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize payload: %w", err)
+	}
+
+	if err := validator.ValidateMQTTPublicationPayload(pub.OperationID, bytes); err != nil {
+		return fmt.Errorf("invalid payload for operationID %s: %w", pub.OperationID, err)
+	}
+
+	return nil
+}
+
+// validatePayloadType checks that the payload has the same type as the registered MessageType (ignoring pointers).
+func validatePayloadType(pub *PublicationSpec, payload any) error {
+	want := derefType(reflect.TypeOf(pub.MessageType))
+	got := derefType(reflect.TypeOf(payload))
+
+	if got != want {
+		return fmt.Errorf("payload type %v does not match message type %v of operationID %s", got, want, pub.OperationID)
+	}
+
+	return nil
+}
+
+// derefType returns the element type of a pointer type, or the type itself.
+func derefType(rt reflect.Type) reflect.Type {
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	return rt
+}
+
+// buildTopic substitutes the {param} placeholders of a parameterized topic with the given values.
+// Every placeholder must have a value, and every value must be used.
+func buildTopic(topic string, params map[string]string) (string, error) {
+	segments := strings.Split(topic, "/")
+	used := make(map[string]struct{}, len(params))
+
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := segment[1 : len(segment)-1]
+
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("missing value for topic parameter %s", name)
+		}
+
+		if value == "" {
+			return "", fmt.Errorf("empty value for topic parameter %s", name)
+		}
+
+		if strings.ContainsAny(value, "/+#") {
+			return "", fmt.Errorf("value %q for topic parameter %s must not contain '/', '+' or '#'", value, name)
+		}
+
+		segments[i] = value
+		used[name] = struct{}{}
+	}
+
+	var unknown []string
+
+	for name := range params {
+		if _, ok := used[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		return "", fmt.Errorf("unknown topic parameters: %s", strings.Join(unknown, ", "))
+	}
+
+	return strings.Join(segments, "/"), nil
+}
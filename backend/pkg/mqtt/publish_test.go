@@ -0,0 +1,285 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/pkg/generate"
+)
+
+func TestBuildTopic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		topic    string
+		params   map[string]string
+		expected string
+		errorMsg string
+	}{
+		{
+			name:     "no parameters",
+			topic:    "devices/status",
+			expected: "devices/status",
+		},
+		{
+			name:     "single parameter",
+			topic:    "devices/{deviceID}/temperature",
+			params:   map[string]string{"deviceID": "device-001"},
+			expected: "devices/device-001/temperature",
+		},
+		{
+			name:     "multiple parameters",
+			topic:    "devices/{deviceID}/sensors/{sensorType}",
+			params:   map[string]string{"deviceID": "device-001", "sensorType": "humidity"},
+			expected: "devices/device-001/sensors/humidity",
+		},
+		{
+			name:     "missing parameter",
+			topic:    "devices/{deviceID}/temperature",
+			errorMsg: "missing value for topic parameter deviceID",
+		},
+		{
+			name:     "empty parameter",
+			topic:    "devices/{deviceID}/temperature",
+			params:   map[string]string{"deviceID": ""},
+			errorMsg: "empty value",
+		},
+		{
+			name:     "wildcard in value",
+			topic:    "devices/{deviceID}/temperature",
+			params:   map[string]string{"deviceID": "+"},
+			errorMsg: "must not contain",
+		},
+		{
+			name:     "unknown parameter",
+			topic:    "devices/{deviceID}/temperature",
+			params:   map[string]string{"deviceID": "device-001", "sensorType": "humidity"},
+			errorMsg: "unknown topic parameters: sensorType",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := buildTopic(tt.topic, tt.params)
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("buildTopic(%q) error = %v, want error containing %q", tt.topic, err, tt.errorMsg)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("buildTopic(%q) unexpected error: %v", tt.topic, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("buildTopic(%q) = %q, want %q", tt.topic, got, tt.expected)
+			}
+		})
+	}
+}
+
+type testTemperature struct {
+	Value float64 `json:"value"`
+}
+
+// newTestBuilder creates a builder with a no-op collector for registration tests.
+func newTestBuilder(t *testing.T) *MQTTBuilder {
+	t.Helper()
+
+	mb, err := NewMQTTBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{}, MQTTClientOptions{
+		BrokerURL: "mqtt://localhost:1883",
+		ClientID:  "test",
+	})
+	if err != nil {
+		t.Fatalf("NewMQTTBuilder() error = %v", err)
+	}
+
+	return mb
+}
+
+func TestPublishJSONValidation(t *testing.T) {
+	t.Parallel()
+
+	mb := newTestBuilder(t)
+	mb.MustRegisterPublish("devices/{deviceID}/temperature", PublicationSpec{
+		OperationID:     "publishTemperature",
+		Summary:         "Publish temperature",
+		Description:     "Publishes a temperature reading",
+		Group:           "Telemetry",
+		TopicParameters: []TopicParameter{{Name: "deviceID", Description: "Device ID", Type: new(string)}},
+		MessageType:     testTemperature{},
+	})
+
+	ctx := context.Background()
+	params := WithTopicParams(map[string]string{"deviceID": "device-001"})
+
+	tests := []struct {
+		name     string
+		publish  func() error
+		errorMsg string
+	}{
+		{
+			name: "unregistered topic",
+			publish: func() error {
+				return PublishJSON(ctx, mb.Client(), "devices/{deviceID}/humidity", testTemperature{}, params)
+			},
+			errorMsg: "no publication registered",
+		},
+		{
+			name: "payload type mismatch",
+			publish: func() error {
+				return PublishJSON(ctx, mb.Client(), "devices/{deviceID}/temperature", "hot", params)
+			},
+			errorMsg: "does not match message type",
+		},
+		{
+			name: "missing topic parameter",
+			publish: func() error {
+				return PublishJSON(ctx, mb.Client(), "devices/{deviceID}/temperature", testTemperature{})
+			},
+			errorMsg: "missing value for topic parameter deviceID",
+		},
+		{
+			name: "not connected",
+			publish: func() error {
+				return PublishJSON(ctx, mb.Client(), "devices/{deviceID}/temperature", &testTemperature{}, params)
+			},
+			errorMsg: "not connected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.publish()
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("PublishJSON() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+// rejectingCollector is a no-op collector whose payload validation always fails.
+type rejectingCollector struct {
+	generate.NoopCollector
+}
+
+func (rejectingCollector) ValidateMQTTPublicationPayload(_ string, _ []byte) error {
+	return errors.New("schema mismatch")
+}
+
+func TestPublishJSONPayloadSchemaValidation(t *testing.T) {
+	t.Parallel()
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, err := NewMQTTBuilder(l, &generate.NoopCollector{}, MQTTClientOptions{
+		BrokerURL:        "mqtt://localhost:1883",
+		ClientID:         "test",
+		ValidatePayloads: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "payload validation requires") {
+		t.Errorf("NewMQTTBuilder() error = %v, want error for collector without validation", err)
+	}
+
+	mb, err := NewMQTTBuilder(l, &rejectingCollector{}, MQTTClientOptions{
+		BrokerURL:        "mqtt://localhost:1883",
+		ClientID:         "test",
+		ValidatePayloads: true,
+	})
+	if err != nil {
+		t.Fatalf("NewMQTTBuilder() error = %v", err)
+	}
+
+	mb.MustRegisterPublish("devices/status", PublicationSpec{
+		OperationID: "publishStatus",
+		Summary:     "Publish status",
+		Description: "Publishes a status reading",
+		Group:       "Telemetry",
+		MessageType: testTemperature{},
+	})
+
+	// Validation runs before the connection check, so the schema error is reported
+	err = PublishJSON(context.Background(), mb.Client(), "devices/status", testTemperature{})
+	if err == nil || !strings.Contains(err.Error(), "schema mismatch") {
+		t.Errorf("PublishJSON() error = %v, want schema validation error", err)
+	}
+}
+
+func TestPublishOptions(t *testing.T) {
+	t.Parallel()
+
+	pub := &PublicationSpec{OperationID: "publishTemperature", QoS: QoSAtLeastOnce, Retained: true}
+
+	tests := []struct {
+		name         string
+		opts         []PublishOption
+		wantQoS      QoS
+		wantRetained bool
+		wantErr      bool
+	}{
+		{name: "registered defaults", wantQoS: QoSAtLeastOnce, wantRetained: true},
+		{name: "QoS override", opts: []PublishOption{WithQoS(QoSAtMostOnce)}, wantQoS: QoSAtMostOnce, wantRetained: true},
+		{name: "retained override", opts: []PublishOption{WithRetained(false)}, wantQoS: QoSAtLeastOnce, wantRetained: false},
+		{name: "last option wins", opts: []PublishOption{WithQoS(QoSAtMostOnce), WithQoS(QoSExactlyOnce)}, wantQoS: QoSExactlyOnce, wantRetained: true},
+		{name: "invalid QoS", opts: []PublishOption{WithQoS(3)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options, err := newPublishOptions(pub, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newPublishOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if options.qos != tt.wantQoS || options.retained != tt.wantRetained {
+				t.Errorf("newPublishOptions() = qos %d retained %v, want qos %d retained %v", options.qos, options.retained, tt.wantQoS, tt.wantRetained)
+			}
+		})
+	}
+}
+
+func TestPublishRejectsInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	mb := newTestBuilder(t)
+	mb.MustRegisterPublish("devices/status", PublicationSpec{
+		OperationID: "publishStatus",
+		Summary:     "Publish status",
+		Description: "Publishes a status reading",
+		Group:       "Telemetry",
+		MessageType: testTemperature{},
+	})
+
+	ctx := context.Background()
+
+	// Options are validated before the connection check
+	if err := PublishJSON(ctx, mb.Client(), "devices/status", testTemperature{}, WithQoS(3)); err == nil || !strings.Contains(err.Error(), "qos must be") {
+		t.Errorf("PublishJSON() error = %v, want invalid QoS error", err)
+	}
+
+	if err := mb.Client().Publish(ctx, "publishStatus", "devices/status", testTemperature{}, WithQoS(3)); err == nil || !strings.Contains(err.Error(), "qos must be") {
+		t.Errorf("Publish() error = %v, want invalid QoS error", err)
+	}
+
+	err := mb.Client().Publish(ctx, "publishStatus", "devices/status", testTemperature{}, WithTopicParams(map[string]string{"deviceID": "device-001"}))
+	if err == nil || !strings.Contains(err.Error(), "topic parameters are not supported") {
+		t.Errorf("Publish() error = %v, want topic parameters error", err)
+	}
+}
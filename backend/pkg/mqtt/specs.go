@@ -49,6 +49,8 @@ type SubscriptionSpec struct {
 	TopicParameters []TopicParameter    // TopicParameters describes the parameters in the topic pattern (e.g., {deviceID}).
 	MessageType     any                 // Expected Go type of messages received on this subscription.
 	Handler         paho.MessageHandler // Handler is the function that will be called when a message is received.
+	TypedHandler    MessageHandler      // TypedHandler is an alternative to Handler that receives the decoded message (see [TypedHandler]).
 	QoS             QoS                 // QoS is the quality of service level for this subscription.
 	Examples        map[string]any      // Examples contains named examples of messages that may be received.
+	DeadLetterTopic string              // DeadLetterTopic is an optional concrete topic that messages are republished to when the TypedHandler fails.
 }
@@ -0,0 +1,87 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+)
+
+// isTLSScheme reports whether the broker URL scheme requires TLS.
+func isTLSScheme(scheme string) bool {
+	return slices.Contains([]string{"ssl", "tls", "mqtts", "mqtt+ssl", "tcps", "wss"}, scheme)
+}
+
+// hasTLSFileOptions reports whether any of the TLS convenience options are set.
+func (o *MQTTClientOptions) hasTLSFileOptions() bool {
+	return o.CAFile != "" || o.ClientCertFile != "" || o.ClientKeyFile != "" || o.InsecureSkipVerify
+}
+
+// resolveTLSConfig returns the TLS configuration for the broker connection.
+// TLSConfig takes precedence, otherwise a config is built from the convenience options.
+// Returns nil for non-TLS brokers.
+func (o *MQTTClientOptions) resolveTLSConfig() (*tls.Config, error) {
+	brokerURL, err := url.Parse(o.BrokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse broker URL: %w", err)
+	}
+
+	useTLS := isTLSScheme(brokerURL.Scheme)
+
+	if !useTLS {
+		if o.TLSConfig != nil || o.hasTLSFileOptions() {
+			return nil, fmt.Errorf("TLS options are set but broker URL scheme %q does not use TLS", brokerURL.Scheme)
+		}
+
+		return nil, nil //nolint:nilnil // No TLS config is a valid outcome for plain brokers
+	}
+
+	if o.TLSConfig != nil {
+		if o.hasTLSFileOptions() {
+			return nil, errors.New("TLSConfig and TLS file options are mutually exclusive")
+		}
+
+		return o.TLSConfig, nil
+	}
+
+	if !o.hasTLSFileOptions() {
+		return nil, fmt.Errorf("broker URL scheme %q requires TLS but no TLS configuration was provided (set TLSConfig or CAFile)", brokerURL.Scheme)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify, //nolint:gosec // Explicitly opted in by the operator
+	}
+
+	if o.CAFile != "" {
+		caPEM, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", o.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if (o.ClientCertFile == "") != (o.ClientKeyFile == "") {
+		return nil, errors.New("client certificate and key files must be set together")
+	}
+
+	if o.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
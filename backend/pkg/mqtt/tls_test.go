@@ -0,0 +1,121 @@
+package mqtt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCA writes a self-signed CA certificate to a temporary directory and returns its path.
+func writeTestCA(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	return path
+}
+
+func TestResolveTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	caFile := writeTestCA(t)
+
+	invalidCAFile := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidCAFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("failed to write invalid CA file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        MQTTClientOptions
+		expectTLS   bool
+		expectError bool
+	}{
+		{
+			name: "plain broker",
+			opts: MQTTClientOptions{BrokerURL: "mqtt://localhost:1883"},
+		},
+		{
+			name:        "plain broker with tls options",
+			opts:        MQTTClientOptions{BrokerURL: "mqtt://localhost:1883", CAFile: caFile},
+			expectError: true,
+		},
+		{
+			name:        "tls broker without config",
+			opts:        MQTTClientOptions{BrokerURL: "mqtts://localhost:8883"},
+			expectError: true,
+		},
+		{
+			name:      "tls broker with custom config",
+			opts:      MQTTClientOptions{BrokerURL: "ssl://localhost:8883", TLSConfig: &tls.Config{MinVersion: tls.VersionTLS13}},
+			expectTLS: true,
+		},
+		{
+			name:      "tls broker with CA file",
+			opts:      MQTTClientOptions{BrokerURL: "mqtts://localhost:8883", CAFile: caFile},
+			expectTLS: true,
+		},
+		{
+			name:        "tls broker with invalid CA file",
+			opts:        MQTTClientOptions{BrokerURL: "mqtts://localhost:8883", CAFile: invalidCAFile},
+			expectError: true,
+		},
+		{
+			name:        "client cert without key",
+			opts:        MQTTClientOptions{BrokerURL: "mqtts://localhost:8883", CAFile: caFile, ClientCertFile: caFile},
+			expectError: true,
+		},
+		{
+			name:        "custom config and file options",
+			opts:        MQTTClientOptions{BrokerURL: "mqtts://localhost:8883", TLSConfig: &tls.Config{MinVersion: tls.VersionTLS13}, CAFile: caFile},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := tt.opts.resolveTLSConfig()
+			if (err != nil) != tt.expectError {
+				t.Fatalf("resolveTLSConfig() error = %v, expectError %v", err, tt.expectError)
+			}
+
+			if (cfg != nil) != tt.expectTLS {
+				t.Errorf("resolveTLSConfig() config = %v, expectTLS %v", cfg, tt.expectTLS)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"http-mqtt-boilerplate/backend/pkg/utils"
+)
+
+// TypedHandlerFunc handles a decoded subscription message.
+// params contains the values of the topic parameters (e.g., {"deviceID": "device-001"}).
+type TypedHandlerFunc[T any] func(ctx context.Context, params map[string]string, msg T) error
+
+// MessageHandler is a subscription handler created with [TypedHandler].
+type MessageHandler interface {
+	messageType() reflect.Type
+	pahoHandler(l *slog.Logger, counters *messageCounters, operationID, topic string, onError handlerErrorFunc) paho.MessageHandler
+}
+
+// handlerErrorFunc is called with the original message when a typed handler returns an error, it may be nil.
+type handlerErrorFunc func(msg *paho.Publish, err error)
+
+// typedHandler adapts a [TypedHandlerFunc] to a [paho.MessageHandler].
+type typedHandler[T any] struct {
+	fn TypedHandlerFunc[T]
+}
+
+// TypedHandler creates a subscription handler that decodes the JSON payload into T before calling fn.
+// Malformed and empty messages (e.g., a retained message being cleared) are logged, counted in [Metrics] and dropped, errors returned by fn are logged
+// and the message is republished to the DeadLetterTopic of the [SubscriptionSpec], if set.
+// T must match the MessageType of the [SubscriptionSpec] it is registered with.
+//
+//nolint:ireturn // Returns MessageHandler interface so handlers of different types can be stored in SubscriptionSpec
+func TypedHandler[T any](fn TypedHandlerFunc[T]) MessageHandler {
+	return &typedHandler[T]{fn: fn}
+}
+
+// messageType returns the type the handler decodes messages into.
+func (h *typedHandler[T]) messageType() reflect.Type {
+	return derefType(reflect.TypeFor[T]())
+}
+
+// pahoHandler returns a paho handler that decodes messages received on the parameterized topic and calls the typed handler.
+func (h *typedHandler[T]) pahoHandler(l *slog.Logger, counters *messageCounters, operationID, topic string, onError handlerErrorFunc) paho.MessageHandler {
+	return func(msg *paho.Publish) {
+		log := l.With(slog.String("operationID", operationID), slog.String("topic", msg.Topic))
+
+		params, err := ParseTopicParams(topic, msg.Topic)
+		if err != nil {
+			log.Error("failed to extract topic parameters, dropping message", utils.ErrAttr(err))
+			counters.dropped.Add(1)
+
+			return
+		}
+
+		// An empty payload clears a retained message, it must not reach the handler as a zero value
+		if len(msg.Payload) == 0 {
+			log.Debug("empty payload, dropping message")
+			counters.dropped.Add(1)
+
+			return
+		}
+
+		payload, err := utils.FromJSON[T](msg.Payload)
+		if err != nil {
+			log.Error("failed to decode message, dropping message", utils.ErrAttr(err))
+			counters.dropped.Add(1)
+
+			return
+		}
+
+		if err := h.fn(context.Background(), params, payload); err != nil {
+			log.Error("subscription handler failed", utils.ErrAttr(err))
+
+			if onError != nil {
+				onError(msg, err)
+			}
+		}
+	}
+}
+
+// ParseTopicParams matches an actual topic against a registered parameterized topic and returns the parameter values
+// keyed by name (e.g., devices/{deviceID}/temperature and devices/device-001/temperature give deviceID=device-001).
+// Raw wildcards ('+' and a trailing '#', see [MQTTBuilder.RegisterWildcardSubscribe]) match without capturing a value.
+// An error is returned when the segment counts differ or a literal segment does not match.
+// Handlers created with [TypedHandler] receive the parsed parameters, plain handlers can call it with msg.Topic.
+func ParseTopicParams(topic, actualTopic string) (map[string]string, error) {
+	segments := strings.Split(topic, "/")
+	actualSegments := strings.Split(actualTopic, "/")
+
+	// A trailing multi-level wildcard matches the parent level and any number of levels below it
+	if segments[len(segments)-1] == "#" {
+		segments = segments[:len(segments)-1]
+		if len(actualSegments) < len(segments) {
+			return nil, fmt.Errorf("topic %s has %d segments, expected at least %d to match %s", actualTopic, len(actualSegments), len(segments), topic)
+		}
+
+		actualSegments = actualSegments[:len(segments)]
+	}
+
+	if len(segments) != len(actualSegments) {
+		return nil, fmt.Errorf("topic %s has %d segments, expected %d to match %s", actualTopic, len(actualSegments), len(segments), topic)
+	}
+
+	params := make(map[string]string)
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = actualSegments[i]
+
+			continue
+		}
+
+		if segment == "+" {
+			continue
+		}
+
+		if segment != actualSegments[i] {
+			return nil, fmt.Errorf("topic %s does not match %s at segment %d", actualTopic, topic, i)
+		}
+	}
+
+	return params, nil
+}
@@ -0,0 +1,205 @@
+package mqtt
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+func TestTypedHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls     int
+		gotParams map[string]string
+		gotMsg    testTemperature
+	)
+
+	handler := TypedHandler(func(_ context.Context, params map[string]string, msg testTemperature) error {
+		calls++
+		gotParams = params
+		gotMsg = msg
+
+		return nil
+	})
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	counters := &messageCounters{}
+	h := handler.pahoHandler(l, counters, "subscribeTemperature", "devices/{deviceID}/temperature", nil)
+
+	// Malformed payload is dropped
+	h(&paho.Publish{Topic: "devices/device-001/temperature", Payload: []byte("{")})
+
+	// Topic that does not match the pattern is dropped
+	h(&paho.Publish{Topic: "devices/device-001/temperature/extra", Payload: []byte(`{"value":1}`)})
+
+	// Empty payload clearing a retained message is dropped, not decoded as a zero value
+	h(&paho.Publish{Topic: "devices/device-001/temperature", Retain: true})
+
+	if calls != 0 {
+		t.Fatalf("handler called %d times for invalid messages, want 0", calls)
+	}
+
+	if dropped := counters.dropped.Load(); dropped != 3 {
+		t.Errorf("dropped = %d, want 3", dropped)
+	}
+
+	h(&paho.Publish{Topic: "devices/device-001/temperature", Payload: []byte(`{"value":22.5}`)})
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+
+	if !reflect.DeepEqual(gotParams, map[string]string{"deviceID": "device-001"}) {
+		t.Errorf("params = %v, want deviceID=device-001", gotParams)
+	}
+
+	if gotMsg.Value != 22.5 {
+		t.Errorf("msg = %+v, want value 22.5", gotMsg)
+	}
+}
+
+func TestRegisterSubscribeTypedHandlerValidation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(_ context.Context, _ map[string]string, _ testTemperature) error { return nil }
+
+	tests := []struct {
+		name     string
+		spec     SubscriptionSpec
+		errorMsg string
+	}{
+		{
+			name:     "typed handler type mismatch",
+			spec:     SubscriptionSpec{MessageType: "not a temperature", TypedHandler: TypedHandler(noop)},
+			errorMsg: "does not match messageType",
+		},
+		{
+			name:     "both handlers set",
+			spec:     SubscriptionSpec{MessageType: testTemperature{}, TypedHandler: TypedHandler(noop), Handler: func(*paho.Publish) {}},
+			errorMsg: "only one of handler or typedHandler",
+		},
+		{
+			name:     "no handler set",
+			spec:     SubscriptionSpec{MessageType: testTemperature{}},
+			errorMsg: "handler or typedHandler is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := tt.spec
+			spec.OperationID = "subscribeTemperature"
+			spec.Summary = "Subscribe to temperature"
+			spec.Description = "Receives temperature readings"
+			spec.Group = "Telemetry"
+
+			err := newTestBuilder(t).RegisterSubscribe("devices/temperature", spec)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("RegisterSubscribe() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+		})
+	}
+
+	err := newTestBuilder(t).RegisterSubscribe("devices/temperature", SubscriptionSpec{
+		OperationID:  "subscribeTemperature",
+		Summary:      "Subscribe to temperature",
+		Description:  "Receives temperature readings",
+		Group:        "Telemetry",
+		MessageType:  &testTemperature{},
+		TypedHandler: TypedHandler(noop),
+	})
+	if err != nil {
+		t.Errorf("RegisterSubscribe() unexpected error: %v", err)
+	}
+}
+
+func TestParseTopicParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		topic       string
+		actualTopic string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:        "single parameter",
+			topic:       "devices/{deviceID}/temperature",
+			actualTopic: "devices/device-001/temperature",
+			expected:    map[string]string{"deviceID": "device-001"},
+		},
+		{
+			name:        "parameters",
+			topic:       "devices/{deviceID}/sensors/{sensorID}",
+			actualTopic: "devices/device-001/sensors/temp-1",
+			expected:    map[string]string{"deviceID": "device-001", "sensorID": "temp-1"},
+		},
+		{
+			name:        "single-level wildcard",
+			topic:       "devices/+/status",
+			actualTopic: "devices/device-001/status",
+			expected:    map[string]string{},
+		},
+		{
+			name:        "multi-level wildcard",
+			topic:       "devices/#",
+			actualTopic: "devices/device-001/sensors/temp-1",
+			expected:    map[string]string{},
+		},
+		{
+			name:        "multi-level wildcard matches parent level",
+			topic:       "devices/#",
+			actualTopic: "devices",
+			expected:    map[string]string{},
+		},
+		{
+			name:        "multi-level wildcard prefix mismatch",
+			topic:       "devices/#",
+			actualTopic: "gateways/gateway-01",
+			expectError: true,
+		},
+		{
+			name:        "segment count mismatch",
+			topic:       "devices/{deviceID}/status",
+			actualTopic: "devices/device-001",
+			expectError: true,
+		},
+		{
+			name:        "extra segments",
+			topic:       "devices/{deviceID}/status",
+			actualTopic: "devices/device-001/status/extra",
+			expectError: true,
+		},
+		{
+			name:        "literal segment mismatch",
+			topic:       "devices/{deviceID}/status",
+			actualTopic: "devices/device-001/temperature",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			params, err := ParseTopicParams(tt.topic, tt.actualTopic)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ParseTopicParams(%q, %q) error = %v, expectError %v", tt.topic, tt.actualTopic, err, tt.expectError)
+			}
+
+			if !tt.expectError && !maps.Equal(params, tt.expected) {
+				t.Errorf("ParseTopicParams(%q, %q) = %v, want %v", tt.topic, tt.actualTopic, params, tt.expected)
+			}
+		})
+	}
+}
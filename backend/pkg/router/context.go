@@ -0,0 +1,27 @@
+package router
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey struct{ name string }
+
+//nolint:gochecknoglobals // Context keys must be package-level variables
+var maxBodyBytesKey = contextKey{name: "maxBodyBytes"}
+
+// withMaxBodyBytes wraps a handler to store the route's maximum body size in the request context.
+func withMaxBodyBytes(maxBodyBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), maxBodyBytesKey, maxBodyBytes)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetMaxBodyBytesFromContext retrieves the route's maximum body size from context.
+// Returns false if the route does not set [RouteSpec.MaxBodyBytes].
+func GetMaxBodyBytesFromContext(ctx context.Context) (int64, bool) {
+	maxBodyBytes, ok := ctx.Value(maxBodyBytesKey).(int64)
+
+	return maxBodyBytes, ok
+}
@@ -4,11 +4,85 @@ import (
 	"errors"
 	"fmt"
 	"http-mqtt-boilerplate/backend/pkg/generate"
+	"maps"
 	"net/http"
 	"slices"
 	"strings"
+	"unicode/utf8"
 )
 
+// validatePath validates a route path or route prefix (e.g., /teams/{teamID}), mirroring the MQTT topic checks.
+// The path must start with a slash, must not contain empty segments or a trailing slash (except the root path),
+// and every {param} must be well formed.
+func validatePath(path string) error {
+	if path == "" {
+		return errors.New("path cannot be empty")
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return errors.New("missing leading slash")
+	}
+
+	if path == "/" {
+		return nil
+	}
+
+	if strings.HasSuffix(path, "/") {
+		return errors.New("trailing slash is not allowed")
+	}
+
+	for segment := range strings.SplitSeq(path[1:], "/") {
+		if segment == "" {
+			return errors.New("empty segments (double slashes) are not allowed")
+		}
+
+		if err := validatePathSegmentBraces(segment); err != nil {
+			return fmt.Errorf("invalid segment %q: %w", segment, err)
+		}
+
+		paramNames, err := generate.ExtractParamName(segment)
+		if err != nil {
+			return fmt.Errorf("invalid segment %q: %w", segment, err)
+		}
+
+		for _, paramName := range paramNames {
+			if !generate.IsValidParameterName(paramName) {
+				return fmt.Errorf("invalid parameter name '%s' - must start with a letter and contain only alphanumeric characters and underscores", paramName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatePathSegmentBraces checks that the braces of a path segment are balanced and not nested.
+func validatePathSegmentBraces(segment string) error {
+	open := false
+
+	for _, ch := range segment {
+		switch ch {
+		case '{':
+			if open {
+				return errors.New("nested '{' is not allowed - use {paramName} format")
+			}
+
+			open = true
+		case '}':
+			if !open {
+				return errors.New("unbalanced '}' - use {paramName} format")
+			}
+
+			open = false
+		}
+	}
+
+	if open {
+		return errors.New("unbalanced '{' - use {paramName} format")
+	}
+
+	return nil
+}
+
 // validateRouteSpec validates a RouteSpec.
 func validateRouteSpec(spec RouteSpec) error {
 	if spec.OperationID == "" {
@@ -31,6 +105,22 @@ func validateRouteSpec(spec RouteSpec) error {
 		return errors.New("field Handler required")
 	}
 
+	if spec.MaxBodyBytes < 0 {
+		return errors.New("field MaxBodyBytes must not be negative")
+	}
+
+	for i, middleware := range spec.Middlewares {
+		if middleware == nil {
+			return fmt.Errorf("field Middlewares[%d] must not be nil", i)
+		}
+	}
+
+	for statusCode, respSpec := range spec.Responses {
+		if _, exists := respSpec.Headers[""]; exists {
+			return fmt.Errorf("response header name required for status %d", statusCode)
+		}
+	}
+
 	// GET requests must not have request bodies
 	if spec.method == http.MethodGet && spec.RequestType != nil {
 		return fmt.Errorf("GET requests must not have request bodies (operation: %s, path: %s)", spec.OperationID, spec.fullPath)
@@ -39,11 +129,32 @@ func validateRouteSpec(spec RouteSpec) error {
 	return nil
 }
 
+// validateSummary checks that a summary is a single line of at most maxLength characters.
+func validateSummary(summary string, maxLength int) error {
+	if strings.TrimSpace(summary) == "" {
+		return errors.New("field Summary required")
+	}
+
+	if strings.ContainsAny(summary, "\r\n") {
+		return errors.New("field Summary must be a single line, use Description for details")
+	}
+
+	if length := utf8.RuneCountInString(summary); length > maxLength {
+		return fmt.Errorf("field Summary is %d characters long, the maximum is %d - use Description for details", length, maxLength)
+	}
+
+	return nil
+}
+
+// generateParameters validates the declared parameters and collects their metadata.
+// The path template and the declared path parameters are cross-checked: every {param} of the path
+// must be declared once with In: path, and every declared path parameter must appear in the path.
 func generateParameters(spec RouteSpec) ([]generate.ParameterInfo, error) {
 	var parameters []generate.ParameterInfo
 
 	// Validate path parameters and collect metadata
 	paramsInPath := map[string]struct{}{}
+	paramNamesInPath := []string{} // Path order, for deterministic errors
 	documentedPathParams := map[string]struct{}{}
 
 	// Extract param names from path
@@ -58,12 +169,18 @@ func generateParameters(spec RouteSpec) ([]generate.ParameterInfo, error) {
 				return nil, fmt.Errorf("invalid parameter name %s in path %s", paramName, spec.fullPath)
 			}
 
+			if _, exists := paramsInPath[paramName]; exists {
+				return nil, fmt.Errorf("path parameter %s appears more than once in path %s", paramName, spec.fullPath)
+			}
+
 			paramsInPath[paramName] = struct{}{}
+			paramNamesInPath = append(paramNamesInPath, paramName)
 		}
 	}
 
-	// For each documented parameter, validate and collect metadata
-	for name, paramSpec := range spec.Parameters {
+	// For each documented parameter, validate and collect metadata, in name order for deterministic errors
+	for _, name := range slices.Sorted(maps.Keys(spec.Parameters)) {
+		paramSpec := spec.Parameters[name]
 		if name == "" {
 			return nil, fmt.Errorf("parameter name required for %s %s", spec.method, spec.fullPath)
 		}
@@ -76,7 +193,7 @@ func generateParameters(spec RouteSpec) ([]generate.ParameterInfo, error) {
 			return nil, fmt.Errorf("parameter Type required for %s %s", spec.method, spec.fullPath)
 		}
 
-		validInValues := []ParameterIn{ParameterInPath, ParameterInQuery, ParameterInHeader}
+		validInValues := []ParameterIn{ParameterInPath, ParameterInQuery, ParameterInHeader, ParameterInCookie}
 		if !slices.Contains(validInValues, paramSpec.In) {
 			return nil, fmt.Errorf("parameter In must be one of %v for %s %s", validInValues, spec.method, spec.fullPath)
 		}
@@ -87,27 +204,79 @@ func generateParameters(spec RouteSpec) ([]generate.ParameterInfo, error) {
 			TypeValue:   paramSpec.Type,
 			Description: paramSpec.Description,
 			Required:    paramSpec.Required,
+			Default:     paramSpec.Default,
 		})
 
-		if paramSpec.In == ParameterInPath {
+		switch paramSpec.In {
+		case ParameterInPath:
 			if _, exists := paramsInPath[name]; !exists {
-				return nil, fmt.Errorf("documented path parameter %s not found in path", name)
+				return nil, fmt.Errorf("path parameter %s is declared in Parameters but does not appear in path %s %s", name, spec.method, spec.fullPath)
 			}
 
 			if !paramSpec.Required {
 				return nil, fmt.Errorf("path parameter %s must be required", name)
 			}
 
+			if paramSpec.Default != nil {
+				return nil, fmt.Errorf("path parameter %s must not have a default value", name)
+			}
+
 			documentedPathParams[name] = struct{}{}
+
+		case ParameterInQuery:
+			if !generate.IsValidParameterName(name) {
+				return nil, fmt.Errorf("invalid query parameter name %s for %s %s", name, spec.method, spec.fullPath)
+			}
+
+			if _, exists := paramsInPath[name]; exists {
+				return nil, fmt.Errorf("query parameter %s conflicts with path parameter of the same name", name)
+			}
+
+			if paramSpec.Required && paramSpec.Default != nil {
+				return nil, fmt.Errorf("required query parameter %s must not have a default value", name)
+			}
+
+		case ParameterInHeader, ParameterInCookie:
+			// Header and cookie parameters have no additional constraints
 		}
 	}
 
 	// Now go over all discovered path parameters and validate that they are documented
-	for name := range paramsInPath {
+	for _, name := range paramNamesInPath {
 		if _, exists := documentedPathParams[name]; !exists {
-			return nil, fmt.Errorf("path parameter %s not documented", name)
+			return nil, fmt.Errorf("path parameter %s appears in path %s %s but is not declared in Parameters", name, spec.method, spec.fullPath)
 		}
 	}
 
 	return parameters, nil
 }
+
+// generateHeaders converts response header specs to header metadata, sorted by name.
+func generateHeaders(headers map[string]HeaderSpec) []generate.HeaderInfo {
+	var headerInfos []generate.HeaderInfo
+
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		headerInfos = append(headerInfos, generate.HeaderInfo{
+			Name:        name,
+			TypeValue:   headers[name].Type,
+			Description: headers[name].Description,
+		})
+	}
+
+	return headerInfos
+}
+
+// generateContentVariants converts content variant specs to content variant metadata, in declaration order.
+func generateContentVariants(variants []ContentVariant) []generate.ContentVariantInfo {
+	var variantInfos []generate.ContentVariantInfo
+
+	for _, variant := range variants {
+		variantInfos = append(variantInfos, generate.ContentVariantInfo{
+			ContentType: variant.ContentType,
+			TypeValue:   variant.Type,
+			Examples:    variant.Examples,
+		})
+	}
+
+	return variantInfos
+}
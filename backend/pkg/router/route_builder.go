@@ -1,17 +1,28 @@
 package router
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"http-mqtt-boilerplate/backend/pkg/generate"
 	"http-mqtt-boilerplate/backend/pkg/utils"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// DefaultSummaryMaxLength is the summary length limit of [WithStrictSummaries] when none is given.
+const DefaultSummaryMaxLength = 80
+
+// supportedMethods lists the HTTP methods routes can be registered with.
+//
+//nolint:gochecknoglobals // Read-only list of supported methods
+var supportedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
 // RouteBuilder is a chi router that collects metadata for OpenAPI generation.
 type RouteBuilder struct {
 	router    chi.Router
@@ -19,32 +30,76 @@ type RouteBuilder struct {
 	l         *slog.Logger
 	prefix    string
 
+	summaryMaxLength int // Maximum summary length in strict summary mode, 0 disables the check
+
+	defaultMaxBodyBytes int64                                 // Body limit of routes without [RouteSpec.MaxBodyBytes], documented by bodyLimitResponse
+	bodyLimitResponse   func(maxBodyBytes int64) ResponseSpec // Builds the documented 413 response, nil documents none
+
 	operationIDs map[string]struct{}
 }
 
+// RouteBuilderOption customizes a [RouteBuilder].
+type RouteBuilderOption func(*RouteBuilder)
+
+// WithStrictSummaries rejects routes whose summary is multi-line or longer than maxLength characters,
+// keeping the generated docs readable. A non-positive maxLength uses [DefaultSummaryMaxLength].
+func WithStrictSummaries(maxLength int) RouteBuilderOption {
+	return func(rb *RouteBuilder) {
+		if maxLength <= 0 {
+			maxLength = DefaultSummaryMaxLength
+		}
+
+		rb.summaryMaxLength = maxLength
+	}
+}
+
+// WithBodyLimitResponse documents a 413 response on every route that does not declare one, built by response from
+// the route's [RouteSpec.MaxBodyBytes], or from defaultMaxBodyBytes if unset, so the docs match the enforced limit.
+func WithBodyLimitResponse(defaultMaxBodyBytes int64, response func(maxBodyBytes int64) ResponseSpec) RouteBuilderOption {
+	return func(rb *RouteBuilder) {
+		rb.defaultMaxBodyBytes = defaultMaxBodyBytes
+		rb.bodyLimitResponse = response
+	}
+}
+
 // NewRouteBuilder creates a new RouteBuilder.
-func NewRouteBuilder(l *slog.Logger, collector generate.RouteMetadataCollector) (*RouteBuilder, error) {
-	return &RouteBuilder{
+func NewRouteBuilder(l *slog.Logger, collector generate.RouteMetadataCollector, opts ...RouteBuilderOption) (*RouteBuilder, error) {
+	rb := &RouteBuilder{
 		router:       chi.NewRouter(),
 		collector:    collector,
 		operationIDs: make(map[string]struct{}),
 		l:            l.With(slog.String("component", "route-builder")),
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(rb)
+	}
+
+	return rb, nil
 }
 
 // Route adds a new route group to the router.
+// It panics if the path prefix is malformed (see [RouteBuilder.Get] for the path rules), like chi does for invalid patterns.
 func (rb *RouteBuilder) Route(path string, fn func(rb *RouteBuilder)) {
+	if err := validatePath(path); err != nil {
+		panic(fmt.Sprintf("invalid route prefix %q: %v", path, err))
+	}
+
 	oldPrefix := rb.prefix
 	rb.prefix += path
 
 	// Isolate sub-router
 	rb.router.Group(func(r chi.Router) {
 		subRB := &RouteBuilder{
-			router:       r,
-			collector:    rb.collector,
-			operationIDs: rb.operationIDs,
-			prefix:       rb.prefix,
-			l:            rb.l.With(slog.String("prefix", rb.prefix)),
+			router:           r,
+			collector:        rb.collector,
+			operationIDs:     rb.operationIDs,
+			prefix:           rb.prefix,
+			summaryMaxLength: rb.summaryMaxLength,
+			l:                rb.l.With(slog.String("prefix", rb.prefix)),
+
+			defaultMaxBodyBytes: rb.defaultMaxBodyBytes,
+			bodyLimitResponse:   rb.bodyLimitResponse,
 		}
 		fn(subRB)
 	})
@@ -73,6 +128,15 @@ type RouteSpec struct {
 
 	Parameters map[string]ParameterSpec // Parameters (ie query, path, etc) is a map of parameter name to parameter spec
 
+	Security []string // Security names the accepted security schemes declared in the API info, overrides the default security
+	Public   bool     // Public documents the route as not requiring authentication, overrides the default security
+
+	MaxBodyBytes int64                             // MaxBodyBytes is the maximum request body size, exposed via [GetMaxBodyBytesFromContext] (0 = package default)
+	Middlewares  []func(http.Handler) http.Handler // Middlewares wrap only this route, inside the group middlewares, the first one runs first
+
+	Method string // Method is the HTTP method, only used by [RouteBuilder.RegisterAll]
+	Path   string // Path is the route path relative to the group, only used by [RouteBuilder.RegisterAll]
+
 	// Internal fields
 	localPath string // localPath is the path without the prefix
 	fullPath  string // fullPath is the full path with the prefix
@@ -85,6 +149,7 @@ const (
 	ParameterInPath   ParameterIn = "path"
 	ParameterInQuery  ParameterIn = "query"
 	ParameterInHeader ParameterIn = "header"
+	ParameterInCookie ParameterIn = "cookie"
 )
 
 // ParameterSpec defines a parameter for a route.
@@ -93,20 +158,46 @@ type ParameterSpec struct {
 	Description string
 	Required    bool
 	Type        any // The Go type - validation comes from interfaces
+	Default     any // Default value for optional query and header parameters, must have the same type as Type
 }
 
 type RequestBodySpec struct {
 	Type     any
 	Examples map[string]any
+	Required *bool // Required documents whether the body must be sent, defaults to true (e.g., PATCH endpoints may accept no body)
+	Patch    bool  // Patch documents the body as the all-optional patch type of Type (e.g., TeamPatch), see [generate.OpenAPICollector.RegisterPatchType]
 }
 
 type ResponseSpec struct {
 	Description string
 	Type        any
 	Examples    map[string]any
+	ContentType string // ContentType is the media type of the body, defaults to application/json
+	Binary      bool   // Binary documents the body as raw bytes (e.g., file exports), Type must be nil
+
+	// Variants documents the body in several media types (e.g., JSON and CSV) with examples per type.
+	// When set, Type, Examples, ContentType and Binary must be unset.
+	Variants []ContentVariant
+
+	Headers map[string]HeaderSpec // Headers is a map of response header name to header spec
+}
+
+// ContentVariant defines one media type of a response with several content types.
+type ContentVariant struct {
+	ContentType string // ContentType is the media type of the body (e.g., text/csv)
+	Type        any    // Type is a zero value struct, or a string (e.g., "") for text bodies
+	Examples    map[string]any
+}
+
+// HeaderSpec defines a response header.
+type HeaderSpec struct {
+	Description string
+	Type        any // The Go type, must be a primitive or an enum
 }
 
 // Get adds a GET route to the router.
+// The path must start with a slash, must not contain double or trailing slashes (except the root path "/"),
+// and every {param} must be well formed. The same rules apply to the other methods.
 func (rb *RouteBuilder) Get(path string, spec RouteSpec) error {
 	spec.method = http.MethodGet
 
@@ -191,18 +282,61 @@ func (rb *RouteBuilder) MustDelete(path string, spec RouteSpec) {
 	}
 }
 
-// Router returns the underlying chi.Router.
-//
-//nolint:ireturn // we want to return the specific type chi.Router
-func (rb *RouteBuilder) Router() chi.Router {
-	return rb.router
+// RegisterAll adds a batch of routes, each spec carrying its own Method and Path.
+// The batch is checked before any route is added: every spec needs a supported method and a path,
+// operation IDs must be unique across the batch and the already registered routes, and each spec must
+// pass the checks of a single route (path, required fields, summary length and parameters).
+// Only a failure of the collector itself can leave the batch partially registered.
+func (rb *RouteBuilder) RegisterAll(specs []RouteSpec) error {
+	batchIDs := make(map[string]struct{}, len(specs))
+
+	for i, spec := range specs {
+		if !slices.Contains(supportedMethods, spec.Method) {
+			return fmt.Errorf("route %d (%s): unsupported method %q, must be one of %v", i, spec.OperationID, spec.Method, supportedMethods)
+		}
+
+		if spec.Path == "" {
+			return fmt.Errorf("route %d (%s): path is required", i, spec.OperationID)
+		}
+
+		if _, exists := batchIDs[spec.OperationID]; exists {
+			return fmt.Errorf("route %d: operation ID %s is used more than once in the batch", i, spec.OperationID)
+		}
+
+		if _, exists := rb.operationIDs[spec.OperationID]; exists {
+			return fmt.Errorf("route %d: operation ID %s already exists", i, spec.OperationID)
+		}
+
+		batchIDs[spec.OperationID] = struct{}{}
+
+		if _, _, err := rb.prepare(spec.Path, spec); err != nil {
+			return fmt.Errorf("route %d (%s %s): %w", i, spec.Method, spec.Path, err)
+		}
+	}
+
+	for _, spec := range specs {
+		spec.method = spec.Method
+
+		if err := rb.add(spec.Path, spec); err != nil {
+			return fmt.Errorf("failed to register %s %s: %w", spec.Method, spec.Path, err)
+		}
+	}
+
+	return nil
 }
 
-// add adds a new route to the router and collects metadata.
-func (rb *RouteBuilder) add(path string, spec RouteSpec) error {
-	sanitizedPath := generate.SanitizePath(path)
-	if path != sanitizedPath {
-		return fmt.Errorf("invalid path %q; sanitized form would be %q", path, sanitizedPath)
+// MustRegisterAll adds a batch of routes and terminates the program if an error occurs.
+func (rb *RouteBuilder) MustRegisterAll(specs []RouteSpec) {
+	if err := rb.RegisterAll(specs); err != nil {
+		rb.l.Error("fatal error", utils.ErrAttr(err))
+		os.Exit(1)
+	}
+}
+
+// prepare resolves the paths of a route and checks its spec and parameters, without registering anything.
+func (rb *RouteBuilder) prepare(path string, spec RouteSpec) (RouteSpec, []generate.ParameterInfo, error) {
+	if err := validatePath(path); err != nil {
+		return spec, nil, fmt.Errorf("invalid path %q: %w", path, err)
 	}
 
 	spec.localPath = path
@@ -211,30 +345,65 @@ func (rb *RouteBuilder) add(path string, spec RouteSpec) error {
 	spec.fullPath = cleanPath
 
 	if _, exists := rb.operationIDs[spec.OperationID]; exists {
-		return fmt.Errorf("operation ID %s already exists", spec.OperationID)
+		return spec, nil, fmt.Errorf("operation ID %s already exists", spec.OperationID)
 	}
 
 	if err := validateRouteSpec(spec); err != nil {
-		return fmt.Errorf("invalid route spec: %w", err)
+		return spec, nil, fmt.Errorf("invalid route spec: %w", err)
+	}
+
+	if rb.summaryMaxLength > 0 {
+		if err := validateSummary(spec.Summary, rb.summaryMaxLength); err != nil {
+			return spec, nil, fmt.Errorf("invalid route spec: %w", err)
+		}
 	}
 
 	// Collect parameters metadata
 	parameters, err := generateParameters(spec)
 	if err != nil {
-		return fmt.Errorf("failed to generate parameters: %w", err)
+		return spec, nil, fmt.Errorf("failed to generate parameters: %w", err)
+	}
+
+	if spec.RequestType != nil && spec.RequestType.Type == nil {
+		return spec, nil, errors.New("request type is nil")
+	}
+
+	if _, exists := spec.Responses[http.StatusRequestEntityTooLarge]; rb.bodyLimitResponse != nil && !exists {
+		// Clone so a responses map shared between specs is not modified
+		spec.Responses = maps.Clone(spec.Responses)
+		if spec.Responses == nil {
+			spec.Responses = make(map[int]ResponseSpec, 1)
+		}
+
+		spec.Responses[http.StatusRequestEntityTooLarge] = rb.bodyLimitResponse(cmp.Or(spec.MaxBodyBytes, rb.defaultMaxBodyBytes))
+	}
+
+	return spec, parameters, nil
+}
+
+// Router returns the underlying chi.Router.
+//
+//nolint:ireturn // we want to return the specific type chi.Router
+func (rb *RouteBuilder) Router() chi.Router {
+	return rb.router
+}
+
+// add adds a new route to the router and collects metadata.
+func (rb *RouteBuilder) add(path string, spec RouteSpec) error {
+	spec, parameters, err := rb.prepare(path, spec)
+	if err != nil {
+		return err
 	}
 
 	// Collect request metadata
 	var requestInfo *generate.RequestInfo
 
 	if spec.RequestType != nil {
-		if spec.RequestType.Type == nil {
-			return errors.New("request type is nil")
-		}
-
 		requestInfo = &generate.RequestInfo{
 			TypeValue: spec.RequestType.Type,
 			Examples:  spec.RequestType.Examples,
+			Required:  spec.RequestType.Required == nil || *spec.RequestType.Required,
+			Patch:     spec.RequestType.Patch,
 		}
 	}
 
@@ -242,11 +411,20 @@ func (rb *RouteBuilder) add(path string, spec RouteSpec) error {
 	responses := make(map[int]generate.ResponseInfo)
 
 	for statusCode, respSpec := range spec.Responses {
+		contentType := respSpec.ContentType
+		if contentType == "" && len(respSpec.Variants) == 0 {
+			contentType = generate.ContentTypeJSON
+		}
+
 		responseInfo := generate.ResponseInfo{
 			StatusCode:  statusCode,
 			TypeValue:   respSpec.Type,
 			Description: respSpec.Description,
 			Examples:    respSpec.Examples,
+			ContentType: contentType,
+			Binary:      respSpec.Binary,
+			Headers:     generateHeaders(respSpec.Headers),
+			Variants:    generateContentVariants(respSpec.Variants),
 		}
 
 		responses[statusCode] = responseInfo
@@ -264,14 +442,27 @@ func (rb *RouteBuilder) add(path string, spec RouteSpec) error {
 		Request:     requestInfo,
 		Parameters:  parameters,
 		Responses:   responses,
+		Security:    spec.Security,
+		Public:      spec.Public,
 	}); err != nil {
 		return fmt.Errorf("failed to register route: %w", err)
 	}
 
 	// Everything is good here. Register the route.
 
+	// Compose route middlewares so they run in registration order
+	var handler http.Handler = spec.Handler
+	for _, middleware := range slices.Backward(spec.Middlewares) {
+		handler = middleware(handler)
+	}
+
+	// Expose the route's body limit to the route middlewares and the handler
+	if spec.MaxBodyBytes > 0 {
+		handler = withMaxBodyBytes(spec.MaxBodyBytes, handler)
+	}
+
 	// Register route with router
-	rb.router.Method(spec.method, spec.fullPath, spec.Handler)
+	rb.router.Method(spec.method, spec.fullPath, handler)
 	rb.operationIDs[spec.OperationID] = struct{}{}
 
 	rb.l.Info("registered route", slog.String("method", spec.method), slog.String("path", spec.fullPath), slog.String("operationID", spec.OperationID))
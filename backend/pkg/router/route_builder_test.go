@@ -0,0 +1,589 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"http-mqtt-boilerplate/backend/pkg/generate"
+)
+
+func TestRouteSpecMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{})
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	var calls []string
+
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := func(name string) http.HandlerFunc {
+		return func(http.ResponseWriter, *http.Request) {
+			calls = append(calls, name)
+		}
+	}
+
+	rb.Use(record("group"))
+
+	if err := rb.Post("/login", RouteSpec{
+		OperationID: "login",
+		Summary:     "Log in",
+		Description: "Log in with credentials",
+		Group:       "Auth",
+		Handler:     handler("login"),
+		Middlewares: []func(http.Handler) http.Handler{record("first"), record("second")},
+	}); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	if err := rb.Get("/status", RouteSpec{
+		OperationID: "status",
+		Summary:     "Status",
+		Description: "Get the status",
+		Group:       "Auth",
+		Handler:     handler("status"),
+	}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	tests := []struct {
+		method    string
+		path      string
+		wantCalls []string
+	}{
+		{method: http.MethodPost, path: "/login", wantCalls: []string{"group", "first", "second", "login"}},
+		{method: http.MethodGet, path: "/status", wantCalls: []string{"group", "status"}},
+	}
+
+	for _, tt := range tests {
+		calls = nil
+
+		rb.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+		if !slices.Equal(calls, tt.wantCalls) {
+			t.Errorf("%s %s calls = %v, want %v", tt.method, tt.path, calls, tt.wantCalls)
+		}
+	}
+}
+
+func TestRouteSpecNilMiddleware(t *testing.T) {
+	t.Parallel()
+
+	rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{})
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	err = rb.Get("/status", RouteSpec{
+		OperationID: "status",
+		Summary:     "Status",
+		Description: "Get the status",
+		Group:       "Auth",
+		Handler:     func(http.ResponseWriter, *http.Request) {},
+		Middlewares: []func(http.Handler) http.Handler{nil},
+	})
+	if err == nil {
+		t.Error("Get() with nil middleware expected error, got nil")
+	}
+}
+
+// recordingCollector records the registered routes.
+type recordingCollector struct {
+	generate.NoopCollector
+
+	routes map[string]*generate.RouteInfo
+}
+
+func (c *recordingCollector) RegisterRoute(route *generate.RouteInfo) error {
+	c.routes[route.OperationID] = route
+
+	return nil
+}
+
+func TestRequestBodyRequired(t *testing.T) {
+	t.Parallel()
+
+	collector := &recordingCollector{routes: make(map[string]*generate.RouteInfo)}
+
+	rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), collector)
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	type patchBody struct{}
+
+	tests := []struct {
+		operationID  string
+		required     *bool
+		wantRequired bool
+	}{
+		{operationID: "patchDefault", required: nil, wantRequired: true},
+		{operationID: "patchRequired", required: new(true), wantRequired: true},
+		{operationID: "patchOptional", required: new(false), wantRequired: false},
+	}
+
+	for _, tt := range tests {
+		if err := rb.Patch("/"+tt.operationID, RouteSpec{
+			OperationID: tt.operationID,
+			Summary:     "Patch",
+			Description: "Patch the resource",
+			Group:       "Items",
+			Handler:     func(http.ResponseWriter, *http.Request) {},
+			RequestType: &RequestBodySpec{Type: patchBody{}, Required: tt.required},
+		}); err != nil {
+			t.Fatalf("Patch(%s) error = %v", tt.operationID, err)
+		}
+
+		if got := collector.routes[tt.operationID].Request.Required; got != tt.wantRequired {
+			t.Errorf("%s: Request.Required = %v, want %v", tt.operationID, got, tt.wantRequired)
+		}
+	}
+}
+
+func TestPatchRoute(t *testing.T) {
+	t.Parallel()
+
+	collector := &recordingCollector{routes: make(map[string]*generate.RouteInfo)}
+
+	rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), collector)
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	type itemPatch struct{}
+
+	rb.MustPatch("/items/{itemID}", RouteSpec{
+		OperationID: "patchItem",
+		Summary:     "Patch item",
+		Description: "Partially update an item",
+		Group:       "Items",
+		Handler:     func(http.ResponseWriter, *http.Request) {},
+		RequestType: &RequestBodySpec{Type: itemPatch{}},
+		Parameters: map[string]ParameterSpec{
+			"itemID": {In: ParameterInPath, Description: "Item ID", Type: "", Required: true},
+		},
+	})
+
+	route, ok := collector.routes["patchItem"]
+	if !ok {
+		t.Fatal("patchItem route was not registered with the collector")
+	}
+
+	if route.Method != http.MethodPatch {
+		t.Errorf("route Method = %s, want %s", route.Method, http.MethodPatch)
+	}
+
+	tests := []struct {
+		method     string
+		wantStatus int
+	}{
+		{method: http.MethodPatch, wantStatus: http.StatusOK},
+		{method: http.MethodPut, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		rb.Router().ServeHTTP(rec, httptest.NewRequest(tt.method, "/items/1", nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s /items/1 status = %d, want %d", tt.method, rec.Code, tt.wantStatus)
+		}
+	}
+}
+
+func TestCookieParameter(t *testing.T) {
+	t.Parallel()
+
+	collector := &recordingCollector{routes: make(map[string]*generate.RouteInfo)}
+
+	rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), collector)
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	if err := rb.Get("/profile", RouteSpec{
+		OperationID: "getProfile",
+		Summary:     "Get profile",
+		Description: "Get the profile of the session user",
+		Group:       "Users",
+		Handler:     func(http.ResponseWriter, *http.Request) {},
+		Parameters: map[string]ParameterSpec{
+			"session": {In: ParameterInCookie, Description: "Session token", Type: "", Required: true},
+		},
+	}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	params := collector.routes["getProfile"].Parameters
+	if len(params) != 1 || params[0].Name != "session" || params[0].In != string(ParameterInCookie) {
+		t.Errorf("Parameters = %+v, want the session cookie", params)
+	}
+}
+
+func TestStrictSummaries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []RouteBuilderOption
+		summary  string
+		errorMsg string
+	}{
+		{name: "short", opts: []RouteBuilderOption{WithStrictSummaries(20)}, summary: "Get the status"},
+		{name: "at the limit", opts: []RouteBuilderOption{WithStrictSummaries(14)}, summary: "Get the status"},
+		{name: "too long", opts: []RouteBuilderOption{WithStrictSummaries(10)}, summary: "Get the status", errorMsg: "14 characters long, the maximum is 10"},
+		{name: "default limit", opts: []RouteBuilderOption{WithStrictSummaries(0)}, summary: strings.Repeat("a", DefaultSummaryMaxLength+1), errorMsg: "the maximum is 80"},
+		{name: "multi line", opts: []RouteBuilderOption{WithStrictSummaries(0)}, summary: "Get the status.\nAlso checks the database.", errorMsg: "single line"},
+		{name: "whitespace only", opts: []RouteBuilderOption{WithStrictSummaries(0)}, summary: "  ", errorMsg: "Summary required"},
+		{name: "disabled", opts: nil, summary: "Get the status.\n" + strings.Repeat("a", 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewRouteBuilder() error = %v", err)
+			}
+
+			// Sub-routers inherit the strict mode
+			rb.Route("/api", func(rb *RouteBuilder) {
+				err = rb.Get("/status", RouteSpec{
+					OperationID: "status",
+					Summary:     tt.summary,
+					Description: "Get the status",
+					Group:       "Core",
+					Handler:     func(http.ResponseWriter, *http.Request) {},
+				})
+			})
+
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("Get() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Get() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestBodyLimitResponse(t *testing.T) {
+	t.Parallel()
+
+	collector := &recordingCollector{routes: make(map[string]*generate.RouteInfo)}
+
+	rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), collector,
+		WithBodyLimitResponse(1024, func(maxBodyBytes int64) ResponseSpec {
+			return ResponseSpec{Description: fmt.Sprintf("max %d", maxBodyBytes)}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewRouteBuilder() error = %v", err)
+	}
+
+	declared := map[int]ResponseSpec{http.StatusRequestEntityTooLarge: {Description: "declared"}}
+
+	tests := []struct {
+		operationID  string
+		maxBodyBytes int64
+		responses    map[int]ResponseSpec
+		wantDesc     string
+	}{
+		{operationID: "defaultLimit", wantDesc: "max 1024"},
+		{operationID: "routeLimit", maxBodyBytes: 16, wantDesc: "max 16"},
+		{operationID: "declaredResponse", maxBodyBytes: 16, responses: declared, wantDesc: "declared"},
+	}
+
+	for _, tt := range tests {
+		// Sub-routers inherit the option
+		rb.Route("/api", func(rb *RouteBuilder) {
+			err = rb.Post("/"+tt.operationID, RouteSpec{
+				OperationID:  tt.operationID,
+				Summary:      "Ingest",
+				Description:  "Ingest a payload",
+				Group:        "Items",
+				Handler:      func(http.ResponseWriter, *http.Request) {},
+				MaxBodyBytes: tt.maxBodyBytes,
+				Responses:    tt.responses,
+			})
+		})
+		if err != nil {
+			t.Fatalf("Post(%s) error = %v", tt.operationID, err)
+		}
+
+		if got := collector.routes[tt.operationID].Responses[http.StatusRequestEntityTooLarge].Description; got != tt.wantDesc {
+			t.Errorf("%s: 413 description = %q, want %q", tt.operationID, got, tt.wantDesc)
+		}
+	}
+}
+
+func TestPathValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		errorMsg string
+	}{
+		{name: "root", path: "/"},
+		{name: "parameter", path: "/teams/{teamID}"},
+		{name: "parameter with pattern", path: "/teams/{teamID:[0-9]+}"},
+		{name: "double slash", path: "//api", errorMsg: "double slashes"},
+		{name: "missing leading slash", path: "api", errorMsg: "missing leading slash"},
+		{name: "trailing slash", path: "/api/", errorMsg: "trailing slash"},
+		{name: "unbalanced open brace", path: "/teams/{teamID", errorMsg: "unbalanced '{'"},
+		{name: "unbalanced close brace", path: "/teams/teamID}", errorMsg: "unbalanced '}'"},
+		{name: "nested braces", path: "/teams/{{teamID}}", errorMsg: "nested '{'"},
+		{name: "invalid parameter name", path: "/teams/{1team}", errorMsg: "invalid parameter name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{})
+			if err != nil {
+				t.Fatalf("NewRouteBuilder() error = %v", err)
+			}
+
+			spec := RouteSpec{
+				OperationID: "getTeam",
+				Summary:     "Get team",
+				Description: "Get a team",
+				Group:       "Teams",
+				Handler:     func(http.ResponseWriter, *http.Request) {},
+			}
+
+			err = validatePath(tt.path)
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("validatePath(%q) error = %v, want nil", tt.path, err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Fatalf("validatePath(%q) error = %v, want error containing %q", tt.path, err, tt.errorMsg)
+			}
+
+			// Registering a route with the path fails with the same error
+			if err := rb.Get(tt.path, spec); err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Get(%q) error = %v, want error containing %q", tt.path, err, tt.errorMsg)
+			}
+
+			// Using the path as a route prefix panics with the same error
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), tt.errorMsg) {
+					t.Errorf("Route(%q) panic = %v, want panic containing %q", tt.path, r, tt.errorMsg)
+				}
+			}()
+
+			rb.Route(tt.path, func(*RouteBuilder) {})
+		})
+	}
+}
+
+func TestPathParameterCrossCheck(t *testing.T) {
+	t.Parallel()
+
+	teamID := ParameterSpec{In: ParameterInPath, Description: "Team ID", Required: true, Type: new(string)}
+
+	tests := []struct {
+		name       string
+		prefix     string
+		path       string
+		parameters map[string]ParameterSpec
+		errorMsg   string
+	}{
+		{
+			name:       "declared path parameter",
+			path:       "/{teamID}",
+			parameters: map[string]ParameterSpec{"teamID": teamID},
+		},
+		{
+			name:       "path parameter from the route prefix",
+			prefix:     "/teams/{teamID}",
+			path:       "/members",
+			parameters: map[string]ParameterSpec{"teamID": teamID},
+		},
+		{
+			name:     "undeclared path parameter",
+			path:     "/{teamID}",
+			errorMsg: "path parameter teamID appears in path GET /{teamID} but is not declared in Parameters",
+		},
+		{
+			name:       "declared path parameter absent from path",
+			path:       "/",
+			parameters: map[string]ParameterSpec{"teamID": teamID},
+			errorMsg:   "path parameter teamID is declared in Parameters but does not appear in path GET /",
+		},
+		{
+			name:       "repeated path parameter",
+			path:       "/{teamID}/copy/{teamID}",
+			parameters: map[string]ParameterSpec{"teamID": teamID},
+			errorMsg:   "path parameter teamID appears more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), &generate.NoopCollector{})
+			if err != nil {
+				t.Fatalf("NewRouteBuilder() error = %v", err)
+			}
+
+			register := func(rb *RouteBuilder) {
+				err = rb.Get(tt.path, RouteSpec{
+					OperationID: "getTeam",
+					Summary:     "Get team",
+					Description: "Get a team",
+					Group:       "Teams",
+					Handler:     func(http.ResponseWriter, *http.Request) {},
+					Parameters:  tt.parameters,
+				})
+			}
+
+			if tt.prefix != "" {
+				rb.Route(tt.prefix, register)
+			} else {
+				register(rb)
+			}
+
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("Get() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Get() error = %v, want error containing %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestRegisterAll(t *testing.T) {
+	t.Parallel()
+
+	spec := func(method, path, operationID string) RouteSpec {
+		return RouteSpec{
+			Method:      method,
+			Path:        path,
+			OperationID: operationID,
+			Summary:     "Item operation",
+			Description: "Operate on items",
+			Group:       "Items",
+			Handler:     func(http.ResponseWriter, *http.Request) {},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		specs    []RouteSpec
+		errorMsg string
+	}{
+		{
+			name:  "valid batch",
+			specs: []RouteSpec{spec(http.MethodGet, "/items", "listItems"), spec(http.MethodPost, "/items", "createItem")},
+		},
+		{
+			name:     "duplicate operation ID",
+			specs:    []RouteSpec{spec(http.MethodGet, "/items", "listItems"), spec(http.MethodPost, "/items", "listItems")},
+			errorMsg: "operation ID listItems is used more than once in the batch",
+		},
+		{
+			name:     "unsupported method",
+			specs:    []RouteSpec{spec(http.MethodGet, "/items", "listItems"), spec(http.MethodHead, "/items", "headItems")},
+			errorMsg: "unsupported method",
+		},
+		{
+			name:     "missing path",
+			specs:    []RouteSpec{spec(http.MethodGet, "", "listItems")},
+			errorMsg: "path is required",
+		},
+		{
+			name: "invalid later spec",
+			specs: []RouteSpec{
+				spec(http.MethodGet, "/items", "listItems"),
+				func() RouteSpec {
+					s := spec(http.MethodPost, "/items", "createItem")
+					s.Description = ""
+
+					return s
+				}(),
+			},
+			errorMsg: "field Description required",
+		},
+		{
+			name:     "undocumented path parameter",
+			specs:    []RouteSpec{spec(http.MethodGet, "/items", "listItems"), spec(http.MethodGet, "/items/{itemID}", "getItem")},
+			errorMsg: "itemID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector := &recordingCollector{routes: make(map[string]*generate.RouteInfo)}
+
+			rb, err := NewRouteBuilder(slog.New(slog.NewTextHandler(io.Discard, nil)), collector)
+			if err != nil {
+				t.Fatalf("NewRouteBuilder() error = %v", err)
+			}
+
+			err = rb.RegisterAll(tt.specs)
+
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("RegisterAll() error = %v, want error containing %q", err, tt.errorMsg)
+				}
+
+				// An invalid batch registers nothing
+				if len(collector.routes) != 0 {
+					t.Errorf("registered %d routes from an invalid batch, want 0", len(collector.routes))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RegisterAll() error = %v", err)
+			}
+
+			for _, spec := range tt.specs {
+				route, ok := collector.routes[spec.OperationID]
+				if !ok || route.Method != spec.Method || route.Path != spec.Path {
+					t.Errorf("route %s = %+v, want %s %s", spec.OperationID, route, spec.Method, spec.Path)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -34,27 +35,127 @@ func FromJSON[T any](data []byte) (T, error) {
 func FromJSONStream[T any](r io.Reader) (T, error) {
 	var result T
 
+	err := FromJSONStreamInto(r, &result)
+
+	return result, err
+}
+
+// FromJSONStreamInto decodes JSON from io.Reader into v, which must be a pointer.
+// Like [FromJSONStream], unknown fields and extra data after the JSON value are rejected.
+// Use it when the type is only known at runtime.
+func FromJSONStreamInto(r io.Reader, v any) error {
 	decoder := json.NewDecoder(r)
 	decoder.DisallowUnknownFields()
 
-	err := decoder.Decode(&result)
+	return decodeSingleJSONValue(decoder, v)
+}
+
+// FromJSONLenient decodes JSON from byte slice, ignoring unknown fields (wrapper around streaming version).
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func FromJSONLenient[T any](data []byte) (T, error) {
+	var result T
+	if len(data) == 0 {
+		return result, nil
+	}
+
+	return FromJSONStreamLenient[T](bytes.NewReader(data))
+}
+
+// FromJSONStreamLenient decodes JSON from io.Reader, ignoring unknown fields.
+// Use it only for forward-compatible ingestion (e.g., webhooks) where producers may add fields:
+// typos and removed fields are silently ignored instead of rejected, so prefer [FromJSONStream] for our own APIs.
+// Extra data after the JSON value is still rejected.
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func FromJSONStreamLenient[T any](r io.Reader) (T, error) {
+	var result T
+
+	err := decodeSingleJSONValue(json.NewDecoder(r), &result)
+
+	return result, err
+}
+
+// FromJSONUseNumber decodes JSON from byte slice like [FromJSON], but numbers decoded into interface values
+// are kept as [json.Number] instead of float64. This preserves integers beyond 2^53 (e.g., 64-bit IDs).
+//
+//nolint:ireturn // Generic functions must return type parameter T
+func FromJSONUseNumber[T any](data []byte) (T, error) {
+	var result T
+	if len(data) == 0 {
+		return result, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+
+	err := decodeSingleJSONValue(decoder, &result)
+
+	return result, err
+}
+
+// decodeSingleJSONValue decodes a single JSON value into v and rejects any data after it.
+func decodeSingleJSONValue(decoder *json.Decoder, v any) error {
+	err := decoder.Decode(v)
 	if err != nil {
-		return result, err
+		return err
 	}
 
+	return checkNoTrailingJSON(decoder)
+}
+
+// checkNoTrailingJSON rejects any data left in the decoder after the top-level JSON value.
+func checkNoTrailingJSON(decoder *json.Decoder) error {
 	// Attempt a second decode to detect extra top-level JSON values
-	err = decoder.Decode(new(any))
+	err := decoder.Decode(new(any))
 	switch err {
 	case io.EOF:
 		// EOF is expected - no extra data
-		return result, nil
+		return nil
 	case nil:
 		// No error means extra data was successfully decoded
-		return result, &ExtraDataAfterJSONError{}
+		return &ExtraDataAfterJSONError{}
 	}
 
 	// Any other error should be propagated
-	return result, err
+	return err
+}
+
+// DecodeJSONArray decodes a JSON array from io.Reader one element at a time, calling fn for each element.
+// Unlike [FromJSONStream] the whole array is never held in memory, which suits bulk ingestion.
+// Like [FromJSONStream], unknown fields and extra data after the array are rejected.
+// Decoding stops at the first error returned by fn, which is returned as is.
+func DecodeJSONArray[T any](r io.Reader, fn func(T) error) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", token)
+	}
+
+	for decoder.More() {
+		var element T
+		if err := decoder.Decode(&element); err != nil {
+			return err
+		}
+
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	return checkNoTrailingJSON(decoder)
 }
 
 // MustFromJSON decodes JSON from byte slice (wrapper around streaming version).
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testJSONItem struct {
+	ID int `json:"id"`
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	t.Parallel()
+
+	many := make([]string, 1000)
+	for i := range many {
+		many[i] = fmt.Sprintf(`{"id":%d}`, i)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantIDs int
+		wantErr bool
+	}{
+		{name: "empty array", input: `[]`},
+		{name: "whitespace", input: " [ {\"id\":0} , {\"id\":1} ] \n", wantIDs: 2},
+		{name: "many elements", input: "[" + strings.Join(many, ",") + "]", wantIDs: len(many)},
+		{name: "not an array", input: `{"id":1}`, wantErr: true},
+		{name: "empty input", input: ``, wantErr: true},
+		{name: "unterminated array", input: `[{"id":0}`, wantErr: true},
+		{name: "malformed element", input: `[{"id":0},{"id":]`, wantErr: true},
+		{name: "wrong element type", input: `[{"id":"1"}]`, wantErr: true},
+		{name: "unknown field", input: `[{"id":0,"name":"x"}]`, wantErr: true},
+		{name: "extra data", input: `[{"id":0}] [{"id":1}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got int
+
+			err := DecodeJSONArray(strings.NewReader(tt.input), func(item testJSONItem) error {
+				// Elements are passed in order
+				if item.ID != got {
+					return fmt.Errorf("element %d has id %d", got, item.ID)
+				}
+
+				got++
+
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeJSONArray() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.wantIDs {
+				t.Errorf("DecodeJSONArray() decoded %d elements, want %d", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONArrayStopsOnCallbackError(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+
+	var calls int
+
+	err := DecodeJSONArray(strings.NewReader(`[{"id":1},{"id":2},{"id":3}]`), func(testJSONItem) error {
+		calls++
+
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("DecodeJSONArray() error = %v, want %v", err, errStop)
+	}
+
+	if calls != 1 {
+		t.Errorf("callback called %d times, want 1", calls)
+	}
+}
+
+func TestFromJSONLenient(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"id":1,"addedLater":"value"}`)
+
+	if _, err := FromJSON[testJSONItem](data); err == nil {
+		t.Error("FromJSON() with unknown field expected error")
+	}
+
+	got, err := FromJSONLenient[testJSONItem](data)
+	if err != nil {
+		t.Fatalf("FromJSONLenient() error = %v", err)
+	}
+
+	if got.ID != 1 {
+		t.Errorf("FromJSONLenient() = %+v, want id 1", got)
+	}
+
+	var extraDataErr *ExtraDataAfterJSONError
+	if _, err := FromJSONLenient[testJSONItem]([]byte(`{"id":1} {"id":2}`)); !errors.As(err, &extraDataErr) {
+		t.Errorf("FromJSONLenient() with extra data error = %v, want ExtraDataAfterJSONError", err)
+	}
+}
+
+func TestFromJSONUseNumber(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"id":1234567890123456789}`)
+
+	// float64 cannot represent the 19-digit integer exactly
+	rounded, err := FromJSON[map[string]any](data)
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	if got := fmt.Sprint(int64(rounded["id"].(float64))); got == "1234567890123456789" {
+		t.Errorf("FromJSON() id = %s, expected float64 rounding", got)
+	}
+
+	exact, err := FromJSONUseNumber[map[string]any](data)
+	if err != nil {
+		t.Fatalf("FromJSONUseNumber() error = %v", err)
+	}
+
+	if got, ok := exact["id"].(json.Number); !ok || got.String() != "1234567890123456789" {
+		t.Errorf("FromJSONUseNumber() id = %v (%T), want json.Number 1234567890123456789", exact["id"], exact["id"])
+	}
+
+	if _, err := FromJSONUseNumber[map[string]any]([]byte(`{"id":1} {}`)); err == nil {
+		t.Error("FromJSONUseNumber() with extra data expected error")
+	}
+
+	// Empty input decodes to the zero value like FromJSON
+	if empty, err := FromJSONUseNumber[map[string]any](nil); err != nil || empty != nil {
+		t.Errorf("FromJSONUseNumber(nil) = %v, %v, want nil, nil", empty, err)
+	}
+}
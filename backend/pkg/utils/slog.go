@@ -3,8 +3,14 @@ package utils
 import (
 	"bytes"
 	"log/slog"
+	"path"
+	"strconv"
+	"strings"
 )
 
+// RedactedValue replaces the value of redacted log attributes.
+const RedactedValue = "[REDACTED]"
+
 // logWriter is a small io.Writer that writes to a slog.Logger.
 type logWriter struct {
 	logger *slog.Logger
@@ -31,9 +37,19 @@ func ErrAttr(err error) slog.Attr {
 	return slog.Any("error", err)
 }
 
+// SlogReplacer formats times and durations as strings, and shortens source locations to pkg/file.go:line.
 func SlogReplacer(groups []string, a slog.Attr) slog.Attr {
 	timeFormat := "2006-01-02 15:04:05"
 
+	// Source is only added with slog.HandlerOptions.AddSource
+	if a.Key == slog.SourceKey && len(groups) == 0 {
+		if source, ok := a.Value.Any().(*slog.Source); ok && source != nil {
+			a.Value = slog.StringValue(shortSourcePath(source.File) + ":" + strconv.Itoa(source.Line))
+		}
+
+		return a
+	}
+
 	//nolint:exhaustive // Only formatting specific types; other types pass through unchanged
 	switch a.Value.Kind() {
 	case slog.KindTime:
@@ -44,3 +60,37 @@ func SlogReplacer(groups []string, a slog.Attr) slog.Attr {
 
 	return a
 }
+
+// NewSlogRedactor returns a ReplaceAttr function that masks the value of every attribute whose key matches
+// one of keys, case-insensitively and at any group depth, then passes the attribute to next (e.g., [SlogReplacer]).
+// A nil next returns the attribute as is.
+func NewSlogRedactor(keys []string, next func(groups []string, a slog.Attr) slog.Attr) func(groups []string, a slog.Attr) slog.Attr {
+	redacted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		redacted[strings.ToLower(key)] = struct{}{}
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if _, ok := redacted[strings.ToLower(a.Key)]; ok {
+			a.Value = slog.StringValue(RedactedValue)
+		}
+
+		if next == nil {
+			return a
+		}
+
+		return next(groups, a)
+	}
+}
+
+// shortSourcePath trims a source file path to its package directory and file name (e.g., api/api.go).
+func shortSourcePath(file string) string {
+	dir, name := path.Split(file)
+
+	pkg := path.Base(dir)
+	if pkg == "." || pkg == "/" {
+		return name
+	}
+
+	return pkg + "/" + name
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogReplacerSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{name: "long path", file: "/home/user/go/src/http-mqtt-boilerplate/backend/internal/api/handlers_team.go", want: "api/handlers_team.go:42"},
+		{name: "file only", file: "main.go", want: "main.go:42"},
+		{name: "root file", file: "/main.go", want: "main.go:42"},
+	}
+
+	for _, tt := range tests {
+		attr := SlogReplacer(nil, slog.Any(slog.SourceKey, &slog.Source{File: tt.file, Line: 42}))
+
+		if got := attr.Value.String(); got != tt.want {
+			t.Errorf("%s: source = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlogReplacerTimeAndDuration(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if got := SlogReplacer(nil, slog.Time(slog.TimeKey, ts)).Value.String(); got != "2024-01-02 03:04:05" {
+		t.Errorf("time = %q, want %q", got, "2024-01-02 03:04:05")
+	}
+
+	if got := SlogReplacer(nil, slog.Duration("elapsed", 1500*time.Millisecond)).Value.String(); got != "1.5s" {
+		t.Errorf("duration = %q, want %q", got, "1.5s")
+	}
+
+	// A grouped attribute named "source" is user data, not the record source
+	attr := SlogReplacer([]string{"req"}, slog.String(slog.SourceKey, "client"))
+	if got := attr.Value.String(); got != "client" {
+		t.Errorf("grouped source = %q, want %q", got, "client")
+	}
+}
+
+func TestSlogRedactor(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: NewSlogRedactor([]string{"password", "Authorization"}, SlogReplacer),
+	}))
+
+	logger.Info("login",
+		slog.String("user", "alice"),
+		slog.String("PASSWORD", "hunter2"),
+		slog.Group("request", slog.String("authorization", "Bearer abc"), slog.Duration("elapsed", 1500*time.Millisecond)),
+	)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log record: %v", err)
+	}
+
+	if got := record["PASSWORD"]; got != RedactedValue {
+		t.Errorf("PASSWORD = %v, want %q", got, RedactedValue)
+	}
+
+	if got := record["user"]; got != "alice" {
+		t.Errorf("user = %v, want %q", got, "alice")
+	}
+
+	request, _ := record["request"].(map[string]any)
+	if got := request["authorization"]; got != RedactedValue {
+		t.Errorf("request.authorization = %v, want %q", got, RedactedValue)
+	}
+
+	// The redactor composes with the replacer
+	if got := request["elapsed"]; got != "1.5s" {
+		t.Errorf("request.elapsed = %v, want %q", got, "1.5s")
+	}
+
+	if strings.Contains(buf.String(), "hunter2") || strings.Contains(buf.String(), "Bearer abc") {
+		t.Errorf("log output leaks a secret: %s", buf.String())
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"net/url"
+	"slices"
+	"strings"
 )
 
 // URL wraps net/url.URL and marshals as a string instead of an object.
@@ -14,16 +16,27 @@ type URL struct {
 }
 
 // NewURL creates a new URL from a string.
+// Only absolute http and https URLs are accepted, use [NewURLWithSchemes] for other schemes.
 func NewURL(s string) (URL, error) {
+	return NewURLWithSchemes(s, "http", "https")
+}
+
+// NewURLWithSchemes creates a new URL from a string, accepting only absolute URLs with one of the allowed schemes.
+// This rejects schemes such as javascript: or file: in API-facing URLs.
+func NewURLWithSchemes(s string, allowed ...string) (URL, error) {
 	u, err := url.Parse(s)
 	if err != nil {
 		return URL{}, err
 	}
 
+	if !slices.ContainsFunc(allowed, func(scheme string) bool { return strings.EqualFold(scheme, u.Scheme) }) {
+		return URL{}, fmt.Errorf("URL scheme %q is not allowed (allowed: %s)", u.Scheme, strings.Join(allowed, ", "))
+	}
+
 	return URL{URL: u}, nil
 }
 
-// MustNewURL creates a new URL from a string and panics on error.
+// MustNewURL creates a new URL from a string and panics on error, including disallowed schemes.
 func MustNewURL(s string) URL {
 	u, err := NewURL(s)
 	if err != nil {
@@ -43,7 +56,7 @@ func (u URL) MarshalJSON() ([]byte, error) {
 	return ToJSON(u.String())
 }
 
-// UnmarshalJSON unmarshals a JSON string into a URL.
+// UnmarshalJSON unmarshals a JSON string into a URL, with the same scheme restrictions as [NewURL].
 func (u *URL) UnmarshalJSON(data []byte) error {
 	// Handle JSON null explicitly
 	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
@@ -63,12 +76,12 @@ func (u *URL) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	parsed, err := url.Parse(s)
+	parsed, err := NewURL(s)
 	if err != nil {
 		return fmt.Errorf("invalid URL: %w", err)
 	}
 
-	u.URL = parsed
+	u.URL = parsed.URL
 
 	return nil
 }
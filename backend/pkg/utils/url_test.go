@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNewURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "https", input: "https://example.com/path?q=1"},
+		{name: "http", input: "http://localhost:8080/user"},
+		{name: "uppercase scheme", input: "HTTPS://example.com"},
+		{name: "javascript", input: "javascript:alert(1)", wantErr: true},
+		{name: "file", input: "file:///etc/passwd", wantErr: true},
+		{name: "relative", input: "/user/1", wantErr: true},
+		{name: "malformed", input: "http://[::1", wantErr: true},
+		{name: "malformed escape", input: "https://example.com/%zz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := NewURL(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("NewURL(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewURLWithSchemes(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewURLWithSchemes("mqtts://broker.example.com:8883", "mqtt", "mqtts"); err != nil {
+		t.Errorf("NewURLWithSchemes() with allowed scheme error = %v", err)
+	}
+
+	if _, err := NewURLWithSchemes("https://example.com", "mqtt", "mqtts"); err == nil {
+		t.Error("NewURLWithSchemes() with disallowed scheme expected error")
+	}
+}
+
+func TestMustNewURLPanicsOnDisallowedScheme(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustNewURL() did not panic on a disallowed scheme")
+		}
+	}()
+
+	MustNewURL("javascript:alert(1)")
+}
+
+func TestURLUnmarshalJSONRejectsDisallowedScheme(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		URL URL `json:"url"`
+	}
+
+	if _, err := FromJSON[payload]([]byte(`{"url":"javascript:alert(1)"}`)); err == nil {
+		t.Error("FromJSON() with javascript URL expected error")
+	}
+
+	got, err := FromJSON[payload]([]byte(`{"url":"https://example.com"}`))
+	if err != nil || got.URL.String() != "https://example.com" {
+		t.Errorf("FromJSON() = %v, %v, want https://example.com", got.URL, err)
+	}
+}
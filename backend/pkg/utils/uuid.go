@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UUID is a string holding a UUID in its canonical form (lowercase, hyphenated).
+// It is documented as a string with the uuid format.
+type UUID string
+
+// NewUUID validates a UUID string and returns it in canonical form.
+func NewUUID(s string) (UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid UUID %q: %w", s, err)
+	}
+
+	return UUID(u.String()), nil
+}
+
+// MustNewUUID creates a new UUID from a string and panics on error.
+func MustNewUUID(s string) UUID {
+	u, err := NewUUID(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// NewRandomUUID generates a new random (version 4) UUID.
+func NewRandomUUID() UUID {
+	return UUID(uuid.NewString())
+}
+
+// UnmarshalJSON unmarshals a JSON string into a UUID, rejecting invalid UUIDs.
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	s, err := FromJSON[string](data)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := NewUUID(s)
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+
+	return nil
+}
+
+// String returns the UUID as a string.
+func (u UUID) String() string {
+	return string(u)
+}
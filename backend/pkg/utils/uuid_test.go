@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNewUUID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    UUID
+		wantErr bool
+	}{
+		{name: "canonical", input: "0190b7c4-8f6e-7c3a-9d2b-4a5e6f708192", want: "0190b7c4-8f6e-7c3a-9d2b-4a5e6f708192"},
+		{name: "uppercase", input: "0190B7C4-8F6E-7C3A-9D2B-4A5E6F708192", want: "0190b7c4-8f6e-7c3a-9d2b-4a5e6f708192"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "too short", input: "0190b7c4-8f6e-7c3a-9d2b", wantErr: true},
+		{name: "invalid characters", input: "0190b7c4-8f6e-7c3a-9d2b-4a5e6f70819z", wantErr: true},
+		{name: "not a UUID", input: "123", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewUUID(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewUUID(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("NewUUID(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUUIDUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		ID UUID `json:"id"`
+	}
+
+	got, err := FromJSON[payload]([]byte(`{"id":"0190B7C4-8F6E-7C3A-9D2B-4A5E6F708192"}`))
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	if got.ID != "0190b7c4-8f6e-7c3a-9d2b-4a5e6f708192" {
+		t.Errorf("ID = %q, want the canonical form", got.ID)
+	}
+
+	for _, input := range []string{`{"id":"not-a-uuid"}`, `{"id":""}`, `{"id":1}`} {
+		if _, err := FromJSON[payload]([]byte(input)); err == nil {
+			t.Errorf("FromJSON(%s) expected error", input)
+		}
+	}
+}
+
+func TestMustNewUUIDPanics(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustNewUUID() did not panic on an invalid UUID")
+		}
+	}()
+
+	MustNewUUID("invalid")
+}
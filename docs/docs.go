@@ -0,0 +1,23 @@
+// Package docs embeds the generated API documentation artifacts, so the servers can expose them at runtime.
+// generate.sh copies the OpenAPI specs into spec/ after generating them, they are not committed so a build
+// never serves a contract older than its own generation run. A rebuild picks up the new contract.
+package docs
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed all:spec
+var artifacts embed.FS
+
+// OpenAPISpec returns the generated OpenAPI spec (YAML) of the given deployment (e.g., local, cloud).
+// The error wraps [fs.ErrNotExist] when generate.sh has not run for the deployment before the build.
+func OpenAPISpec(deployment string) ([]byte, error) {
+	spec, err := artifacts.ReadFile("spec/" + deployment + ".openapi.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI spec of deployment %s: %w", deployment, err)
+	}
+
+	return spec, nil
+}
@@ -46,5 +46,9 @@ npm run fmt
 echo "Running docs build..."
 npm run docs:build
 
+echo "Copying [${component}] OpenAPI spec for embedding..."
+mkdir -p docs/spec
+cp docs/${component}/openapi.yaml docs/spec/${component}.openapi.yaml
+
 echo "Building server binary with the docs..."
 go build -o ${OUTPUT_BINARY} ./backend/cmd/${component}
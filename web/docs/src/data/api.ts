@@ -10,6 +10,17 @@ export type FieldType = {
     itemsType?: FieldType;
     additionalProperties?: FieldType;
     mapKeyType?: FieldType;
+    minLength?: number;
+    maxLength?: number;
+    minimum?: number;
+    maximum?: number;
+    pattern?: string;
+    minItems?: number;
+    maxItems?: number;
+    uniqueItems?: boolean;
+    enum?: (string | number)[];
+    note?: string;
+    fields?: FieldInfo[];
 };
 
 // FieldInfo describes a field in a struct
@@ -19,6 +30,9 @@ export type FieldInfo = {
     typeInfo: FieldType;
     description: string;
     deprecated: string;
+    default?: string | number | boolean;
+    readOnly?: boolean;
+    writeOnly?: boolean;
 };
 
 // EnumValue represents an enum constant with its documentation
@@ -58,22 +72,27 @@ export type TypeInfo = {
     usedByHTTP: boolean;
     usedByMQTT: boolean;
     underlyingType?: FieldType;
+    cyclic: boolean;
+    allowAdditionalProperties?: boolean;
 };
 
 // RequestInfo describes a request body
 export type RequestInfo = {
     type: string;
     description: string;
+    required: boolean;
     examples?: Record<string, string>;
 };
 
 // ParameterInfo describes a route parameter
 export type ParameterInfo = {
     name: string;
-    in: "path" | "query" | "header";
+    in: "path" | "query" | "header" | "cookie";
     type: string;
+    format?: string;
     description: string;
     required: boolean;
+    default?: string | number | boolean;
     deprecated: string;
 };
 
@@ -82,7 +101,26 @@ export type ResponseInfo = {
     statusCode: number;
     type: string;
     description: string;
+    contentType: string;
+    binary: boolean;
     examples?: Record<string, string>;
+    headers?: HeaderInfo[];
+    variants?: ContentVariantInfo[];
+};
+
+// ContentVariantInfo describes one media type of a response with several content types
+export type ContentVariantInfo = {
+    contentType: string;
+    type: string;
+    examples?: Record<string, string>;
+};
+
+// HeaderInfo describes a response header
+export type HeaderInfo = {
+    name: string;
+    type: string;
+    format?: string;
+    description: string;
 };
 
 // HTTP method union type
@@ -100,6 +138,8 @@ export type RouteInfo = {
     request?: RequestInfo;
     parameters?: ParameterInfo[];
     responses: Record<number, ResponseInfo>;
+    security?: string[];
+    public?: boolean;
 };
 
 // MQTTTopicParameter describes a parameter in an MQTT topic pattern
@@ -144,6 +184,14 @@ export type MQTTSubscriptionInfo = {
 export type ServerInfo = {
     url: string;
     description: string;
+    variables?: Record<string, ServerVariable>;
+};
+
+// ServerVariable describes a variable in a templated server URL
+export type ServerVariable = {
+    default: string;
+    enum?: string[];
+    description: string;
 };
 
 // APIInfo contains API metadata
@@ -152,6 +200,19 @@ export type APIInfo = {
     version: string;
     description: string;
     servers: ServerInfo[];
+    tagDescriptions?: Record<string, string>;
+    securitySchemes?: Record<string, SecuritySchemeInfo>;
+    security?: string[];
+};
+
+// SecuritySchemeInfo describes how clients authenticate
+export type SecuritySchemeInfo = {
+    type: "http" | "apiKey";
+    description: string;
+    scheme?: string;
+    bearerFormat?: string;
+    name?: string;
+    in?: "header" | "query" | "cookie";
 };
 
 export type Database = {